@@ -1,6 +1,8 @@
 package account
 
 import (
+	"fmt"
+
 	"github.com/iost-official/go-iost/common"
 	"github.com/iost-official/go-iost/crypto"
 )
@@ -14,6 +16,9 @@ type KeyPair struct {
 
 // NewKeyPair create an account
 func NewKeyPair(seckey []byte, algo crypto.Algorithm) (*KeyPair, error) {
+	if _, ok := algo.SeckeyLen(); !ok {
+		return nil, fmt.Errorf("unsupported algorithm %d", uint8(algo))
+	}
 	if seckey == nil {
 		seckey = algo.GenSeckey()
 	}
@@ -31,6 +31,18 @@ func TestMember(t *testing.T) {
 			So(bytes.Equal(sig2.Pubkey, m.Pubkey), ShouldBeTrue)
 
 		})
+		Convey("unregistered algorithm: ", func() {
+			_, err := NewKeyPair(nil, crypto.Algorithm(100))
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "unsupported algorithm 100")
+		})
+
+		Convey("invalid seckey length: ", func() {
+			_, err := NewKeyPair([]byte("short"), crypto.Ed25519)
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "expected 64, got 5")
+		})
+
 		Convey("sec to pub", func() {
 			m, err := NewKeyPair(Base58Decode("3BZ3HWs2nWucCCvLp7FRFv1K7RR3fAjjEQccf9EJrTv4"), crypto.Secp256k1)
 			So(err, ShouldBeNil)
@@ -12,6 +12,7 @@ import (
 // Constant of limit
 var (
 	MaxBlockGasLimit = int64(800000000)
+	MaxBlockTxsSize  = 8 * 1024 * 1024
 	MaxTxTimeLimit   = 200 * time.Millisecond
 )
 
@@ -20,6 +21,7 @@ type ACCConfig struct {
 	ID        string
 	SecKey    string
 	Algorithm string
+	SignHash  string
 }
 
 // Witness config of the genesis block
@@ -52,12 +54,28 @@ type GenesisConfig struct {
 // DBConfig config of the database
 type DBConfig struct {
 	LdbPath string
+	// WALPath is the directory of the block cache wal, empty means under LdbPath.
+	WALPath string
+	// WALSegmentSize is the size in bytes a wal segment is cut at, 0 means the default 8MB.
+	WALSegmentSize int64
 }
 
 // VMConfig config of the v8vm
 type VMConfig struct {
 	JsPath   string
 	LogLevel string
+	// CostModel tunes the costs charged by the vms, nil means the default costs.
+	// All the nodes of a chain must agree on it.
+	CostModel *CostModelConfig
+}
+
+// CostModelConfig is the cpu cost of the vm operations, 0 keeps the default cost.
+type CostModelConfig struct {
+	StorageRead  int64
+	StorageWrite int64
+	Transfer     int64
+	Call         int64
+	Error        int64
 }
 
 // P2PConfig is the config for p2p network.
@@ -72,6 +90,8 @@ type P2PConfig struct {
 	BlackPID     []string
 	BlackIP      []string
 	AdminPort    string
+	// BlockCompression is the codec of the block messages sent, "" for none or "snappy".
+	BlockCompression string
 }
 
 //RPCConfig is the config for RPC Server.
@@ -120,9 +140,54 @@ type SnapshotConfig struct {
 	FilePath string
 }
 
+// ConsensusConfig is the config of consensus.
+type ConsensusConfig struct {
+	BlockNumPerWitness  int
+	VerifyPipelineDepth int
+	// MaxFutureBlockDrift is how far the time of a received block may be ahead of the local clock, 0 means the default 1s.
+	MaxFutureBlockDrift time.Duration
+	// IncomingBlockBufferSize is the number of received blocks buffered before verifying, 0 means the default.
+	IncomingBlockBufferSize int
+	// VerifyTxWorkers is the number of workers verifying the tx signatures of a block, 0 means the number of cpus.
+	VerifyTxWorkers int
+	// SeenBlockExpiration is how long after its time a single block in the block cache dedupes a re-delivery of it,
+	// 0 means until the block cache prunes it. Linked blocks always dedupe.
+	SeenBlockExpiration time.Duration
+	// GenesisTime is the RFC3339 time at which slot 0 of the witness schedule starts, "" means the unix epoch.
+	// All the nodes of a chain must agree on it.
+	GenesisTime string
+	// BaseFeeHeight is the number of the first block which carries a base fee, and whose txs must pay it.
+	// Unset means the base fee is not activated. All the nodes of a chain must agree on it.
+	BaseFeeHeight *int64
+	// BroadcastQueueSize is the number of block hashes waiting to be broadcast, the oldest is dropped when it is full,
+	// 0 means the default. Own blocks are queued apart and not dropped for the block hashes.
+	BroadcastQueueSize int
+}
+
+// TxPoolConfig is the config of txpool.
+type TxPoolConfig struct {
+	MaxReorgDepth int64
+	MinGasPrice   int64
+	ClearInterval time.Duration
+	TrackBaseFee  bool
+	// MaxBlockListSize caps the number of recent blocks kept for the duplicate tx check.
+	MaxBlockListSize int
+	// MaxTxGasLimit caps the gas limit of the txs sent to this node, 0 means no cap besides the protocol's.
+	MaxTxGasLimit int64
+	// OrderingPolicy is the order of the txs packed by this node: gasprice (default), fifo or gasprice_fifo.
+	OrderingPolicy string
+	// MinConfirmations is the number of blocks a tx in the chain needs to be reported as FoundChain, 0 means any.
+	MinConfirmations int64
+	// MaxPendingTxs caps the number of pending txs, the txs of the lowest gas ratio are evicted for better ones.
+	// 0 means 10000.
+	MaxPendingTxs int
+}
+
 // DebugConfig is the config of debug.
 type DebugConfig struct {
 	ListenAddr string
+	// CallTrace enables tracing the contract calls for debugging, which slows down the calls.
+	CallTrace bool
 }
 
 // VersionConfig contrains netname(mainnet / testnet etc) and protocol info
@@ -133,17 +198,20 @@ type VersionConfig struct {
 
 // Config provide all configuration for the application
 type Config struct {
-	ACC      *ACCConfig
-	Genesis  string
-	VM       *VMConfig
-	DB       *DBConfig
-	Snapshot *SnapshotConfig
-	P2P      *P2PConfig
-	RPC      *RPCConfig
-	Log      *LogConfig
-	Metrics  *MetricsConfig
-	Debug    *DebugConfig
-	Version  *VersionConfig
+	ACC       *ACCConfig
+	Genesis   string
+	VM        *VMConfig
+	DB        *DBConfig
+	Snapshot  *SnapshotConfig
+	Consensus *ConsensusConfig
+	TxPool    *TxPoolConfig
+	P2P       *P2PConfig
+	RPC       *RPCConfig
+	Log       *LogConfig
+	Metrics   *MetricsConfig
+	Debug     *DebugConfig
+	Version   *VersionConfig
+	Fork      *ForkConfig
 }
 
 // LoadYamlAsViper load yaml file as viper object
@@ -4,6 +4,8 @@ import (
 	"encoding/binary"
 	"errors"
 	"math"
+	"regexp"
+	"strings"
 )
 
 var errOverflow = errors.New("overflow error")
@@ -11,6 +13,9 @@ var errAbnormalChar = errors.New("abnormal char in amount")
 var errAmountFormat = errors.New("amount format error")
 var errDivideByZero = errors.New("divide by zero error")
 var errDoubleDot = errors.New("double dot error")
+var errAmountPrecision = errors.New("amount has more decimals than allowed")
+
+var amountRegexp = regexp.MustCompile(`^([0-9]+[.])?[0-9]+$`)
 
 // Fixed implements fixed point number for user of token balance
 type Fixed struct {
@@ -207,6 +212,22 @@ func NewFixed(amount string, decimal int) (*Fixed, error) {
 	return parsePositiveFixed(amount, decimal)
 }
 
+// ParseAmount parses a non-negative amount to the number of the smallest unit with the decimal.
+// Unlike NewFixed, it rejects amounts with more decimals instead of truncating them.
+func ParseAmount(amount string, decimal int) (int64, error) {
+	if decimal < 0 || !amountRegexp.MatchString(amount) {
+		return 0, errAmountFormat
+	}
+	if i := strings.IndexByte(amount, '.'); i >= 0 && len(amount)-i-1 > decimal {
+		return 0, errAmountPrecision
+	}
+	f, err := parsePositiveFixed(amount, decimal)
+	if err != nil {
+		return 0, err
+	}
+	return f.Value, nil
+}
+
 func parsePositiveFixed(amount string, decimal int) (*Fixed, error) {
 	fpn := &Fixed{Value: 0, Decimal: 0}
 	decimalStart := false
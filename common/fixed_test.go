@@ -27,6 +27,29 @@ func TestNewFixed(t *testing.T) {
 	assert.Equal(t, err, errDoubleDot)
 }
 
+func TestParseAmount(t *testing.T) {
+	v, err := ParseAmount("1.23456789", 8)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(123456789), v)
+	v, err = ParseAmount("12.5", 8)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1250000000), v)
+	v, err = ParseAmount("7", 0)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(7), v)
+
+	_, err = ParseAmount("1.234567891", 8)
+	assert.Equal(t, errAmountPrecision, err)
+	_, err = ParseAmount("0.1", 0)
+	assert.Equal(t, errAmountPrecision, err)
+	for _, amount := range []string{"", "-1", "1.", ".1", "1e8", "1.2.3"} {
+		_, err = ParseAmount(amount, 8)
+		assert.Equal(t, errAmountFormat, err, amount)
+	}
+	_, err = ParseAmount("92233720368.54775808", 8)
+	assert.Equal(t, errOverflow, err)
+}
+
 func TestFixed_Multiply(t *testing.T) {
 	f1 := Fixed{-9223372036854775807, 4, nil}
 	f2 := Fixed{-9223372036854775807, 4, nil}
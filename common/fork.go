@@ -0,0 +1,28 @@
+package common
+
+// ForkConfig is the numbers of the first blocks which follow the rule changes of the chain,
+// nil means the rule change is not activated. All the nodes of a chain must agree on it.
+type ForkConfig struct {
+	// DeployRateLimit limits the number of contracts a publisher deploys per window.
+	DeployRateLimit *int64
+	// UpdateCodeCompatibility rejects the code updates which break the old abi, unless forced.
+	UpdateCodeCompatibility *int64
+	// CallDepthLimit charges the nested contract calls rejected by host.MaxCallDepth.
+	CallDepthLimit *int64
+	// ActionDataLimit rejects the actions whose data is longer than vm.MaxActionDataLen.
+	ActionDataLimit *int64
+	// ReceiptContentLimit rejects the receipts whose content is longer than native.MaxReceiptContentLen.
+	ReceiptContentLimit *int64
+	// ContractGasReceipt tallies the gas of the actions by contract in the tx receipts.
+	ContractGasReceipt *int64
+	// ContractPausedCost charges the read of the paused flag of the contract on every contract call.
+	ContractPausedCost *int64
+}
+
+// Forks is the activation heights of the rule changes, set from the config at node start before any block is handled.
+var Forks ForkConfig
+
+// Activated returns whether the rule change activated at height applies to the block of number.
+func Activated(height *int64, number int64) bool {
+	return height != nil && number >= *height
+}
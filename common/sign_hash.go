@@ -0,0 +1,66 @@
+package common
+
+import (
+	"errors"
+
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/sha3"
+)
+
+// SignHash is the hash function applied to the payloads signed by txs and blocks.
+type SignHash byte
+
+// SignHash list
+const (
+	SignHashSha3 SignHash = iota
+	SignHashBlake2b
+)
+
+// ErrUnknownSignHash is returned when the sign hash isn't supported.
+var ErrUnknownSignHash = errors.New("unknown sign hash")
+
+// DefaultSignHash is the sign hash of the blocks generated by this node.
+var DefaultSignHash = SignHashSha3
+
+// ParseSignHash returns the sign hash of the name, "" means sha3.
+func ParseSignHash(name string) (SignHash, error) {
+	switch name {
+	case "", "sha3":
+		return SignHashSha3, nil
+	case "blake2b":
+		return SignHashBlake2b, nil
+	default:
+		return SignHashSha3, ErrUnknownSignHash
+	}
+}
+
+// String returns the name of the sign hash.
+func (h SignHash) String() string {
+	switch h {
+	case SignHashSha3:
+		return "sha3"
+	case SignHashBlake2b:
+		return "blake2b"
+	default:
+		return "unknown"
+	}
+}
+
+// Valid returns whether the sign hash is supported.
+func (h SignHash) Valid() bool {
+	return h == SignHashSha3 || h == SignHashBlake2b
+}
+
+// Sum returns the 256-bit digest of raw, nil if the sign hash isn't supported.
+func (h SignHash) Sum(raw []byte) []byte {
+	switch h {
+	case SignHashSha3:
+		data := sha3.Sum256(raw)
+		return data[:]
+	case SignHashBlake2b:
+		data := blake2b.Sum256(raw)
+		return data[:]
+	default:
+		return nil
+	}
+}
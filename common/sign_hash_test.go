@@ -0,0 +1,26 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignHash(t *testing.T) {
+	input := []byte("abc")
+	assert.Equal(t, Sha3(input), SignHashSha3.Sum(input))
+	assert.Equal(t, "bddd813c634239723171ef3fee98579b94964e3bb1cb3e427262c8c068d52319", ToHex(SignHashBlake2b.Sum(input)))
+	assert.Nil(t, SignHash(9).Sum(input))
+	assert.False(t, SignHash(9).Valid())
+
+	for _, h := range []SignHash{SignHashSha3, SignHashBlake2b} {
+		parsed, err := ParseSignHash(h.String())
+		assert.Nil(t, err)
+		assert.Equal(t, h, parsed)
+	}
+	h, err := ParseSignHash("")
+	assert.Nil(t, err)
+	assert.Equal(t, SignHashSha3, h)
+	_, err = ParseSignHash("md5")
+	assert.Equal(t, ErrUnknownSignHash, err)
+}
@@ -9,26 +9,43 @@ import (
 )
 
 var (
-	errFutureBlk  = errors.New("block from future")
-	errOldBlk     = errors.New("block time older than parent block")
-	errParentHash = errors.New("wrong parent hash")
-	errNumber     = errors.New("wrong number")
-	errTxHash     = errors.New("wrong txs hash")
-	errMerkleHash = errors.New("wrong tx receipt merkle hash")
+	// ErrFutureBlock is returned when the block time is more than MaxBlockTimeGap ahead of local time.
+	ErrFutureBlock = errors.New("block from future")
+	errOldBlk      = errors.New("block time older than parent block")
+	errParentHash  = errors.New("wrong parent hash")
+	errNumber      = errors.New("wrong number")
+	errTxHash      = errors.New("wrong txs hash")
+	errMerkleHash  = errors.New("wrong tx receipt merkle hash")
 	// errTxReceipt  = errors.New("wrong tx receipt")
 
 	// TxExecTimeLimit the maximum verify execution time of a transaction
 	TxExecTimeLimit = 400 * time.Millisecond
 
-	// MaxBlockTimeGap is the limit of the difference of block time and local time.
+	// MaxBlockTimeGap is the default limit of the difference of block time and local time.
 	MaxBlockTimeGap = 1 * time.Second.Nanoseconds()
 )
 
-// VerifyBlockHead verifies the block head.
-func VerifyBlockHead(blk *block.Block, parentBlock *block.Block) error {
+// VerifyTxRoot checks that the tx merkle hash in the block head is the root of the txs of the block.
+func VerifyTxRoot(blk *block.Block) error {
+	if !bytes.Equal(blk.CalculateTxMerkleHash(), blk.Head.TxMerkleHash) {
+		return errTxHash
+	}
+	return nil
+}
+
+// VerifyBlockTime checks that the block time is not more than maxGap nanoseconds ahead of now.
+func VerifyBlockTime(bh *block.BlockHead, now time.Time, maxGap int64) error {
+	if bh.Time > now.UnixNano()+maxGap {
+		return ErrFutureBlock
+	}
+	return nil
+}
+
+// VerifyBlockHead verifies the block head, the block time may be maxGap nanoseconds ahead of now.
+func VerifyBlockHead(blk *block.Block, parentBlock *block.Block, maxGap int64) error {
 	bh := blk.Head
-	if bh.Time > time.Now().UnixNano()+MaxBlockTimeGap {
-		return errFutureBlk
+	if err := VerifyBlockTime(bh, time.Now(), maxGap); err != nil {
+		return err
 	}
 	if bh.Time <= parentBlock.Head.Time {
 		return errOldBlk
@@ -39,8 +56,8 @@ func VerifyBlockHead(blk *block.Block, parentBlock *block.Block) error {
 	if bh.Number != parentBlock.Head.Number+1 {
 		return errNumber
 	}
-	if !bytes.Equal(blk.CalculateTxMerkleHash(), bh.TxMerkleHash) {
-		return errTxHash
+	if err := VerifyTxRoot(blk); err != nil {
+		return err
 	}
 	if !bytes.Equal(blk.CalculateTxReceiptMerkleHash(), bh.TxReceiptMerkleHash) {
 		return errMerkleHash
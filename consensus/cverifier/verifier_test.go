@@ -32,28 +32,28 @@ func TestVerifyBlockHead(t *testing.T) {
 			},
 		}
 		convey.Convey("Pass", func() {
-			err := VerifyBlockHead(blk, parentBlk)
+			err := VerifyBlockHead(blk, parentBlk, MaxBlockTimeGap)
 			convey.So(err, convey.ShouldBeNil)
 		})
 
 		convey.Convey("Wrong time", func() {
 			blk.Head.Time = stamp - 5
-			err := VerifyBlockHead(blk, parentBlk)
+			err := VerifyBlockHead(blk, parentBlk, MaxBlockTimeGap)
 			convey.So(err, convey.ShouldEqual, errOldBlk)
 			blk.Head.Time = stamp + 10*1e9
-			err = VerifyBlockHead(blk, parentBlk)
-			convey.So(err, convey.ShouldEqual, errFutureBlk)
+			err = VerifyBlockHead(blk, parentBlk, MaxBlockTimeGap)
+			convey.So(err, convey.ShouldEqual, ErrFutureBlock)
 		})
 
 		convey.Convey("Wrong parent", func() {
 			blk.Head.ParentHash = []byte("fake hash")
-			err := VerifyBlockHead(blk, parentBlk)
+			err := VerifyBlockHead(blk, parentBlk, MaxBlockTimeGap)
 			convey.So(err, convey.ShouldEqual, errParentHash)
 		})
 
 		convey.Convey("Wrong number", func() {
 			blk.Head.Number = 5
-			err := VerifyBlockHead(blk, parentBlk)
+			err := VerifyBlockHead(blk, parentBlk, MaxBlockTimeGap)
 			convey.So(err, convey.ShouldEqual, errNumber)
 		})
 
@@ -61,11 +61,34 @@ func TestVerifyBlockHead(t *testing.T) {
 			tx0 := tx.NewTx(nil, nil, 1000, 1, 300, 0, 0)
 			blk.Txs = append(blk.Txs, tx0)
 			blk.Head.TxMerkleHash = blk.CalculateTxMerkleHash()
-			err := VerifyBlockHead(blk, parentBlk)
+			err := VerifyBlockHead(blk, parentBlk, MaxBlockTimeGap)
 			convey.So(err, convey.ShouldBeNil)
 			blk.Head.TxMerkleHash = []byte("fake hash")
-			err = VerifyBlockHead(blk, parentBlk)
+			err = VerifyBlockHead(blk, parentBlk, MaxBlockTimeGap)
 			convey.So(err, convey.ShouldEqual, errTxHash)
 		})
 	})
 }
+
+func TestVerifyTxRoot(t *testing.T) {
+	Convey("Test of verify tx root", t, func() {
+		tx0 := tx.NewTx(nil, nil, 1000, 1, 300, 0, 0)
+		tx1 := tx.NewTx(nil, nil, 1000, 2, 300, 0, 0)
+		tx2 := tx.NewTx(nil, nil, 1000, 3, 300, 0, 0)
+		blk := &block.Block{
+			Head: &block.BlockHead{Number: 1},
+			Txs:  []*tx.Tx{tx0, tx1},
+		}
+		blk.Head.TxMerkleHash = blk.CalculateTxMerkleHash()
+		So(VerifyTxRoot(blk), ShouldBeNil)
+
+		blk.Txs = []*tx.Tx{tx1, tx0}
+		So(VerifyTxRoot(blk), ShouldEqual, errTxHash)
+		blk.Txs = []*tx.Tx{tx0, tx2}
+		So(VerifyTxRoot(blk), ShouldEqual, errTxHash)
+		blk.Txs = []*tx.Tx{tx0}
+		So(VerifyTxRoot(blk), ShouldEqual, errTxHash)
+		blk.Txs = nil
+		So(VerifyTxRoot(blk), ShouldEqual, errTxHash)
+	})
+}
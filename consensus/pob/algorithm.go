@@ -2,6 +2,7 @@ package pob
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/iost-official/go-iost/account"
@@ -23,15 +24,38 @@ var (
 	errTxDup                  = errors.New("duplicate tx")
 	errDoubleTx               = errors.New("double tx in block")
 	errTxLenUnmatchReceiptLen = errors.New("tx len unmatch receipt len")
+	errBaseFee                = errors.New("wrong base fee")
+	errBelowBaseFee           = errors.New("tx gas ratio below base fee")
 )
 
+// blockConfig is the configuration of this node for generating and verifying blocks,
+// the zero value is the default.
+type blockConfig struct {
+	// baseFeeHeight is the number of the first block carrying the base fee, nil means never.
+	baseFeeHeight *int64
+	// verifyTxWorkers is the number of workers verifying the tx signatures of a block, 0 means the number of cpus.
+	verifyTxWorkers int
+	// maxBlockTimeGap is how many nanoseconds a block time may be ahead of now, 0 means cverifier.MaxBlockTimeGap.
+	maxBlockTimeGap int64
+	// clock schedules the witnesses of the slots.
+	clock slotClock
+}
+
+func (c *blockConfig) blockTimeGap() int64 {
+	if c.maxBlockTimeGap > 0 {
+		return c.maxBlockTimeGap
+	}
+	return cverifier.MaxBlockTimeGap
+}
+
 func generateBlock(
 	acc *account.KeyPair,
 	txPool txpool.TxPool,
 	db db.MVCCDB,
 	limitTime time.Duration,
 	pTx *txpool.SortedTxMap,
-	head *blockcache.BlockCacheNode) (*block.Block, error) {
+	head *blockcache.BlockCacheNode,
+	conf *blockConfig) (*block.Block, error) {
 
 	ilog.Debug("generate Block start")
 	st := time.Now()
@@ -54,10 +78,17 @@ func generateBlock(
 	v := verifier.Verifier{}
 	t1 := time.Now()
 	// TODO: stateDb and block head is consisdent, pTx may be inconsisdent.
-	dropList, _, err := v.Gen(blk, topBlock, &head.WitnessList, db, pTx, &verifier.Config{
+	witnessList := head.CopyWitnessList()
+	// the txs below the base fee are left in the pool, 0 before the base fee is activated
+	var baseFee int64
+	if baseFeeActive(blk.Head.Number, conf.baseFeeHeight) {
+		baseFee = childBaseFee(topBlock)
+	}
+	dropList, _, err := v.Gen(blk, topBlock, &witnessList, db, pTx, &verifier.Config{
 		Mode:        0,
 		Timeout:     limitTime - time.Now().Sub(st),
 		TxTimeLimit: common.MaxTxTimeLimit,
+		MinGasRatio: baseFee,
 	})
 	t2 := time.Since(t1)
 	if len(blk.Txs) != 0 {
@@ -68,43 +99,70 @@ func generateBlock(
 		ilog.Errorf("Gen is err: %v", err)
 		return nil, err
 	}
+	if baseFee > 0 {
+		setBaseFee(blk.Head, baseFee)
+	}
+	setSignHash(blk.Head, common.DefaultSignHash)
 	blk.Head.TxMerkleHash = blk.CalculateTxMerkleHash()
 	blk.Head.TxReceiptMerkleHash = blk.CalculateTxReceiptMerkleHash()
 	err = blk.CalculateHeadHash()
 	if err != nil {
 		return nil, err
 	}
-	blk.Sign = acc.Sign(blk.HeadHash())
+	blk.Sign = acc.Sign(common.DefaultSignHash.Sum(blk.Head.ToBytes()))
 	db.Commit(string(blk.HeadHash()))
 	metricsGeneratedBlockCount.Add(1, nil)
 	return blk, nil
 }
 
 func verifyBasics(blk *block.Block, signature *crypto.Signature) error {
-	signature.SetPubkey(account.DecodePubkey(blk.Head.Witness))
-	hash := blk.HeadHash()
-	if !signature.Verify(hash) {
+	// The algorithm is decided by the witness's key, not by the signature.
+	pubkey := account.DecodePubkey(blk.Head.Witness)
+	algo, ok := crypto.AlgorithmOfPubkey(pubkey)
+	if !ok || signature.Algorithm != algo {
+		return errSignature
+	}
+	signature.SetPubkey(pubkey)
+	signHash, err := signHashOf(blk.Head)
+	if err != nil {
+		return errSignature
+	}
+	if !signature.Verify(signHash.Sum(blk.Head.ToBytes())) {
 		return errSignature
 	}
 	if len(blk.Txs) != len(blk.Receipts) {
 		return errTxLenUnmatchReceiptLen
 	}
+	return cverifier.VerifyTxRoot(blk)
+}
+
+// checkBlockTime rejects the block whose time is more than maxGap nanoseconds ahead of now.
+func checkBlockTime(head *block.BlockHead, now time.Time, maxGap int64) error {
+	if err := cverifier.VerifyBlockTime(head, now, maxGap); err != nil {
+		metricsFutureBlockCount.Add(1, nil)
+		ilog.Warnf("block %v time %v is ahead of local time %v", head.Number, head.Time, now.UnixNano())
+		return err
+	}
 	return nil
 }
 
-func verifyBlock(blk, parent *block.Block, witnessList *blockcache.WitnessList, txPool txpool.TxPool, db db.MVCCDB, chain block.Chain, replay bool) error {
-	err := cverifier.VerifyBlockHead(blk, parent)
+func verifyBlock(blk, parent *block.Block, witnessList *blockcache.WitnessList, txPool txpool.TxPool, db db.MVCCDB, chain block.Chain, replay bool, conf *blockConfig) error {
+	err := cverifier.VerifyBlockHead(blk, parent, conf.blockTimeGap())
 	if err != nil {
 		return err
 	}
+	if err := checkBaseFee(blk, parent, conf.baseFeeHeight); err != nil {
+		return err
+	}
 
-	if replay == false && witnessOfNanoSec(blk.Head.Time, witnessList.Active()) != blk.Head.Witness {
+	if replay == false && conf.clock.witnessOfNanoSec(blk.Head.Time, witnessList) != blk.Head.Witness {
 		ilog.Errorf("blk num: %v, time: %v, witness: %v, witness len: %v, witness list: %v",
 			blk.Head.Number, blk.Head.Time, blk.Head.Witness, len(witnessList.Active()), witnessList.Active())
 		return errWitness
 	}
 	ilog.Debugf("[pob] start to verify block if foundchain, number: %v, hash = %v, witness = %v", blk.Head.Number, common.Base58Encode(blk.HeadHash()), blk.Head.Witness[4:6])
 	blkTxSet := make(map[string]bool, len(blk.Txs))
+	unverified := make([]*tx.Tx, 0, len(blk.Txs))
 	for i, t := range blk.Txs {
 		if blkTxSet[string(t.Hash())] {
 			return errDoubleTx
@@ -117,17 +175,16 @@ func verifyBlock(blk, parent *block.Block, witnessList *blockcache.WitnessList,
 		}
 		exist := txPool.ExistTxs(t.Hash(), parent)
 		switch exist {
-		case txpool.FoundChain:
+		case txpool.FoundChain, txpool.FoundUnconfirmed:
 			ilog.Infof("FoundChain: %v, %v", t, common.Base58Encode(t.Hash()))
 			return errTxDup
 		case txpool.NotFound:
-			err := t.VerifySelf()
-			if err != nil {
-				return err
-			}
-
+			unverified = append(unverified, t)
 		}
 	}
+	if i, err := tx.VerifyTxsConcurrently(unverified, conf.verifyTxWorkers); err != nil {
+		return fmt.Errorf("%v, tx: %v", err, common.Base58Encode(unverified[i].Hash()))
+	}
 	v := verifier.Verifier{}
 	return v.Verify(blk, parent, witnessList, db, &verifier.Config{
 		Mode:        0,
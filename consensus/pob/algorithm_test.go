@@ -76,7 +76,7 @@ func BenchmarkGenerateBlock(b *testing.B) { // 296275 = 0.3ms(0tx), 466353591 =
 	b.ResetTimer()
 	pTx, head := mockTxPool.PendingTx()
 	for j := 0; j < b.N; j++ {
-		generateBlock(account, mockTxPool, stateDB, time.Millisecond*1000, pTx, head)
+		generateBlock(account, mockTxPool, stateDB, time.Millisecond*1000, pTx, head, &blockConfig{})
 	}
 	b.StopTimer()
 }
@@ -114,7 +114,7 @@ func BenchmarkVerifyBlockWithVM(b *testing.B) { // 296275 = 0.3ms(0tx), 46635359
 	mockTxPool.EXPECT().DelTxList(gomock.Any()).AnyTimes()
 
 	pTx, head := mockTxPool.PendingTx()
-	blk, _ := generateBlock(account, mockTxPool, stateDB, time.Millisecond*1000, pTx, head)
+	blk, _ := generateBlock(account, mockTxPool, stateDB, time.Millisecond*1000, pTx, head, &blockConfig{})
 
 	b.ResetTimer()
 	for j := 0; j < b.N; j++ {
@@ -312,6 +312,76 @@ func TestVerifyBasics(t *testing.T) {
 			err := verifyBasics(blk, blk.Sign)
 			convey.So(err, convey.ShouldEqual, errSignature)
 		})
+
+		convey.Convey("Tampered tx list", func() {
+			blk := &block.Block{
+				Head: &block.BlockHead{
+					Time:    1,
+					Witness: account0.ReadablePubkey(),
+				},
+				Txs:      []*tx.Tx{tx.NewTx(nil, nil, 1000, 1, 300, 0, 0)},
+				Receipts: []*tx.TxReceipt{{}},
+			}
+			blk.Head.TxMerkleHash = blk.CalculateTxMerkleHash()
+			blk.CalculateHeadHash()
+			blk.Sign = account0.Sign(blk.HeadHash())
+			convey.So(verifyBasics(blk, blk.Sign), convey.ShouldBeNil)
+
+			blk.Txs[0] = tx.NewTx(nil, nil, 1000, 2, 300, 0, 0)
+			convey.So(verifyBasics(blk, blk.Sign), convey.ShouldNotBeNil)
+		})
+
+		convey.Convey("Secp256k1 witness with an Ed25519 local node", func() {
+			local, err := account.NewKeyPair(nil, crypto.Ed25519)
+			convey.So(err, convey.ShouldBeNil)
+			blk := &block.Block{
+				Head: &block.BlockHead{
+					Time:    1,
+					Witness: account0.ReadablePubkey(),
+				},
+			}
+			blk.CalculateHeadHash()
+			blk.Sign = account0.Sign(blk.HeadHash())
+			convey.So(local.Algorithm, convey.ShouldNotEqual, blk.Sign.Algorithm)
+			convey.So(verifyBasics(blk, blk.Sign), convey.ShouldBeNil)
+
+			blk.Sign.Algorithm = crypto.Ed25519
+			convey.So(verifyBasics(blk, blk.Sign), convey.ShouldEqual, errSignature)
+		})
+
+		convey.Convey("Sign hash", func() {
+			for _, h := range []common.SignHash{common.SignHashSha3, common.SignHashBlake2b} {
+				blk := &block.Block{
+					Head: &block.BlockHead{
+						Time:    1,
+						Witness: account0.ReadablePubkey(),
+						Info:    []byte(`{"mode":0,"thread":0,"batch":null}`),
+					},
+				}
+				setSignHash(blk.Head, h)
+				signHash, err := signHashOf(blk.Head)
+				convey.So(err, convey.ShouldBeNil)
+				convey.So(signHash, convey.ShouldEqual, h)
+				blk.CalculateHeadHash()
+				blk.Sign = account0.Sign(h.Sum(blk.Head.ToBytes()))
+				convey.So(verifyBasics(blk, blk.Sign), convey.ShouldBeNil)
+			}
+
+			blk := &block.Block{
+				Head: &block.BlockHead{
+					Time:    1,
+					Witness: account0.ReadablePubkey(),
+				},
+			}
+			blk.CalculateHeadHash()
+			blk.Sign = account0.Sign(common.SignHashBlake2b.Sum(blk.Head.ToBytes()))
+			convey.So(verifyBasics(blk, blk.Sign), convey.ShouldEqual, errSignature)
+
+			blk.Head.Info = []byte(`{"sign_hash":"md5"}`)
+			blk.CalculateHeadHash()
+			blk.Sign = account0.Sign(blk.HeadHash())
+			convey.So(verifyBasics(blk, blk.Sign), convey.ShouldEqual, errSignature)
+		})
 		/*
 			convey.Convey("Slot witness duplicate", func() {
 				blk := &block.Block{
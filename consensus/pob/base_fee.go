@@ -0,0 +1,108 @@
+package pob
+
+import (
+	"encoding/json"
+
+	"github.com/iost-official/go-iost/common"
+	"github.com/iost-official/go-iost/core/block"
+	"github.com/iost-official/go-iost/verifier"
+)
+
+// The base fee is a gas ratio floor adjusted by the fullness of the parent block.
+var (
+	minBaseFee               int64 = 100
+	maxBaseFee               int64 = 10000
+	baseFeeTargetPercent     int64 = 50
+	baseFeeChangeDenominator int64 = 8
+	blockGasLimit                  = common.MaxBlockGasLimit
+)
+
+// nextBaseFee returns the base fee of the child of a block with the given base fee and gas usage.
+func nextBaseFee(parentFee, gasUsed, gasLimit int64) int64 {
+	target := gasLimit * baseFeeTargetPercent / 100
+	if target <= 0 {
+		return clampBaseFee(parentFee)
+	}
+	delta := parentFee * (gasUsed - target) / target / baseFeeChangeDenominator
+	if delta == 0 && gasUsed > target {
+		delta = 1
+	}
+	if delta == 0 && gasUsed < target {
+		delta = -1
+	}
+	return clampBaseFee(parentFee + delta)
+}
+
+func clampBaseFee(fee int64) int64 {
+	if fee < minBaseFee {
+		return minBaseFee
+	}
+	if fee > maxBaseFee {
+		return maxBaseFee
+	}
+	return fee
+}
+
+// baseFeeIn returns the base fee stored in the info of the block head, false if there is none.
+func baseFeeIn(head *block.BlockHead) (int64, bool) {
+	var info verifier.Info
+	if err := json.Unmarshal(head.Info, &info); err != nil || info.BaseFee == 0 {
+		return 0, false
+	}
+	return info.BaseFee, true
+}
+
+// baseFeeOf returns the base fee stored in the block head, minBaseFee if there is none.
+func baseFeeOf(head *block.BlockHead) int64 {
+	if fee, ok := baseFeeIn(head); ok {
+		return fee
+	}
+	return minBaseFee
+}
+
+// setBaseFee stores the base fee in the info of the block head, which is written by the verifier first.
+func setBaseFee(head *block.BlockHead, fee int64) {
+	var info verifier.Info
+	if err := json.Unmarshal(head.Info, &info); err != nil {
+		info = verifier.Info{}
+	}
+	info.BaseFee = fee
+	buf, err := json.Marshal(info)
+	if err != nil {
+		panic(err)
+	}
+	head.Info = buf
+}
+
+// baseFeeActive returns whether the block of the number carries the base fee and its txs pay it.
+// height is consensus.basefeeheight, nil means the base fee is not activated.
+func baseFeeActive(number int64, height *int64) bool {
+	return height != nil && number >= *height
+}
+
+// checkBaseFee checks the base fee of blk against its parent, and that every tx of blk pays it.
+// The base fee of a block before the activation height is not checked.
+func checkBaseFee(blk, parent *block.Block, height *int64) error {
+	if !baseFeeActive(blk.Head.Number, height) {
+		return nil
+	}
+	fee, ok := baseFeeIn(blk.Head)
+	if !ok || fee != childBaseFee(parent) {
+		return errBaseFee
+	}
+	for i, t := range blk.Txs {
+		if i == 0 {
+			// base tx
+			continue
+		}
+		if t.GasRatio < fee {
+			return errBelowBaseFee
+		}
+	}
+	return nil
+}
+
+// childBaseFee returns the base fee of the block built on top of parent.
+func childBaseFee(parent *block.Block) int64 {
+	return nextBaseFee(baseFeeOf(parent.Head), parent.CalculateGasUsage(), blockGasLimit)
+}
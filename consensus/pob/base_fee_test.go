@@ -0,0 +1,117 @@
+package pob
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/iost-official/go-iost/core/block"
+	"github.com/iost-official/go-iost/core/tx"
+	"github.com/iost-official/go-iost/core/txpool/mock"
+	"github.com/iost-official/go-iost/verifier"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+func TestNextBaseFee(t *testing.T) {
+	convey.Convey("Test of nextBaseFee", t, func() {
+		target := blockGasLimit * baseFeeTargetPercent / 100
+		convey.So(nextBaseFee(1000, target, blockGasLimit), convey.ShouldEqual, 1000)
+
+		fee := minBaseFee
+		for i := 0; i < 100; i++ {
+			next := nextBaseFee(fee, blockGasLimit, blockGasLimit)
+			if fee < maxBaseFee {
+				convey.So(next, convey.ShouldBeGreaterThan, fee)
+			} else {
+				convey.So(next, convey.ShouldEqual, maxBaseFee)
+			}
+			fee = next
+		}
+		convey.So(fee, convey.ShouldEqual, maxBaseFee)
+
+		for i := 0; i < 100; i++ {
+			next := nextBaseFee(fee, 0, blockGasLimit)
+			if fee > minBaseFee {
+				convey.So(next, convey.ShouldBeLessThan, fee)
+			} else {
+				convey.So(next, convey.ShouldEqual, minBaseFee)
+			}
+			fee = next
+		}
+		convey.So(fee, convey.ShouldEqual, minBaseFee)
+	})
+}
+
+func TestBaseFeeHead(t *testing.T) {
+	convey.Convey("Test of base fee in block head", t, func() {
+		head := &block.BlockHead{Info: make([]byte, 0)}
+		convey.So(baseFeeOf(head), convey.ShouldEqual, minBaseFee)
+		setBaseFee(head, 4321)
+		convey.So(baseFeeOf(head), convey.ShouldEqual, 4321)
+
+		head.Info = []byte(`{"mode":1,"thread":4,"batch":null}`)
+		convey.So(baseFeeOf(head), convey.ShouldEqual, minBaseFee)
+		setBaseFee(head, 4321)
+		convey.So(baseFeeOf(head), convey.ShouldEqual, 4321)
+		var info verifier.Info
+		convey.So(json.Unmarshal(head.Info, &info), convey.ShouldBeNil)
+		convey.So(info.Mode, convey.ShouldEqual, 1)
+		convey.So(info.Thread, convey.ShouldEqual, 4)
+	})
+}
+
+func TestCurrentBaseFee(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+	mockTxPool := txpool_mock.NewMockTxPool(mockController)
+
+	convey.Convey("Test of CurrentBaseFee", t, func() {
+		p := &PoB{txPool: mockTxPool, baseFee: minBaseFee, trackBaseFee: true}
+		full := &block.Block{
+			Head:     &block.BlockHead{},
+			Receipts: []*tx.TxReceipt{{GasUsage: blockGasLimit}},
+		}
+		setBaseFee(full.Head, 1000)
+
+		fee := childBaseFee(full)
+		convey.So(fee, convey.ShouldBeGreaterThan, 1000)
+		mockTxPool.EXPECT().SetMinGasPrice(fee).Times(1)
+		p.updateBaseFee(full)
+		convey.So(p.CurrentBaseFee(), convey.ShouldEqual, fee)
+
+		p.trackBaseFee = false
+		empty := &block.Block{Head: &block.BlockHead{}}
+		setBaseFee(empty.Head, fee)
+		p.updateBaseFee(empty)
+		convey.So(p.CurrentBaseFee(), convey.ShouldBeLessThan, fee)
+	})
+}
+
+func TestCheckBaseFee(t *testing.T) {
+	convey.Convey("Test of checkBaseFee", t, func() {
+		parent := &block.Block{Head: &block.BlockHead{Number: 9}}
+		setBaseFee(parent.Head, 1000)
+		blk := &block.Block{
+			Head: &block.BlockHead{Number: 10},
+			Txs:  []*tx.Tx{{}, {GasRatio: 10000}},
+		}
+
+		convey.Convey("not activated", func() {
+			convey.So(checkBaseFee(blk, parent, nil), convey.ShouldBeNil)
+			height := int64(11)
+			convey.So(checkBaseFee(blk, parent, &height), convey.ShouldBeNil)
+		})
+
+		convey.Convey("activated", func() {
+			height := int64(10)
+			convey.So(checkBaseFee(blk, parent, &height), convey.ShouldEqual, errBaseFee)
+			setBaseFee(blk.Head, childBaseFee(parent)+1)
+			convey.So(checkBaseFee(blk, parent, &height), convey.ShouldEqual, errBaseFee)
+			setBaseFee(blk.Head, childBaseFee(parent))
+			convey.So(checkBaseFee(blk, parent, &height), convey.ShouldBeNil)
+
+			blk.Txs[1].GasRatio = childBaseFee(parent) - 1
+			convey.So(checkBaseFee(blk, parent, &height), convey.ShouldEqual, errBelowBaseFee)
+		})
+	})
+}
@@ -0,0 +1,112 @@
+package pob
+
+import (
+	"sync"
+
+	"github.com/iost-official/go-iost/p2p"
+)
+
+type broadcastMsg struct {
+	data []byte
+	typ  p2p.MessageType
+	mp   p2p.MessagePriority
+}
+
+// maxBroadcastBlocks bounds the queue of blocks. Only the blocks generated by this node are queued,
+// a few per slot, so it fills only if p2p stalls for many slots, and then the oldest block is dropped.
+const maxBroadcastBlocks = 64
+
+// broadcaster sends messages to p2p asynchronously.
+// Blocks are kept in their own queue, they are sent before the other messages and are not dropped
+// for them. The other messages are kept in a bounded queue, when it is full the oldest message is dropped.
+type broadcaster struct {
+	p2pService p2p.Service
+	size       int
+
+	mu     sync.Mutex
+	blocks []*broadcastMsg
+	queue  []*broadcastMsg
+	notify chan struct{}
+}
+
+func newBroadcaster(p2pService p2p.Service, size int) *broadcaster {
+	if size <= 0 {
+		size = 1
+	}
+	return &broadcaster{
+		p2pService: p2pService,
+		size:       size,
+		queue:      make([]*broadcastMsg, 0, size),
+		notify:     make(chan struct{}, 1),
+	}
+}
+
+// Broadcast puts the message into the queue without blocking.
+func (b *broadcaster) Broadcast(data []byte, typ p2p.MessageType, mp p2p.MessagePriority) {
+	msg := &broadcastMsg{data: data, typ: typ, mp: mp}
+	b.mu.Lock()
+	if typ == p2p.NewBlock {
+		if len(b.blocks) >= maxBroadcastBlocks {
+			b.blocks[0] = nil
+			b.blocks = b.blocks[1:]
+			metricsDroppedBroadcastCount.Add(1, nil)
+		}
+		b.blocks = append(b.blocks, msg)
+	} else {
+		if len(b.queue) >= b.size {
+			b.queue[0] = nil
+			b.queue = b.queue[1:]
+			metricsDroppedBroadcastCount.Add(1, nil)
+		}
+		b.queue = append(b.queue, msg)
+	}
+	b.mu.Unlock()
+
+	select {
+	case b.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Len returns the number of messages waiting in the queue.
+func (b *broadcaster) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.blocks) + len(b.queue)
+}
+
+func (b *broadcaster) pop() *broadcastMsg {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.blocks) > 0 {
+		msg := b.blocks[0]
+		b.blocks[0] = nil
+		b.blocks = b.blocks[1:]
+		return msg
+	}
+	if len(b.queue) == 0 {
+		return nil
+	}
+	msg := b.queue[0]
+	b.queue[0] = nil
+	b.queue = b.queue[1:]
+	return msg
+}
+
+func (b *broadcaster) loop(exit <-chan struct{}) {
+	for {
+		select {
+		case <-b.notify:
+			for msg := b.pop(); msg != nil; msg = b.pop() {
+				b.p2pService.Broadcast(msg.data, msg.typ, msg.mp)
+				select {
+				case <-exit:
+					return
+				default:
+				}
+			}
+		case <-exit:
+			return
+		}
+	}
+}
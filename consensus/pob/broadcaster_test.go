@@ -0,0 +1,105 @@
+package pob
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/iost-official/go-iost/p2p"
+	p2p_mock "github.com/iost-official/go-iost/p2p/mocks"
+)
+
+func TestBroadcasterDropOldest(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+	mockP2PService := p2p_mock.NewMockService(mockController)
+
+	b := newBroadcaster(mockP2PService, 4)
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 10; i++ {
+			b.Broadcast([]byte{byte(i)}, p2p.NewBlockHash, p2p.UrgentMessage)
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("broadcast blocked when the queue is full")
+	}
+	if b.Len() != 4 {
+		t.Fatalf("expect queue length 4, got %v", b.Len())
+	}
+
+	sent := make(chan byte, 10)
+	mockP2PService.EXPECT().Broadcast(gomock.Any(), p2p.NewBlockHash, gomock.Any()).Times(4).Do(func(data []byte, typ p2p.MessageType, mp p2p.MessagePriority) {
+		sent <- data[0]
+	})
+	exit := make(chan struct{})
+	go b.loop(exit)
+	defer close(exit)
+	for i := 6; i < 10; i++ {
+		select {
+		case d := <-sent:
+			if d != byte(i) {
+				t.Fatalf("expect message %v, got %v", i, d)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("broadcast timeout")
+		}
+	}
+	if b.Len() != 0 {
+		t.Fatalf("expect empty queue, got %v", b.Len())
+	}
+}
+
+func TestBroadcasterKeepBlocks(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+	mockP2PService := p2p_mock.NewMockService(mockController)
+
+	b := newBroadcaster(mockP2PService, 2)
+	b.Broadcast([]byte{0}, p2p.NewBlockHash, p2p.UrgentMessage)
+	for i := 1; i <= 3; i++ {
+		b.Broadcast([]byte{byte(i)}, p2p.NewBlock, p2p.UrgentMessage)
+	}
+	for i := 4; i < 10; i++ {
+		b.Broadcast([]byte{byte(i)}, p2p.NewBlockHash, p2p.UrgentMessage)
+	}
+	if b.Len() != 5 {
+		t.Fatalf("expect queue length 5, got %v", b.Len())
+	}
+
+	expect := []struct {
+		data byte
+		typ  p2p.MessageType
+	}{
+		{1, p2p.NewBlock}, {2, p2p.NewBlock}, {3, p2p.NewBlock}, {8, p2p.NewBlockHash}, {9, p2p.NewBlockHash},
+	}
+	for _, e := range expect {
+		msg := b.pop()
+		if msg == nil || msg.data[0] != e.data || msg.typ != e.typ {
+			t.Fatalf("expect message %v of type %v, got %+v", e.data, e.typ, msg)
+		}
+	}
+	if msg := b.pop(); msg != nil {
+		t.Fatalf("expect empty queue, got %+v", msg)
+	}
+}
+
+func TestBroadcasterBoundBlocks(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+	mockP2PService := p2p_mock.NewMockService(mockController)
+
+	b := newBroadcaster(mockP2PService, 2)
+	for i := 0; i <= maxBroadcastBlocks; i++ {
+		b.Broadcast([]byte{byte(i)}, p2p.NewBlock, p2p.UrgentMessage)
+	}
+	if b.Len() != maxBroadcastBlocks {
+		t.Fatalf("expect queue length %v, got %v", maxBroadcastBlocks, b.Len())
+	}
+	if msg := b.pop(); msg == nil || msg.data[0] != 1 {
+		t.Fatalf("expect the oldest block dropped, got %+v", msg)
+	}
+}
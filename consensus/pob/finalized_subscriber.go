@@ -0,0 +1,68 @@
+package pob
+
+import (
+	"sync"
+
+	"github.com/iost-official/go-iost/core/block"
+	"github.com/iost-official/go-iost/ilog"
+)
+
+// finalizedChSize is the size of a finalized block subscriber's read channel.
+var finalizedChSize = 100
+
+// finalizedHub streams the blocks becoming irreversible to the subscribers.
+type finalizedHub struct {
+	mu   sync.RWMutex
+	subs []chan *block.Block
+}
+
+// SubscribeFinalized returns a channel of the blocks crossing the LIB, in order.
+// The channel is closed if the subscriber falls finalizedChSize blocks behind, so no block is missed silently.
+func (p *PoB) SubscribeFinalized() <-chan *block.Block {
+	c := make(chan *block.Block, finalizedChSize)
+	p.finalized.mu.Lock()
+	p.finalized.subs = append(p.finalized.subs, c)
+	p.finalized.mu.Unlock()
+	return c
+}
+
+// UnsubscribeFinalized removes and closes a channel returned by SubscribeFinalized.
+func (p *PoB) UnsubscribeFinalized(c <-chan *block.Block) {
+	p.finalized.mu.Lock()
+	defer p.finalized.mu.Unlock()
+	for i, sub := range p.finalized.subs {
+		if sub == c {
+			close(sub)
+			p.finalized.subs = append(p.finalized.subs[:i], p.finalized.subs[i+1:]...)
+			return
+		}
+	}
+}
+
+// notifyFinalized sends the blocks in (oldLib, newLib] to the subscribers.
+// A subscriber which doesn't keep up is closed instead of blocking the chain.
+func (p *PoB) notifyFinalized(oldLib, newLib int64) {
+	p.finalized.mu.Lock()
+	defer p.finalized.mu.Unlock()
+	if len(p.finalized.subs) == 0 {
+		return
+	}
+	for num := oldLib + 1; num <= newLib; num++ {
+		blk, err := p.blockChain.GetBlockByNumber(num)
+		if err != nil {
+			ilog.Errorf("get finalized block failed, blockNum:%v, err:%v", num, err)
+			return
+		}
+		subs := p.finalized.subs[:0]
+		for _, c := range p.finalized.subs {
+			select {
+			case c <- blk:
+				subs = append(subs, c)
+			default:
+				ilog.Warnf("finalized block subscriber is lagging, closing it. blockNum=%v", num)
+				close(c)
+			}
+		}
+		p.finalized.subs = subs
+	}
+}
@@ -0,0 +1,51 @@
+package pob
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/iost-official/go-iost/core/block"
+	"github.com/iost-official/go-iost/core/mocks"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+func TestSubscribeFinalized(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+	mockChain := core_mock.NewMockChain(mockController)
+	mockChain.EXPECT().GetBlockByNumber(gomock.Any()).AnyTimes().DoAndReturn(func(num int64) (*block.Block, error) {
+		return &block.Block{Head: &block.BlockHead{Number: num}}, nil
+	})
+
+	convey.Convey("Test of SubscribeFinalized", t, func() {
+		p := &PoB{blockChain: mockChain}
+		c1 := p.SubscribeFinalized()
+		c2 := p.SubscribeFinalized()
+
+		p.notifyFinalized(0, 3)
+		p.notifyFinalized(3, 3)
+		p.notifyFinalized(3, 5)
+
+		for _, c := range []<-chan *block.Block{c1, c2} {
+			convey.So(len(c), convey.ShouldEqual, 5)
+			for num := int64(1); num <= 5; num++ {
+				convey.So((<-c).Head.Number, convey.ShouldEqual, num)
+			}
+		}
+
+		p.UnsubscribeFinalized(c2)
+		_, ok := <-c2
+		convey.So(ok, convey.ShouldBeFalse)
+		p.notifyFinalized(5, 6)
+		convey.So((<-c1).Head.Number, convey.ShouldEqual, 6)
+
+		p.notifyFinalized(6, 6+int64(finalizedChSize)+1)
+		convey.So(len(p.finalized.subs), convey.ShouldEqual, 0)
+		convey.So(len(c1), convey.ShouldEqual, finalizedChSize)
+		for i := 0; i < finalizedChSize; i++ {
+			<-c1
+		}
+		_, ok = <-c1
+		convey.So(ok, convey.ShouldBeFalse)
+	})
+}
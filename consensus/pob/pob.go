@@ -1,18 +1,24 @@
 package pob
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/iost-official/go-iost/account"
 	"github.com/iost-official/go-iost/common"
+	"github.com/iost-official/go-iost/consensus/cverifier"
 	"github.com/iost-official/go-iost/consensus/synchro"
 	"github.com/iost-official/go-iost/consensus/synchro/pb"
 	"github.com/iost-official/go-iost/core/block"
 	"github.com/iost-official/go-iost/core/blockcache"
 	"github.com/iost-official/go-iost/core/global"
+	"github.com/iost-official/go-iost/core/tx"
 	"github.com/iost-official/go-iost/core/txpool"
 	"github.com/iost-official/go-iost/crypto"
 	"github.com/iost-official/go-iost/db"
@@ -29,20 +35,26 @@ var (
 	metricsTimeCost              = metrics.NewGauge("iost_time_cost", nil)
 	metricsTransferCost          = metrics.NewGauge("iost_transfer_cost", nil)
 	metricsGenerateBlockTimeCost = metrics.NewGauge("iost_generate_block_time_cost", nil)
+	metricsDroppedBroadcastCount = metrics.NewCounter("iost_pob_dropped_broadcast", nil)
+	metricsFutureBlockCount      = metrics.NewCounter("iost_pob_future_block", nil)
+	metricsSuppressedWarnCount   = metrics.NewCounter("iost_pob_suppressed_warning", nil)
 )
 
 var (
 	errSingle     = errors.New("single block")
 	errDuplicate  = errors.New("duplicate block")
 	errOutOfLimit = errors.New("block out of limit in one slot")
+	errBlockNum   = errors.New("block number per witness should be at least 1")
+	errWarmUp     = errors.New("head state not found")
 )
 
 var (
-	continuousNum     int
-	maxBlockNumber    int64 = 10000
-	subSlotTime             = 500 * time.Millisecond
-	genBlockTime            = 400 * time.Millisecond
-	last2GenBlockTime       = 50 * time.Millisecond
+	maxBlockNumber     int64 = 10000
+	subSlotTime              = 500 * time.Millisecond
+	genBlockTime             = 400 * time.Millisecond
+	last2GenBlockTime        = 50 * time.Millisecond
+	broadcastQueueSize       = 128
+	peerBanDuration          = 10 * time.Minute
 )
 
 //PoB is a struct that handles the consensus logic.
@@ -56,11 +68,33 @@ type PoB struct {
 	verifyDB     db.MVCCDB
 	produceDB    db.MVCCDB
 	sync         *synchro.Sync
+	broadcaster  *broadcaster
+	blockWarn    *warnLimiter
+	baseFee      int64
+	trackBaseFee bool
+	blockConf    blockConfig
+	receipts     receiptHub
+	finalized    finalizedHub
+
+	blockNumPerWitness      int
+	verifyPipelineDepth     int
+	incomingBlockBufferSize int
+	// seenBlockExpiration is how long after its time a single block kept in the block cache still
+	// dedupes a re-delivery of it, 0 means until the block cache prunes it. Linked blocks always dedupe.
+	seenBlockExpiration time.Duration
 
 	exitSignal       chan struct{}
 	quitGenerateMode chan struct{}
+	stopOnce         *sync.Once
 	wg               *sync.WaitGroup
-	mu               *sync.RWMutex
+
+	// mu serializes adding blocks: the writes to the block cache, the verify db and the nodes,
+	// from handlePreparedBlock down to the recursive addExistingBlock. The block cache and the
+	// nodes lock themselves for the readers, such as WitnessSets and the schedule loop,
+	// which must not take mu. The txpool is locked by itself inside, it never calls back into PoB.
+	// RecoverBlock runs before the loops start and doesn't take mu.
+	// SimulateBlock read-locks mu to fork the verify db.
+	mu *sync.RWMutex
 }
 
 // New init a new PoB.
@@ -86,13 +120,45 @@ func New(baseVariable global.BaseVariable, blockCache blockcache.BlockCache, txP
 		verifyDB:     baseVariable.StateDB(),
 		produceDB:    baseVariable.StateDB().Fork(),
 		sync:         nil,
+		blockWarn:    newWarnLimiter(blockWarnInterval),
+		baseFee:      minBaseFee,
 
 		exitSignal:       make(chan struct{}),
 		quitGenerateMode: make(chan struct{}),
+		stopOnce:         new(sync.Once),
 		wg:               new(sync.WaitGroup),
 		mu:               new(sync.RWMutex),
 	}
-	continuousNum = baseVariable.Continuous()
+	if conf := baseVariable.Config().TxPool; conf != nil {
+		p.trackBaseFee = conf.TrackBaseFee
+	}
+	p.verifyPipelineDepth = defaultVerifyPipelineDepth
+	if conf := baseVariable.Config().Consensus; conf != nil && conf.VerifyPipelineDepth > 0 {
+		p.verifyPipelineDepth = conf.VerifyPipelineDepth
+	}
+	queueSize := broadcastQueueSize
+	if conf := baseVariable.Config().Consensus; conf != nil {
+		p.incomingBlockBufferSize = conf.IncomingBlockBufferSize
+		p.seenBlockExpiration = conf.SeenBlockExpiration
+		p.blockConf.verifyTxWorkers = conf.VerifyTxWorkers
+		p.blockConf.maxBlockTimeGap = conf.MaxFutureBlockDrift.Nanoseconds()
+		p.blockConf.baseFeeHeight = conf.BaseFeeHeight
+		if conf.BroadcastQueueSize > 0 {
+			queueSize = conf.BroadcastQueueSize
+		}
+		p.blockConf.clock, err = newSlotClock(conf.GenesisTime, time.Now())
+		if err != nil {
+			ilog.Fatalf("Invalid consensus genesis time, stop the program! err:%v", err)
+		}
+	}
+	p.broadcaster = newBroadcaster(p2pService, queueSize)
+	p.blockNumPerWitness, err = blockNumPerWitness(baseVariable)
+	if err != nil {
+		ilog.Fatalf("Invalid consensus config, stop the program! err:%v", err)
+	}
+	if err := checkSlotTiming(p.blockNumPerWitness); err != nil {
+		ilog.Fatalf("Invalid consensus timing, stop the program! err:%v", err)
+	}
 
 	p.recoverBlockcache()
 	close(p.quitGenerateMode)
@@ -100,6 +166,40 @@ func New(baseVariable global.BaseVariable, blockCache blockcache.BlockCache, txP
 	return &p
 }
 
+// blockNumPerWitness returns the configured number of blocks a witness produces in its slot.
+func blockNumPerWitness(baseVariable global.BaseVariable) (int, error) {
+	num := baseVariable.Continuous()
+	if conf := baseVariable.Config().Consensus; conf != nil && conf.BlockNumPerWitness != 0 {
+		num = conf.BlockNumPerWitness
+	}
+	if num < 1 {
+		return 0, errBlockNum
+	}
+	return num, nil
+}
+
+// checkSlotTiming checks that the blocks of a witness fit in its slot, and each block is generated within its sub slot.
+func checkSlotTiming(blockNum int) error {
+	if last2GenBlockTime <= 0 || last2GenBlockTime > genBlockTime || genBlockTime >= subSlotTime {
+		return fmt.Errorf("block generation time should satisfy 0 < %v <= %v < sub slot time %v",
+			last2GenBlockTime, genBlockTime, subSlotTime)
+	}
+	slot := time.Duration(common.SlotLength) * time.Second
+	if time.Duration(blockNum)*subSlotTime > slot {
+		return fmt.Errorf("%v blocks per witness with sub slot time %v exceed the slot length %v",
+			blockNum, subSlotTime, slot)
+	}
+	return nil
+}
+
+// checkSerialNum checks the serial number of a block in its slot against the same limit the producer uses.
+func (p *PoB) checkSerialNum(serialNum int64) error {
+	if serialNum >= int64(p.blockNumPerWitness) {
+		return errOutOfLimit
+	}
+	return nil
+}
+
 func (p *PoB) recoverBlockcache() error {
 	err := p.blockCache.Recover(p)
 	if err != nil {
@@ -115,21 +215,38 @@ func (p *PoB) recoverBlockcache() error {
 
 //Start make the PoB run.
 func (p *PoB) Start() error {
-	p.sync = synchro.New(p.p2pService, p.blockCache, p.blockChain)
+	var err error
+	p.sync, err = synchro.New(p.p2pService, p.blockCache, p.blockChain, p.incomingBlockBufferSize)
+	if err != nil {
+		return err
+	}
 	p.baseVariable.SetMode(global.ModeNormal)
 
-	p.wg.Add(2)
+	p.wg.Add(3)
 	go p.verifyLoop()
 	go p.scheduleLoop()
+	go p.broadcastLoop()
 	return nil
 }
 
-//Stop make the PoB stop
+//Stop make the PoB stop, it is safe to call Stop more than once.
 func (p *PoB) Stop() {
-	close(p.exitSignal)
-	p.wg.Wait()
+	p.stopOnce.Do(func() {
+		close(p.exitSignal)
+		p.wg.Wait()
 
-	p.sync.Close()
+		p.sync.Close()
+	})
+}
+
+func (p *PoB) broadcastLoop() {
+	defer p.wg.Done()
+	p.broadcaster.loop(p.exitSignal)
+}
+
+// BroadcastQueueLen returns the number of broadcasts waiting to be sent.
+func (p *PoB) BroadcastQueueLen() int {
+	return p.broadcaster.Len()
 }
 
 func (p *PoB) broadcastBlockHash(blk *block.Block) {
@@ -145,7 +262,7 @@ func (p *PoB) broadcastBlockHash(blk *block.Block) {
 	if err != nil {
 		ilog.Errorf("fail to encode block hash, err=%v, blockHash=%+v", err, *blkInfo)
 	} else {
-		p.p2pService.Broadcast(b, p2p.NewBlockHash, p2p.UrgentMessage)
+		p.broadcaster.Broadcast(b, p2p.NewBlockHash, p2p.UrgentMessage)
 	}
 }
 
@@ -153,7 +270,7 @@ func calculateTime(blk *block.Block) float64 {
 	return float64((time.Now().UnixNano() - blk.Head.Time) / 1e6)
 }
 
-func (p *PoB) doVerifyBlock(blkMsg *synchro.BlockMessage) {
+func (p *PoB) doVerifyBlock(blkMsg *synchro.BlockMessage, prepErr error) {
 	if p.baseVariable.Mode() == global.ModeInit {
 		return
 	}
@@ -163,58 +280,96 @@ func (p *PoB) doVerifyBlock(blkMsg *synchro.BlockMessage) {
 	case p2p.NewBlock:
 		t1 := calculateTime(blk)
 		metricsTransferCost.Set(t1, nil)
-		err := p.handleRecvBlock(blk)
+		err := p.handlePreparedBlock(blk, prepErr)
 		t2 := calculateTime(blk)
 		metricsTimeCost.Set(t2, nil)
 		if err == errSingle || err == nil {
-			go p.broadcastBlockHash(blk)
+			p.broadcastBlockHash(blk)
 		}
 		if err != nil && err != errSingle && err != errDuplicate {
-			ilog.Warnf("received new block error, err:%v", err)
+			p.blockWarn.Warnf(blockWarnKey("new", blkMsg.From, err), "received new block error, err:%v", err)
+			p.reportPeer(blkMsg, err)
 			return
 		}
 	case p2p.SyncBlockResponse:
-		err := p.handleRecvBlock(blk)
+		err := p.handlePreparedBlock(blk, prepErr)
 		if err != nil && err != errSingle && err != errDuplicate {
-			ilog.Warnf("received sync block error, err:%v", err)
+			p.blockWarn.Warnf(blockWarnKey("sync", blkMsg.From, err), "received sync block error, err:%v", err)
+			p.reportPeer(blkMsg, err)
 			return
 		}
 	}
 	metricsVerifyBlockCount.Add(1, nil)
 }
 
+// isHardFailure returns whether the block error proves the sender is faulty or malicious.
+// Only errors that any relayer can check without chain state count, since the sender of a
+// synced block is not necessarily its producer.
+func isHardFailure(err error) bool {
+	switch err {
+	case errSignature, errTxLenUnmatchReceiptLen:
+		return true
+	default:
+		return false
+	}
+}
+
+// blockWarnKey returns the key limiting the warnings of the block error from the peer.
+// The message of the other errors may carry a hash, such as the tx errors, so they share one key.
+func blockWarnKey(kind, from string, err error) string {
+	category := "other"
+	switch err {
+	case errWitness, errSignature, errTxDup, errDoubleTx, errTxLenUnmatchReceiptLen, errBaseFee, errBelowBaseFee,
+		errOutOfLimit, errWarmUp, cverifier.ErrFutureBlock:
+		category = err.Error()
+	}
+	return kind + "/" + from + "/" + category
+}
+
+// reportPeer bans the peer sending an invalid block for peerBanDuration.
+// Soft failures such as duplicate or single blocks are not penalized.
+func (p *PoB) reportPeer(blkMsg *synchro.BlockMessage, err error) {
+	if !isHardFailure(err) || blkMsg.From == "" {
+		return
+	}
+	ilog.Warnf("ban peer %v for %v, err:%v", blkMsg.From, peerBanDuration, err)
+	p.p2pService.BanPeer(blkMsg.From, peerBanDuration)
+}
+
 func (p *PoB) verifyLoop() {
 	defer p.wg.Done()
-	for {
-		select {
-		case blkMsg := <-p.sync.IncomingBlock():
-			select {
-			case <-p.quitGenerateMode:
-			}
-			if p.blockCache.Head().Head.Number+maxBlockNumber < blkMsg.Blk.Head.Number {
-				ilog.Debugf("block number is too large, block number:%v", blkMsg.Blk.Head.Number)
-				continue
-			}
+	prepare := func(blkMsg *synchro.BlockMessage) error {
+		return prepareBlock(blkMsg.Blk, &p.blockConf)
+	}
+	pipeline := newVerifyPipeline(p.verifyPipelineDepth, prepare, p.applyBlock)
+	pipeline.run(p.sync.IncomingBlock(), p.exitSignal)
+}
 
-			p.doVerifyBlock(blkMsg)
+func (p *PoB) applyBlock(blkMsg *synchro.BlockMessage, prepErr error) {
+	select {
+	case <-p.quitGenerateMode:
+	}
+	if p.blockCache.Head().Head.Number+maxBlockNumber < blkMsg.Blk.Head.Number {
+		ilog.Debugf("block number is too large, block number:%v", blkMsg.Blk.Head.Number)
+		return
+	}
 
-			height := p.blockCache.Head().Head.Number
-			if p.sync.NeighborHeight() > height+120 {
-				p.baseVariable.SetMode(global.ModeSync)
-			} else {
-				p.baseVariable.SetMode(global.ModeNormal)
-			}
-		case <-p.exitSignal:
-			return
-		}
+	p.doVerifyBlock(blkMsg, prepErr)
+
+	height := p.blockCache.Head().Head.Number
+	if p.sync.NeighborHeight() > height+120 {
+		p.baseVariable.SetMode(global.ModeSync)
+	} else {
+		p.baseVariable.SetMode(global.ModeNormal)
 	}
 }
 
 func (p *PoB) scheduleLoop() {
 	defer p.wg.Done()
-	nextSchedule := timeUntilNextSchedule(time.Now().UnixNano())
+	nextSchedule := p.blockConf.clock.timeUntilNextSchedule(time.Now().UnixNano())
 	ilog.Debugf("nextSchedule: %.2f", time.Duration(nextSchedule).Seconds())
 	pubkey := p.account.ReadablePubkey()
+	p.warmUpBeforeSlot(nextSchedule, pubkey)
 
 	var slotFlag int64
 	for {
@@ -224,43 +379,70 @@ func (p *PoB) scheduleLoop() {
 			metricsMode.Set(float64(p.baseVariable.Mode()), nil)
 			t := time.Now()
 			pTx, head := p.txPool.PendingTx()
-			witnessList := head.Active()
-			if slotFlag != slotOfSec(t.Unix()) && p.baseVariable.Mode() == global.ModeNormal && witnessOfNanoSec(t.UnixNano(), witnessList) == pubkey {
+			if head != nil && slotFlag != p.blockConf.clock.slotOfSec(t.Unix()) && p.baseVariable.Mode() == global.ModeNormal && p.blockConf.clock.witnessOfNanoSec(t.UnixNano(), head) == pubkey {
 				p.quitGenerateMode = make(chan struct{})
-				slotFlag = slotOfSec(t.Unix())
+				slotFlag = p.blockConf.clock.slotOfSec(t.Unix())
 				generateBlockTicker := time.NewTicker(subSlotTime)
-				for num := 0; num < continuousNum; num++ {
+				for num := 0; num < p.blockNumPerWitness; num++ {
 					p.gen(num, pTx, head)
-					if num == continuousNum-1 {
+					if num == p.blockNumPerWitness-1 {
 						break
 					}
 					select {
 					case <-generateBlockTicker.C:
 					}
 					pTx, head = p.txPool.PendingTx()
-					witnessList = head.Active()
-					if witnessOfNanoSec(time.Now().UnixNano(), witnessList) != pubkey {
+					if head == nil || p.blockConf.clock.witnessOfNanoSec(time.Now().UnixNano(), head) != pubkey {
 						break
 					}
 				}
 				close(p.quitGenerateMode)
 				generateBlockTicker.Stop()
 			}
-			nextSchedule = timeUntilNextSchedule(time.Now().UnixNano())
+			nextSchedule = p.blockConf.clock.timeUntilNextSchedule(time.Now().UnixNano())
 			ilog.Debugf("nextSchedule: %.2f", time.Duration(nextSchedule).Seconds())
+			p.warmUpBeforeSlot(nextSchedule, pubkey)
 		case <-p.exitSignal:
 			return
 		}
 	}
 }
 
+// WarmUp checks out the head state in produceDB and loads the active witness list,
+// so that the first block of the coming slot doesn't pay the cold cache cost.
+func (p *PoB) WarmUp() error {
+	return p.warmUp(p.blockCache.Head())
+}
+
+func (p *PoB) warmUp(head *blockcache.BlockCacheNode) error {
+	if !p.produceDB.Checkout(string(head.HeadHash())) {
+		return errWarmUp
+	}
+	head.Active()
+	return nil
+}
+
+func (p *PoB) warmUpBeforeSlot(nextSchedule int64, pubkey string) {
+	if p.baseVariable.Mode() != global.ModeNormal {
+		return
+	}
+	head := p.blockCache.Head()
+	if p.blockConf.clock.witnessOfNanoSec(time.Now().UnixNano()+nextSchedule, head) != pubkey {
+		return
+	}
+	if err := p.warmUp(head); err != nil {
+		ilog.Warnf("Warm up before slot failed: %v", err)
+	}
+}
+
 func (p *PoB) gen(num int, pTx *txpool.SortedTxMap, head *blockcache.BlockCacheNode) {
-	limitTime := genBlockTime
-	if num >= continuousNum-2 {
-		limitTime = last2GenBlockTime
+	if pTx == nil || head == nil {
+		ilog.Warnf("Skip generating block %v, pending txs or head is nil, the tx pool may be stopping.", num)
+		return
 	}
+	limitTime := p.genLimitTime(num, time.Now())
 	p.txPool.Lock()
-	blk, err := generateBlock(p.account, p.txPool, p.produceDB, limitTime, pTx, head)
+	blk, err := generateBlock(p.account, p.txPool, p.produceDB, limitTime, pTx, head, &p.blockConf)
 	p.txPool.Release()
 	if err != nil {
 		ilog.Error(err)
@@ -272,7 +454,7 @@ func (p *PoB) gen(num int, pTx *txpool.SortedTxMap, head *blockcache.BlockCacheN
 		ilog.Error(err)
 		return
 	}
-	p.p2pService.Broadcast(blkByte, p2p.NewBlock, p2p.UrgentMessage)
+	p.broadcaster.Broadcast(blkByte, p2p.NewBlock, p2p.UrgentMessage)
 	metricsGenerateBlockTimeCost.Set(calculateTime(blk), nil)
 	err = p.handleRecvBlock(blk)
 	if err != nil {
@@ -281,18 +463,40 @@ func (p *PoB) gen(num int, pTx *txpool.SortedTxMap, head *blockcache.BlockCacheN
 	}
 }
 
+// remainingSlotTime returns the time left in the slot of now.
+func (p *PoB) remainingSlotTime(now time.Time) time.Duration {
+	return time.Duration(p.blockConf.clock.timeUntilNextSchedule(now.UnixNano()))
+}
+
+// genLimitTime returns the time budget of the num-th block in the slot, which doesn't exceed the remaining slot time.
+func (p *PoB) genLimitTime(num int, now time.Time) time.Duration {
+	limitTime := genBlockTime
+	if num >= p.blockNumPerWitness-2 {
+		limitTime = last2GenBlockTime
+	}
+	if remaining := p.remainingSlotTime(now); remaining < limitTime {
+		limitTime = remaining
+	}
+	return limitTime
+}
+
 func (p *PoB) printStatistics(num int, blk *block.Block) {
 	ptx, _ := p.txPool.PendingTx()
-	ilog.Infof("Gen block - @%v id:%v..., t:%v, num:%v, confirmed:%v, txs:%v, pendingtxs:%v, et:%vms",
-		num,
-		p.account.ReadablePubkey()[:10],
-		blk.Head.Time,
-		blk.Head.Number,
-		p.blockCache.LinkedRoot().Head.Number,
-		len(blk.Txs),
-		ptx.Size(),
-		calculateTime(blk),
-	)
+	fields := blockLogFields(int64(num), blk, p.blockCache.LinkedRoot().Head.Number)
+	ilog.Infow("Gen block", append(fields, "pendingtxs", ptx.Size())...)
+}
+
+// blockLogFields returns the key/value pairs logged for a generated or received block.
+func blockLogFields(serialNum int64, blk *block.Block, confirmed int64) []interface{} {
+	return []interface{}{
+		"serial", serialNum,
+		"witness", blk.Head.Witness,
+		"num", blk.Head.Number,
+		"time", blk.Head.Time,
+		"txs", len(blk.Txs),
+		"confirmed", confirmed,
+		"latency_ms", calculateTime(blk),
+	}
 }
 
 // RecoverBlock recover block from block cache wal
@@ -313,18 +517,28 @@ func (p *PoB) RecoverBlock(blk *block.Block) error {
 	return errSingle
 }
 
+// seenBlockExpired returns whether the cached node no longer dedupes a re-delivered block at now,
+// so that the block is handled again and linked if its parent has arrived since.
+func seenBlockExpired(node *blockcache.BlockCacheNode, now time.Time, expiration time.Duration) bool {
+	return expiration > 0 && node.Type == blockcache.Single &&
+		now.UnixNano()-node.Head.Time > int64(expiration)
+}
+
 func (p *PoB) handleRecvBlock(blk *block.Block) error {
+	return p.handlePreparedBlock(blk, prepareBlock(blk, &p.blockConf))
+}
+
+// handlePreparedBlock adds a block whose stateless checks returned prepErr.
+func (p *PoB) handlePreparedBlock(blk *block.Block, prepErr error) error {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
-	_, err := p.blockCache.Find(blk.HeadHash())
-	if err == nil {
+	if node, err := p.blockCache.Find(blk.HeadHash()); err == nil && !seenBlockExpired(node, time.Now(), p.seenBlockExpiration) {
 		return errDuplicate
 	}
 
-	err = verifyBasics(blk, blk.Sign)
-	if err != nil {
-		return err
+	if prepErr != nil {
+		return prepErr
 	}
 
 	parent, err := p.blockCache.Find(blk.Head.ParentHash)
@@ -335,24 +549,77 @@ func (p *PoB) handleRecvBlock(blk *block.Block) error {
 	return errSingle
 }
 
+// SimulateBlock verifies the block against a fork of the verify db without committing it,
+// so the real chain state is left untouched. It returns the receipts of the block.
+func (p *PoB) SimulateBlock(blk *block.Block) ([]*tx.TxReceipt, error) {
+	parent, err := p.blockCache.Find(blk.Head.ParentHash)
+	if err != nil || parent.Type != blockcache.Linked {
+		return nil, errSingle
+	}
+	return p.simulateBlock(blk, parent)
+}
+
+func (p *PoB) simulateBlock(blk *block.Block, parentNode *blockcache.BlockCacheNode) ([]*tx.TxReceipt, error) {
+	// Fork under mu so that addExistingBlock doesn't move the verify db in the middle,
+	// the fork is verified without the lock.
+	p.mu.RLock()
+	simDB := p.verifyDB.Fork()
+	p.mu.RUnlock()
+	if !simDB.Checkout(string(blk.Head.ParentHash)) {
+		return nil, errSingle
+	}
+	witnessList := parentNode.CopyWitnessList()
+	p.txPool.Lock()
+	err := verifyBlock(blk, parentNode.Block, &witnessList, p.txPool, simDB, p.blockChain, false, &p.blockConf)
+	p.txPool.Release()
+	if err != nil {
+		return nil, err
+	}
+	return blk.Receipts, nil
+}
+
+// WitnessSets returns copies of the active witnesses of the head and the pending witnesses which will be active next.
+func (p *PoB) WitnessSets() (active []string, pending []string) {
+	return witnessSets(p.blockCache.Head())
+}
+
+func witnessSets(node *blockcache.BlockCacheNode) (active []string, pending []string) {
+	active = append([]string{}, node.Active()...)
+	pending = append([]string{}, node.Pending()...)
+	return active, pending
+}
+
+// CurrentBaseFee returns the base fee of the next block on the current head.
+func (p *PoB) CurrentBaseFee() int64 {
+	return atomic.LoadInt64(&p.baseFee)
+}
+
+func (p *PoB) updateBaseFee(head *block.Block) {
+	fee := childBaseFee(head)
+	atomic.StoreInt64(&p.baseFee, fee)
+	if p.trackBaseFee {
+		p.txPool.SetMinGasPrice(fee)
+	}
+}
+
 func (p *PoB) addExistingBlock(blk *block.Block, parentNode *blockcache.BlockCacheNode, replay bool) error {
 	node, _ := p.blockCache.Find(blk.HeadHash())
 
 	if parentNode.Block.Head.Witness != blk.Head.Witness ||
-		slotOfSec(parentNode.Block.Head.Time/1e9) != slotOfSec(blk.Head.Time/1e9) {
+		p.blockConf.clock.slotOfSec(parentNode.Block.Head.Time/1e9) != p.blockConf.clock.slotOfSec(blk.Head.Time/1e9) {
 		node.SerialNum = 0
 	} else {
 		node.SerialNum = parentNode.SerialNum + 1
 	}
 
-	if node.SerialNum >= int64(p.baseVariable.Continuous()) {
-		return errOutOfLimit
+	if err := p.checkSerialNum(node.SerialNum); err != nil {
+		return err
 	}
 	ok := p.verifyDB.Checkout(string(blk.HeadHash()))
 	if !ok {
 		p.verifyDB.Checkout(string(blk.Head.ParentHash))
 		p.txPool.Lock()
-		err := verifyBlock(blk, parentNode.Block, &node.GetParent().WitnessList, p.txPool, p.verifyDB, p.blockChain, replay)
+		err := verifyBlock(blk, parentNode.Block, &node.GetParent().WitnessList, p.txPool, p.verifyDB, p.blockChain, replay, &p.blockConf)
 		p.txPool.Release()
 		if err != nil {
 			ilog.Errorf("verify block failed, blockNum:%v, blockHash:%v. err=%v", blk.Head.Number, common.Base58Encode(blk.HeadHash()), err)
@@ -362,10 +629,14 @@ func (p *PoB) addExistingBlock(blk *block.Block, parentNode *blockcache.BlockCac
 		p.verifyDB.Commit(string(blk.HeadHash()))
 	}
 	p.blockCache.Link(node, replay)
+	p.receipts.publish(blk)
+	lib := p.blockCache.LinkedRoot().Head.Number
 	p.blockCache.UpdateLib(node)
+	p.notifyFinalized(lib, p.blockCache.LinkedRoot().Head.Number)
 	// After UpdateLib, the block head active witness list will be right
 	// So AddLinkedNode need execute after UpdateLib
 	p.txPool.AddLinkedNode(node)
+	p.updateBaseFee(p.blockCache.Head().Block)
 
 	metricsConfirmedLength.Set(float64(p.blockCache.LinkedRoot().Head.Number), nil)
 
@@ -376,12 +647,24 @@ func (p *PoB) addExistingBlock(blk *block.Block, parentNode *blockcache.BlockCac
 	}
 
 	if node.Head.Witness != p.account.ReadablePubkey() {
-		ilog.Infof("Rec block - @%v id:%v..., num:%v, t:%v, txs:%v, confirmed:%v, et:%vms",
-			node.SerialNum, node.Head.Witness[:10], node.Head.Number, node.Head.Time, len(node.Txs), p.blockCache.LinkedRoot().Head.Number, calculateTime(node.Block))
+		ilog.Infow("Rec block", blockLogFields(node.SerialNum, node.Block, p.blockCache.LinkedRoot().Head.Number)...)
 	}
 
-	for child := range node.Children {
+	for _, child := range sortedChildren(node) {
 		p.addExistingBlock(child.Block, node, replay)
 	}
 	return nil
 }
+
+// sortedChildren returns the children of the node sorted by block hash,
+// so that all nodes process the forks in the same order.
+func sortedChildren(node *blockcache.BlockCacheNode) []*blockcache.BlockCacheNode {
+	children := make([]*blockcache.BlockCacheNode, 0, len(node.Children))
+	for child := range node.Children {
+		children = append(children, child)
+	}
+	sort.Slice(children, func(i, j int) bool {
+		return bytes.Compare(children[i].HeadHash(), children[j].HeadHash()) < 0
+	})
+	return children
+}
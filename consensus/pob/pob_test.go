@@ -1,7 +1,12 @@
 package pob
 
 import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
 	"os/exec"
+	"sync"
 	"testing"
 	"time"
 
@@ -10,15 +15,20 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/iost-official/go-iost/account"
 	"github.com/iost-official/go-iost/common"
+	"github.com/iost-official/go-iost/consensus/synchro"
 	"github.com/iost-official/go-iost/core/block"
 	"github.com/iost-official/go-iost/core/blockcache"
 	"github.com/iost-official/go-iost/core/global"
+	"github.com/iost-official/go-iost/core/mocks"
 	"github.com/iost-official/go-iost/core/tx"
 	"github.com/iost-official/go-iost/core/txpool"
+	"github.com/iost-official/go-iost/core/txpool/mock"
 	"github.com/iost-official/go-iost/crypto"
+	"github.com/iost-official/go-iost/db"
 	"github.com/iost-official/go-iost/ilog"
 	"github.com/iost-official/go-iost/p2p"
 	"github.com/iost-official/go-iost/p2p/mocks"
+	"github.com/iost-official/go-iost/vm/database"
 )
 
 func testRun(t *testing.T) {
@@ -68,3 +78,511 @@ func testRun(t *testing.T) {
 	ilog.AddWriter(fw)
 	select {}
 }
+
+func TestReportPeer(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+	mockP2PService := p2p_mock.NewMockService(mockController)
+	p := &PoB{p2pService: mockP2PService}
+
+	mockP2PService.EXPECT().BanPeer("badpeer", peerBanDuration).Times(1)
+	p.reportPeer(&synchro.BlockMessage{From: "badpeer"}, errSignature)
+
+	p.reportPeer(&synchro.BlockMessage{From: "relayer"}, errWitness)
+	p.reportPeer(&synchro.BlockMessage{From: "relayer"}, errOutOfLimit)
+
+	p.reportPeer(&synchro.BlockMessage{From: "goodpeer"}, errDuplicate)
+	p.reportPeer(&synchro.BlockMessage{From: "goodpeer"}, errSingle)
+}
+
+func TestSimulateBlock(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+	mockTxPool := txpool_mock.NewMockTxPool(mockController)
+	mockTxPool.EXPECT().Lock().AnyTimes()
+	mockTxPool.EXPECT().Release().AnyTimes()
+
+	dir, err := ioutil.TempDir("", "simulate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	verifyDB, err := db.NewMVCCDB(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer verifyDB.Close()
+
+	parentBlk := &block.Block{
+		Head:     &block.BlockHead{Number: 0, Time: 0},
+		Txs:      []*tx.Tx{},
+		Receipts: []*tx.TxReceipt{},
+	}
+	parentBlk.CalculateHeadHash()
+	verifyDB.Commit(string(parentBlk.HeadHash()))
+	parentNode := blockcache.NewBCN(nil, parentBlk)
+	parentNode.SetActive([]string{"witness0"})
+
+	blk := &block.Block{
+		Head: &block.BlockHead{
+			Number:     1,
+			ParentHash: parentBlk.HeadHash(),
+			Witness:    "witness1",
+			Time:       time.Now().UnixNano(),
+		},
+		Txs:      []*tx.Tx{},
+		Receipts: []*tx.TxReceipt{},
+	}
+	blk.Head.TxMerkleHash = blk.CalculateTxMerkleHash()
+	blk.Head.TxReceiptMerkleHash = blk.CalculateTxReceiptMerkleHash()
+	blk.CalculateHeadHash()
+
+	p := &PoB{verifyDB: verifyDB, txPool: mockTxPool, mu: new(sync.RWMutex)}
+	_, err = p.simulateBlock(blk, parentNode)
+	if err != errWitness {
+		t.Fatalf("expect %v, got %v", errWitness, err)
+	}
+	if tag := verifyDB.CurrentTag(); tag != string(parentBlk.HeadHash()) {
+		t.Fatalf("verifyDB head changed to %v", tag)
+	}
+	if verifyDB.Checkout(string(blk.HeadHash())) {
+		t.Fatal("simulated block should not be committed")
+	}
+}
+
+// findOnlyCache is a block cache which only supports Find, any change to it panics.
+type findOnlyCache struct {
+	blockcache.BlockCache
+	nodes map[string]*blockcache.BlockCacheNode
+}
+
+func (c *findOnlyCache) Find(hash []byte) (*blockcache.BlockCacheNode, error) {
+	node, ok := c.nodes[string(hash)]
+	if !ok {
+		return nil, errors.New("block not found")
+	}
+	return node, nil
+}
+
+func TestSimulateValidBlock(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+	mockTxPool := txpool_mock.NewMockTxPool(mockController)
+	mockTxPool.EXPECT().Lock().AnyTimes()
+	mockTxPool.EXPECT().Release().AnyTimes()
+
+	dir, err := ioutil.TempDir("", "simulate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	verifyDB, err := db.NewMVCCDB(dir + "/verify")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer verifyDB.Close()
+	produceDB, err := db.NewMVCCDB(dir + "/produce")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer produceDB.Close()
+
+	acc, err := account.NewKeyPair(nil, crypto.Ed25519)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parentBlk := &block.Block{
+		Head:     &block.BlockHead{Number: -1, Time: 0},
+		Txs:      []*tx.Tx{},
+		Receipts: []*tx.TxReceipt{},
+	}
+	parentBlk.CalculateHeadHash()
+	verifyDB.Commit(string(parentBlk.HeadHash()))
+	produceDB.Commit(string(parentBlk.HeadHash()))
+	parentNode := blockcache.NewBCN(nil, parentBlk)
+	parentNode.Type = blockcache.Linked
+	parentNode.SetActive([]string{acc.ReadablePubkey()})
+
+	blk, err := generateBlock(acc, mockTxPool, produceDB, time.Second, txpool.NewSortedTxMap(), parentNode, &blockConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cache := &findOnlyCache{nodes: map[string]*blockcache.BlockCacheNode{string(parentBlk.HeadHash()): parentNode}}
+	p := &PoB{verifyDB: verifyDB, txPool: mockTxPool, blockCache: cache, mu: new(sync.RWMutex)}
+	receipts, err := p.SimulateBlock(blk)
+	if err != nil {
+		t.Fatalf("simulate valid block error: %v", err)
+	}
+	if len(receipts) != len(blk.Txs) || receipts[0].Status.Code != tx.Success {
+		t.Fatalf("unexpected receipts %v", receipts)
+	}
+	if len(parentNode.Children) != 0 {
+		t.Fatal("simulated block should not be added to the block cache")
+	}
+	if tag := verifyDB.CurrentTag(); tag != string(parentBlk.HeadHash()) {
+		t.Fatalf("verifyDB head changed to %v", tag)
+	}
+	if verifyDB.Checkout(string(blk.HeadHash())) {
+		t.Fatal("simulated block should not be committed")
+	}
+}
+
+func TestConcurrentRecvBlock(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+	mockTxPool := txpool_mock.NewMockTxPool(mockController)
+	mockTxPool.EXPECT().AddLinkedNode(gomock.Any()).AnyTimes().Return(nil)
+	mockP2PService := p2p_mock.NewMockService(mockController)
+	mockP2PService.EXPECT().ConnectBPs(gomock.Any()).AnyTimes()
+
+	dir, err := ioutil.TempDir("", "recvblock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	baseVariable, err := global.New(&common.Config{
+		DB:       &common.DBConfig{LdbPath: dir + "/"},
+		Snapshot: &common.SnapshotConfig{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer baseVariable.StateDB().Close()
+	baseVariable.SetMode(global.ModeNormal)
+
+	acc, err := account.NewKeyPair(nil, crypto.Ed25519)
+	if err != nil {
+		t.Fatal(err)
+	}
+	genesis := &block.Block{
+		Head:     &block.BlockHead{Number: 0, Time: 0},
+		Txs:      []*tx.Tx{},
+		Receipts: []*tx.TxReceipt{},
+	}
+	genesis.CalculateHeadHash()
+	genesis.Sign = acc.Sign(genesis.HeadHash())
+	if err := baseVariable.BlockChain().Push(genesis); err != nil {
+		t.Fatal(err)
+	}
+	vi := database.NewVisitor(0, baseVariable.StateDB())
+	vi.Put("vote_producer.iost-pendingProducerList", database.MustMarshal(fmt.Sprintf(`["%v"]`, acc.ReadablePubkey())))
+	vi.Commit()
+	baseVariable.StateDB().Commit(string(genesis.HeadHash()))
+
+	// The state of the blocks is committed in advance, so adding them skips the vm
+	// and only touches the block cache, its nodes and the txpool.
+	blks := make([]*block.Block, 0)
+	parent := genesis
+	for i := int64(1); i <= common.VoteInterval+10; i++ {
+		blk := &block.Block{
+			Head: &block.BlockHead{
+				Number:     i,
+				ParentHash: parent.HeadHash(),
+				Witness:    acc.ReadablePubkey(),
+				Time:       time.Now().UnixNano(),
+			},
+			Txs:      []*tx.Tx{},
+			Receipts: []*tx.TxReceipt{},
+		}
+		blk.Head.TxMerkleHash = blk.CalculateTxMerkleHash()
+		blk.CalculateHeadHash()
+		blk.Sign = acc.Sign(blk.HeadHash())
+		if i == common.VoteInterval {
+			// Change the pending witnesses, so the active lists of the linked nodes are updated.
+			vi.Put("vote_producer.iost-pendingProducerList", database.MustMarshal(fmt.Sprintf(`["%v","w1"]`, acc.ReadablePubkey())))
+			vi.Commit()
+		}
+		baseVariable.StateDB().Commit(string(blk.HeadHash()))
+		blks = append(blks, blk)
+		parent = blk
+	}
+
+	blockCache, err := blockcache.NewBlockCache(baseVariable)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer blockCache.CleanDir()
+	p := &PoB{
+		account:            acc,
+		baseVariable:       baseVariable,
+		blockChain:         baseVariable.BlockChain(),
+		blockCache:         blockCache,
+		txPool:             mockTxPool,
+		p2pService:         mockP2PService,
+		verifyDB:           baseVariable.StateDB(),
+		blockNumPerWitness: len(blks) + 1,
+		mu:                 new(sync.RWMutex),
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		for _, blk := range blks {
+			if err := p.handleRecvBlock(blk); err != nil {
+				done <- err
+				return
+			}
+		}
+		done <- nil
+	}()
+	for {
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatalf("handle block error: %v", err)
+			}
+			if num := blockCache.Head().Head.Number; num != int64(len(blks)) {
+				t.Fatalf("expect head %v, got %v", len(blks), num)
+			}
+			return
+		default:
+		}
+		active, pending := p.WitnessSets()
+		if len(active) == 0 || len(pending) == 0 {
+			t.Fatalf("unexpected witness sets %v %v", active, pending)
+		}
+		if mode := p.baseVariable.Mode(); mode != global.ModeNormal {
+			t.Fatalf("unexpected mode %v", mode)
+		}
+	}
+}
+
+func TestBlockLogFields(t *testing.T) {
+	blk := &block.Block{
+		Head: &block.BlockHead{
+			Number:  10,
+			Witness: "witness0",
+			Time:    time.Now().UnixNano(),
+		},
+		Txs: []*tx.Tx{{}, {}},
+	}
+	fields := blockLogFields(2, blk, 8)
+	values := make(map[string]interface{})
+	for i := 0; i+1 < len(fields); i += 2 {
+		values[fields[i].(string)] = fields[i+1]
+	}
+	for _, key := range []string{"serial", "witness", "num", "time", "txs", "confirmed", "latency_ms"} {
+		if _, ok := values[key]; !ok {
+			t.Fatalf("missing field %v", key)
+		}
+	}
+	if values["num"] != int64(10) || values["witness"] != "witness0" || values["txs"] != 2 || values["confirmed"] != int64(8) {
+		t.Fatalf("unexpected fields %v", values)
+	}
+}
+
+func TestStopTwice(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+	mockP2PService := p2p_mock.NewMockService(mockController)
+	mockP2PService.EXPECT().Register(gomock.Any(), gomock.Any()).Return(make(chan p2p.IncomingMessage)).AnyTimes()
+	mockP2PService.EXPECT().Register(gomock.Any(), gomock.Any(), gomock.Any()).Return(make(chan p2p.IncomingMessage)).AnyTimes()
+	mockP2PService.EXPECT().Register(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(make(chan p2p.IncomingMessage)).AnyTimes()
+
+	s, err := synchro.New(mockP2PService, nil, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &PoB{
+		sync:       s,
+		exitSignal: make(chan struct{}),
+		stopOnce:   new(sync.Once),
+		wg:         new(sync.WaitGroup),
+	}
+	p.Stop()
+	p.Stop()
+}
+
+func TestBlockNumPerWitness(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+	baseVariable := core_mock.NewMockBaseVariable(mockController)
+	baseVariable.EXPECT().Continuous().Return(6).AnyTimes()
+
+	conf := &common.Config{}
+	baseVariable.EXPECT().Config().Return(conf).AnyTimes()
+
+	num, err := blockNumPerWitness(baseVariable)
+	if err != nil || num != 6 {
+		t.Fatalf("expect default block num 6, got %v %v", num, err)
+	}
+
+	conf.Consensus = &common.ConsensusConfig{BlockNumPerWitness: 3}
+	num, err = blockNumPerWitness(baseVariable)
+	if err != nil || num != 3 {
+		t.Fatalf("expect configured block num 3, got %v %v", num, err)
+	}
+	p := &PoB{blockNumPerWitness: num}
+	if err := p.checkSerialNum(2); err != nil {
+		t.Fatalf("serial num 2 should be accepted, got %v", err)
+	}
+	if err := p.checkSerialNum(3); err != errOutOfLimit {
+		t.Fatalf("serial num 3 should be out of limit, got %v", err)
+	}
+
+	conf.Consensus.BlockNumPerWitness = -1
+	if _, err := blockNumPerWitness(baseVariable); err != errBlockNum {
+		t.Fatalf("expect %v, got %v", errBlockNum, err)
+	}
+}
+
+func TestCheckSlotTiming(t *testing.T) {
+	if err := checkSlotTiming(6); err != nil {
+		t.Fatalf("default timing should be valid, got %v", err)
+	}
+	if err := checkSlotTiming(7); err == nil {
+		t.Fatalf("7 blocks of %v should not fit in a slot", subSlotTime)
+	}
+
+	oldGenBlockTime := genBlockTime
+	genBlockTime = subSlotTime
+	defer func() { genBlockTime = oldGenBlockTime }()
+	if err := checkSlotTiming(6); err == nil {
+		t.Fatalf("gen block time equal to sub slot time should be invalid")
+	}
+}
+
+func TestGenLimitTime(t *testing.T) {
+	p := &PoB{blockNumPerWitness: 6}
+	slotStart := time.Unix(common.SlotLength*1000, 0)
+	if got := p.remainingSlotTime(slotStart.Add(time.Second)); got != time.Duration(common.SlotLength)*time.Second-time.Second {
+		t.Fatalf("remaining slot time %v is wrong", got)
+	}
+	if got := p.genLimitTime(0, slotStart); got != genBlockTime {
+		t.Fatalf("limit time of the first block is %v, expected %v", got, genBlockTime)
+	}
+	if got := p.genLimitTime(5, slotStart); got != last2GenBlockTime {
+		t.Fatalf("limit time of the last block is %v, expected %v", got, last2GenBlockTime)
+	}
+
+	slotEnd := slotStart.Add(time.Duration(common.SlotLength) * time.Second)
+	if got := p.genLimitTime(0, slotEnd.Add(-100*time.Millisecond)); got != 100*time.Millisecond {
+		t.Fatalf("limit time is %v, expected the remaining slot time 100ms", got)
+	}
+	if got := p.genLimitTime(5, slotEnd.Add(-10*time.Millisecond)); got != 10*time.Millisecond {
+		t.Fatalf("limit time is %v, expected the remaining slot time 10ms", got)
+	}
+}
+
+func TestSortedChildren(t *testing.T) {
+	parent := blockcache.NewBCN(nil, &block.Block{Head: &block.BlockHead{Number: 1}})
+	for i := int64(0); i < 5; i++ {
+		blk := &block.Block{Head: &block.BlockHead{Number: 2, Time: i}}
+		blk.CalculateHeadHash()
+		blockcache.NewBCN(parent, blk)
+	}
+	children := sortedChildren(parent)
+	if len(children) != 5 {
+		t.Fatalf("expect 5 children, got %v", len(children))
+	}
+	for i := 1; i < len(children); i++ {
+		if bytes.Compare(children[i-1].HeadHash(), children[i].HeadHash()) >= 0 {
+			t.Fatalf("children are not sorted by hash at %v", i)
+		}
+	}
+	for i := 0; i < 10; i++ {
+		again := sortedChildren(parent)
+		for j := range children {
+			if again[j] != children[j] {
+				t.Fatalf("order of children changed at %v", j)
+			}
+		}
+	}
+}
+
+func TestGenNilPending(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+	mockP2PService := p2p_mock.NewMockService(mockController)
+	mockTxPool := txpool_mock.NewMockTxPool(mockController)
+
+	p := &PoB{
+		txPool:             mockTxPool,
+		broadcaster:        newBroadcaster(mockP2PService, 4),
+		blockNumPerWitness: 6,
+	}
+	head := blockcache.NewBCN(nil, &block.Block{Head: &block.BlockHead{}})
+	p.gen(0, nil, head)
+	p.gen(1, txpool.NewSortedTxMap(), nil)
+	p.gen(2, nil, nil)
+	if p.broadcaster.Len() != 0 {
+		t.Fatalf("expect no broadcast, got %v messages", p.broadcaster.Len())
+	}
+}
+
+func TestWitnessSets(t *testing.T) {
+	node := blockcache.NewBCN(nil, &block.Block{Head: &block.BlockHead{}})
+	node.SetActive([]string{"a", "b", "c"})
+	node.SetPending([]string{"b", "c", "d"})
+
+	active, pending := witnessSets(node)
+	if !common.StringSliceEqual(active, []string{"a", "b", "c"}) || !common.StringSliceEqual(pending, []string{"b", "c", "d"}) {
+		t.Fatalf("expect active [a b c] and pending [b c d], got %v %v", active, pending)
+	}
+	active[0] = "x"
+	pending[0] = "x"
+	if node.Active()[0] != "a" || node.Pending()[0] != "b" {
+		t.Fatalf("witness sets should be copies, got %v %v", node.Active(), node.Pending())
+	}
+}
+
+func TestWarmUp(t *testing.T) {
+	dir, err := ioutil.TempDir("", "warmup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	produceDB, err := db.NewMVCCDB(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer produceDB.Close()
+
+	headBlk := &block.Block{
+		Head:     &block.BlockHead{Number: 1, Time: 1},
+		Txs:      []*tx.Tx{},
+		Receipts: []*tx.TxReceipt{},
+	}
+	headBlk.CalculateHeadHash()
+	produceDB.Put("state", "key", "value")
+	produceDB.Commit(string(headBlk.HeadHash()))
+	produceDB.Put("state", "key", "dirty")
+	produceDB.Commit("other")
+
+	head := blockcache.NewBCN(nil, headBlk)
+	head.SetActive([]string{"witness0"})
+
+	p := &PoB{produceDB: produceDB}
+	if err := p.warmUp(head); err != nil {
+		t.Fatal(err)
+	}
+	if tag := produceDB.CurrentTag(); tag != string(headBlk.HeadHash()) {
+		t.Fatalf("expect produceDB at head state, got %v", tag)
+	}
+
+	unknown := &block.Block{Head: &block.BlockHead{Number: 2, Time: 2}}
+	unknown.CalculateHeadHash()
+	if err := p.warmUp(blockcache.NewBCN(nil, unknown)); err != errWarmUp {
+		t.Fatalf("expect %v, got %v", errWarmUp, err)
+	}
+}
+
+func TestSeenBlockExpired(t *testing.T) {
+	now := time.Now()
+	node := blockcache.NewBCN(nil, &block.Block{Head: &block.BlockHead{Time: now.Add(-time.Minute).UnixNano()}})
+	node.Type = blockcache.Single
+
+	if seenBlockExpired(node, now, 0) {
+		t.Fatal("seen block should not expire when the expiration is 0")
+	}
+	if seenBlockExpired(node, now, time.Minute) {
+		t.Fatal("seen block should not expire at the expiration")
+	}
+	if !seenBlockExpired(node, now.Add(time.Nanosecond), time.Minute) {
+		t.Fatal("seen block should expire after the expiration")
+	}
+	node.Type = blockcache.Linked
+	if seenBlockExpired(node, now.Add(time.Hour), time.Minute) {
+		t.Fatal("linked block should never expire")
+	}
+}
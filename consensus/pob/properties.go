@@ -1,13 +1,41 @@
 package pob
 
 import (
+	"errors"
+	"fmt"
+	"time"
+
 	"github.com/iost-official/go-iost/common"
 )
 
 var (
 	second2nanosecond int64 = 1000000000
+
+	errGenesisTime = errors.New("genesis time is in the future")
 )
 
+// slotClock maps the unix times to the slots, the zero value counts the slots from the unix epoch.
+type slotClock struct {
+	// epoch is the unix time in seconds at which slot 0 starts.
+	epoch int64
+}
+
+// newSlotClock returns the clock whose slot 0 starts at the RFC3339 genesis time, "" means the unix epoch.
+// A genesis time later than now is rejected.
+func newSlotClock(genesisTime string, now time.Time) (slotClock, error) {
+	if genesisTime == "" {
+		return slotClock{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, genesisTime)
+	if err != nil {
+		return slotClock{}, fmt.Errorf("invalid genesis time %v: %v", genesisTime, err)
+	}
+	if t.After(now) {
+		return slotClock{}, fmt.Errorf("%v: %v", errGenesisTime, genesisTime)
+	}
+	return slotClock{epoch: t.Unix()}, nil
+}
+
 func isWitness(w string, witnessList []string) bool {
 	for _, v := range witnessList {
 		if v == w {
@@ -17,25 +45,58 @@ func isWitness(w string, witnessList []string) bool {
 	return false
 }
 
-func witnessOfNanoSec(nanosec int64, witnessList []string) string {
-	return witnessOfSec(nanosec/second2nanosecond, witnessList)
+// witnessSource is the block whose active witness list schedules the slots of its children,
+// a block cache node or a copy of its witness list.
+type witnessSource interface {
+	Active() []string
 }
 
-func witnessOfSec(sec int64, witnessList []string) string {
-	return witnessOfSlot(sec/common.SlotLength, witnessList)
+// witnessOfNanoSec returns the witness scheduled by source to produce the child block at the unix time nanosec.
+// A block must be checked against the list of its parent, not of the current head, so that all nodes
+// agree on the producer of a slot when the active list changes across a fork.
+func (c slotClock) witnessOfNanoSec(nanosec int64, source witnessSource) string {
+	return c.witnessOfSec(nanosec/second2nanosecond, source)
+}
+
+func (c slotClock) witnessOfSec(sec int64, source witnessSource) string {
+	return witnessOfSlot(c.slotOfSec(sec), source.Active())
 }
 
 func witnessOfSlot(slot int64, witnessList []string) string {
-	index := slot % int64(len(witnessList))
-	witness := witnessList[index]
-	return witness
+	return WitnessAt(slot, witnessList)
 }
 
-func slotOfSec(sec int64) int64 {
-	return sec / common.SlotLength
+// WitnessAt returns the scheduled witness of the slot in the given witness list.
+// The result depends only on the slot and the order of the list, so nodes using the
+// witness list of the same parent block agree on the producer. It returns "" if the list is empty.
+func WitnessAt(slot int64, list []string) string {
+	if len(list) == 0 {
+		return ""
+	}
+	index := slot % int64(len(list))
+	if index < 0 {
+		index += int64(len(list))
+	}
+	return list[index]
 }
 
-func timeUntilNextSchedule(timeSec int64) int64 {
-	currentSlot := timeSec / (second2nanosecond * common.SlotLength)
-	return (currentSlot+1)*second2nanosecond*common.SlotLength - timeSec
+// slotOfSec returns the slot of the unix time sec, counted from the epoch of the clock.
+func (c slotClock) slotOfSec(sec int64) int64 {
+	return floorDiv(sec-c.epoch, common.SlotLength)
+}
+
+// timeUntilNextSchedule returns the nanoseconds from the unix time timeSec in nanoseconds to the next slot.
+func (c slotClock) timeUntilNextSchedule(timeSec int64) int64 {
+	slotNano := second2nanosecond * common.SlotLength
+	epochNano := c.epoch * second2nanosecond
+	currentSlot := floorDiv(timeSec-epochNano, slotNano)
+	return epochNano + (currentSlot+1)*slotNano - timeSec
+}
+
+func floorDiv(a, b int64) int64 {
+	q := a / b
+	if a%b != 0 && a < 0 {
+		q--
+	}
+	return q
 }
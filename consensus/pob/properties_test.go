@@ -0,0 +1,69 @@
+package pob
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iost-official/go-iost/common"
+	"github.com/iost-official/go-iost/core/block"
+	"github.com/iost-official/go-iost/core/blockcache"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestWitnessAt(t *testing.T) {
+	Convey("Test of WitnessAt", t, func() {
+		list := []string{"w0", "w1", "w2"}
+		So(WitnessAt(0, list), ShouldEqual, "w0")
+		So(WitnessAt(1, list), ShouldEqual, "w1")
+		So(WitnessAt(5, list), ShouldEqual, "w2")
+		So(WitnessAt(-1, list), ShouldEqual, "w2")
+		So(WitnessAt(7, []string{"w3", "w4"}), ShouldEqual, "w4")
+		So(WitnessAt(7, nil), ShouldEqual, "")
+
+		sec := int64(1540000000)
+		source := &blockcache.WitnessList{ActiveWitnessList: list}
+		var clock slotClock
+		So(clock.witnessOfSec(sec, source), ShouldEqual, WitnessAt(sec/common.SlotLength, list))
+		So(clock.witnessOfNanoSec(sec*second2nanosecond, source), ShouldEqual, clock.witnessOfSec(sec, source))
+
+		Convey("each fork is scheduled by its own parent", func() {
+			forkA := blockcache.NewBCN(nil, &block.Block{Head: &block.BlockHead{Number: 10}})
+			forkA.SetActive([]string{"w0", "w1", "w2"})
+			forkB := blockcache.NewBCN(nil, &block.Block{Head: &block.BlockHead{Number: 10}})
+			forkB.SetActive([]string{"w3", "w4"})
+			nanosec := 7 * common.SlotLength * second2nanosecond
+			So(clock.witnessOfNanoSec(nanosec, forkA), ShouldEqual, "w1")
+			So(clock.witnessOfNanoSec(nanosec, forkB), ShouldEqual, "w4")
+		})
+	})
+}
+
+func TestSlotEpoch(t *testing.T) {
+	Convey("Test of slot epoch", t, func() {
+		genesis := "2019-02-25T12:00:00Z"
+		gt, _ := time.Parse(time.RFC3339, genesis)
+		clock, err := newSlotClock(genesis, gt.Add(time.Hour))
+		So(err, ShouldBeNil)
+		So(clock.epoch, ShouldEqual, gt.Unix())
+
+		sec := gt.Unix()
+		list := &blockcache.WitnessList{ActiveWitnessList: []string{"w0", "w1", "w2"}}
+		So(clock.slotOfSec(sec), ShouldEqual, 0)
+		So(clock.slotOfSec(sec+common.SlotLength-1), ShouldEqual, 0)
+		So(clock.slotOfSec(sec+common.SlotLength), ShouldEqual, 1)
+		So(clock.slotOfSec(sec-1), ShouldEqual, -1)
+		So(clock.witnessOfNanoSec(gt.UnixNano(), list), ShouldEqual, "w0")
+		So(clock.witnessOfNanoSec(gt.UnixNano()+common.SlotLength*second2nanosecond, list), ShouldEqual, "w1")
+		So(clock.timeUntilNextSchedule(gt.UnixNano()), ShouldEqual, common.SlotLength*second2nanosecond)
+		So(clock.timeUntilNextSchedule(gt.UnixNano()-1), ShouldEqual, 1)
+
+		_, err = newSlotClock("2019-02-25T12:00:00Z", gt.Add(-time.Second))
+		So(err, ShouldNotBeNil)
+		_, err = newSlotClock("not a time", gt)
+		So(err, ShouldNotBeNil)
+
+		clock, err = newSlotClock("", gt)
+		So(err, ShouldBeNil)
+		So(clock.slotOfSec(sec), ShouldEqual, sec/common.SlotLength)
+	})
+}
@@ -0,0 +1,96 @@
+package pob
+
+import (
+	"sync"
+
+	"github.com/iost-official/go-iost/core/block"
+	"github.com/iost-official/go-iost/core/tx"
+	"github.com/iost-official/go-iost/ilog"
+)
+
+// receiptChSize is the size of a receipt subscriber's read channel.
+var receiptChSize = 100
+
+type receiptSubscription struct {
+	contractID string
+	c          chan *tx.TxReceipt
+}
+
+// receiptHub streams the receipts of linked blocks to the subscribers.
+type receiptHub struct {
+	mu   sync.RWMutex
+	subs []*receiptSubscription
+}
+
+// SubscribeReceipts returns a channel of the receipts of txs calling the contract, as their blocks are linked.
+// The channel is closed if the subscriber falls receiptChSize receipts behind, so no receipt is missed silently.
+func (p *PoB) SubscribeReceipts(contractID string) <-chan *tx.TxReceipt {
+	return p.receipts.subscribe(contractID)
+}
+
+// UnsubscribeReceipts removes and closes a channel returned by SubscribeReceipts.
+func (p *PoB) UnsubscribeReceipts(c <-chan *tx.TxReceipt) {
+	p.receipts.unsubscribe(c)
+}
+
+func (rh *receiptHub) subscribe(contractID string) <-chan *tx.TxReceipt {
+	sub := &receiptSubscription{
+		contractID: contractID,
+		c:          make(chan *tx.TxReceipt, receiptChSize),
+	}
+	rh.mu.Lock()
+	rh.subs = append(rh.subs, sub)
+	rh.mu.Unlock()
+	return sub.c
+}
+
+func (rh *receiptHub) unsubscribe(c <-chan *tx.TxReceipt) {
+	rh.mu.Lock()
+	defer rh.mu.Unlock()
+	for i, sub := range rh.subs {
+		if sub.c == c {
+			close(sub.c)
+			rh.subs = append(rh.subs[:i], rh.subs[i+1:]...)
+			return
+		}
+	}
+}
+
+// publish sends each receipt of the block to the subscribers whose contract is called by its tx.
+// A subscriber which doesn't keep up is closed instead of blocking the chain.
+func (rh *receiptHub) publish(blk *block.Block) {
+	rh.mu.Lock()
+	defer rh.mu.Unlock()
+	if len(rh.subs) == 0 {
+		return
+	}
+	for i, t := range blk.Txs {
+		if i >= len(blk.Receipts) {
+			break
+		}
+		subs := rh.subs[:0]
+		for _, sub := range rh.subs {
+			if !txCallsContract(t, sub.contractID) {
+				subs = append(subs, sub)
+				continue
+			}
+			select {
+			case sub.c <- blk.Receipts[i]:
+				subs = append(subs, sub)
+			default:
+				ilog.Warnf("receipt subscriber is lagging, closing it. contract=%v, blockNum=%v", sub.contractID, blk.Head.Number)
+				close(sub.c)
+			}
+		}
+		rh.subs = subs
+	}
+}
+
+func txCallsContract(t *tx.Tx, contractID string) bool {
+	for _, a := range t.Actions {
+		if a.Contract == contractID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package pob
+
+import (
+	"testing"
+
+	"github.com/iost-official/go-iost/core/block"
+	"github.com/iost-official/go-iost/core/tx"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+func TestSubscribeReceipts(t *testing.T) {
+	convey.Convey("Test of SubscribeReceipts", t, func() {
+		p := &PoB{}
+		tokenCh := p.SubscribeReceipts("token.iost")
+		voteCh := p.SubscribeReceipts("vote.iost")
+
+		blk := &block.Block{Head: &block.BlockHead{Number: 1}}
+		for i, con := range []string{"token.iost", "vote.iost", "token.iost", "gas.iost"} {
+			t := tx.NewTx([]*tx.Action{{Contract: con, ActionName: "a", Data: "[]"}}, nil, 100000, 100, int64(i), 0, 0)
+			blk.Txs = append(blk.Txs, t)
+			blk.Receipts = append(blk.Receipts, &tx.TxReceipt{TxHash: t.Hash()})
+		}
+		p.receipts.publish(blk)
+
+		convey.So(len(tokenCh), convey.ShouldEqual, 2)
+		convey.So(<-tokenCh, convey.ShouldEqual, blk.Receipts[0])
+		convey.So(<-tokenCh, convey.ShouldEqual, blk.Receipts[2])
+		convey.So(len(voteCh), convey.ShouldEqual, 1)
+		convey.So(<-voteCh, convey.ShouldEqual, blk.Receipts[1])
+
+		p.UnsubscribeReceipts(voteCh)
+		_, ok := <-voteCh
+		convey.So(ok, convey.ShouldBeFalse)
+
+		for i := 0; i <= receiptChSize/2; i++ {
+			p.receipts.publish(blk)
+		}
+		convey.So(len(p.receipts.subs), convey.ShouldEqual, 0)
+		for i := 0; i < receiptChSize; i++ {
+			<-tokenCh
+		}
+		_, ok = <-tokenCh
+		convey.So(ok, convey.ShouldBeFalse)
+	})
+}
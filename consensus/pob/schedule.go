@@ -0,0 +1,33 @@
+package pob
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ScheduledSlot is a sub-slot of the witness schedule, Time is the start of the sub-slot in nanoseconds.
+type ScheduledSlot struct {
+	Time    int64  `json:"time"`
+	Witness string `json:"witness"`
+}
+
+// ScheduleJSON returns the JSON of the next slots sub-slots and their witnesses,
+// scheduled by the active witness list of the head.
+func (p *PoB) ScheduleJSON(slots int) ([]byte, error) {
+	return json.Marshal(p.blockConf.clock.schedule(time.Now().UnixNano(), p.blockCache.Head(), slots))
+}
+
+// schedule returns the slots sub-slots starting after nanosec and their witnesses scheduled by source.
+func (c slotClock) schedule(nanosec int64, source witnessSource, slots int) []*ScheduledSlot {
+	if slots < 0 {
+		slots = 0
+	}
+	subSlot := int64(subSlotTime)
+	start := (nanosec/subSlot + 1) * subSlot
+	ret := make([]*ScheduledSlot, 0, slots)
+	for i := 0; i < slots; i++ {
+		t := start + int64(i)*subSlot
+		ret = append(ret, &ScheduledSlot{Time: t, Witness: c.witnessOfNanoSec(t, source)})
+	}
+	return ret
+}
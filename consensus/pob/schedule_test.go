@@ -0,0 +1,32 @@
+package pob
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/iost-official/go-iost/core/blockcache"
+)
+
+func TestSchedule(t *testing.T) {
+	list := &blockcache.WitnessList{ActiveWitnessList: []string{"w0", "w1"}}
+	b, err := json.Marshal(slotClock{}.schedule(2999999999, list, 7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `[{"time":3000000000,"witness":"w1"},{"time":3500000000,"witness":"w1"},` +
+		`{"time":4000000000,"witness":"w1"},{"time":4500000000,"witness":"w1"},` +
+		`{"time":5000000000,"witness":"w1"},{"time":5500000000,"witness":"w1"},` +
+		`{"time":6000000000,"witness":"w0"}]`
+	if string(b) != expected {
+		t.Fatalf("schedule json %s, expected %s", b, expected)
+	}
+
+	b, _ = json.Marshal(slotClock{}.schedule(3000000000, list, 1))
+	if string(b) != `[{"time":3500000000,"witness":"w1"}]` {
+		t.Fatalf("schedule should start at the next sub-slot, got %s", b)
+	}
+	b, _ = json.Marshal(slotClock{}.schedule(3000000000, list, 0))
+	if string(b) != `[]` {
+		t.Fatalf("empty schedule json %s", b)
+	}
+}
@@ -0,0 +1,37 @@
+package pob
+
+import (
+	"encoding/json"
+
+	"github.com/iost-official/go-iost/common"
+	"github.com/iost-official/go-iost/core/block"
+	"github.com/iost-official/go-iost/verifier"
+)
+
+// signHashOf returns the hash function of the payload signed by the witness,
+// which is recorded in the info of the block head. Blocks without it are signed with sha3.
+func signHashOf(head *block.BlockHead) (common.SignHash, error) {
+	var info verifier.Info
+	if err := json.Unmarshal(head.Info, &info); err != nil {
+		return common.SignHashSha3, nil
+	}
+	return common.ParseSignHash(info.SignHash)
+}
+
+// setSignHash records the sign hash in the info of the block head. Sha3 isn't recorded,
+// so the blocks signed with it are the same as before.
+func setSignHash(head *block.BlockHead, h common.SignHash) {
+	if h == common.SignHashSha3 {
+		return
+	}
+	var info verifier.Info
+	if err := json.Unmarshal(head.Info, &info); err != nil {
+		info = verifier.Info{}
+	}
+	info.SignHash = h.String()
+	buf, err := json.Marshal(info)
+	if err != nil {
+		panic(err)
+	}
+	head.Info = buf
+}
@@ -0,0 +1,89 @@
+package pob
+
+import (
+	"time"
+
+	"github.com/iost-official/go-iost/consensus/synchro"
+	"github.com/iost-official/go-iost/core/block"
+)
+
+var defaultVerifyPipelineDepth = 4
+
+// preparedBlock is an incoming block whose stateless checks are running or done.
+type preparedBlock struct {
+	msg  *synchro.BlockMessage
+	err  error
+	done chan struct{}
+}
+
+// verifyPipeline runs the stateless checks of up to depth incoming blocks concurrently,
+// while the blocks are applied one by one in the order they arrived.
+type verifyPipeline struct {
+	depth   int
+	prepare func(*synchro.BlockMessage) error
+	apply   func(*synchro.BlockMessage, error)
+}
+
+func newVerifyPipeline(depth int, prepare func(*synchro.BlockMessage) error, apply func(*synchro.BlockMessage, error)) *verifyPipeline {
+	if depth < 1 {
+		depth = 1
+	}
+	return &verifyPipeline{
+		depth:   depth,
+		prepare: prepare,
+		apply:   apply,
+	}
+}
+
+// run consumes the incoming blocks until exit is closed.
+func (vp *verifyPipeline) run(in <-chan *synchro.BlockMessage, exit <-chan struct{}) {
+	queue := make(chan *preparedBlock, vp.depth-1)
+	go func() {
+		defer close(queue)
+		for {
+			select {
+			case msg := <-in:
+				pb := &preparedBlock{msg: msg, done: make(chan struct{})}
+				go func() {
+					pb.err = vp.prepare(pb.msg)
+					close(pb.done)
+				}()
+				select {
+				case queue <- pb:
+				case <-exit:
+					return
+				}
+			case <-exit:
+				return
+			}
+		}
+	}()
+
+	for pb := range queue {
+		select {
+		case <-exit:
+			return
+		default:
+		}
+		select {
+		case <-pb.done:
+		case <-exit:
+			return
+		}
+		vp.apply(pb.msg, pb.err)
+	}
+}
+
+// prepareBlock does the checks of a block which don't depend on the chain state.
+func prepareBlock(blk *block.Block, conf *blockConfig) error {
+	if err := checkBlockTime(blk.Head, time.Now(), conf.blockTimeGap()); err != nil {
+		return err
+	}
+	if err := verifyBasics(blk, blk.Sign); err != nil {
+		return err
+	}
+	for _, t := range blk.Txs {
+		t.Hash()
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package pob
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/iost-official/go-iost/account"
+	"github.com/iost-official/go-iost/consensus/cverifier"
+	"github.com/iost-official/go-iost/consensus/synchro"
+	"github.com/iost-official/go-iost/core/block"
+	"github.com/iost-official/go-iost/core/tx"
+	"github.com/iost-official/go-iost/crypto"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+func genSignedBlocks(b testing.TB, blockCnt, txCnt int) []*synchro.BlockMessage {
+	acc, err := account.NewKeyPair(nil, crypto.Ed25519)
+	if err != nil {
+		b.Fatal(err)
+	}
+	msgs := make([]*synchro.BlockMessage, 0, blockCnt)
+	for i := 0; i < blockCnt; i++ {
+		blk := &block.Block{
+			Head: &block.BlockHead{
+				Number:  int64(i + 1),
+				Witness: acc.ReadablePubkey(),
+				Time:    time.Now().UnixNano(),
+			},
+		}
+		for j := 0; j < txCnt; j++ {
+			t := tx.NewTx([]*tx.Action{{Contract: "token.iost", ActionName: "transfer", Data: "[]"}}, nil, 100000, 100, time.Now().UnixNano()+int64(j), 0, 0)
+			blk.Txs = append(blk.Txs, t)
+			blk.Receipts = append(blk.Receipts, &tx.TxReceipt{TxHash: t.Hash()})
+		}
+		blk.Head.TxMerkleHash = blk.CalculateTxMerkleHash()
+		blk.CalculateHeadHash()
+		blk.Sign = acc.Sign(blk.HeadHash())
+		msgs = append(msgs, &synchro.BlockMessage{Blk: blk})
+	}
+	return msgs
+}
+
+func runPipeline(msgs []*synchro.BlockMessage, depth int, prepare func(*synchro.BlockMessage) error, apply func(*synchro.BlockMessage, error)) {
+	in := make(chan *synchro.BlockMessage, len(msgs))
+	for _, msg := range msgs {
+		in <- msg
+	}
+	exit := make(chan struct{})
+	applied := 0
+	vp := newVerifyPipeline(depth, prepare, func(msg *synchro.BlockMessage, err error) {
+		apply(msg, err)
+		applied++
+		if applied == len(msgs) {
+			close(exit)
+		}
+	})
+	vp.run(in, exit)
+}
+
+func TestVerifyPipelineOrder(t *testing.T) {
+	convey.Convey("Test of verifyPipeline order", t, func() {
+		msgs := make([]*synchro.BlockMessage, 0)
+		for i := 0; i < 10; i++ {
+			msgs = append(msgs, &synchro.BlockMessage{Blk: &block.Block{Head: &block.BlockHead{Number: int64(i)}}})
+		}
+		errBad := errors.New("bad block")
+		prepare := func(msg *synchro.BlockMessage) error {
+			// Later blocks finish preparing first.
+			time.Sleep(time.Duration(10-msg.Blk.Head.Number) * time.Millisecond)
+			if msg.Blk.Head.Number == 5 {
+				return errBad
+			}
+			return nil
+		}
+
+		var order []int64
+		errs := make(map[int64]error)
+		runPipeline(msgs, 4, prepare, func(msg *synchro.BlockMessage, err error) {
+			order = append(order, msg.Blk.Head.Number)
+			errs[msg.Blk.Head.Number] = err
+		})
+
+		convey.So(order, convey.ShouldResemble, []int64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9})
+		convey.So(errs[5], convey.ShouldEqual, errBad)
+		convey.So(errs[4], convey.ShouldBeNil)
+	})
+}
+
+func TestPrepareBlock(t *testing.T) {
+	convey.Convey("Test of prepareBlock", t, func() {
+		msgs := genSignedBlocks(t, 2, 3)
+		convey.So(prepareBlock(msgs[0].Blk, &blockConfig{}), convey.ShouldBeNil)
+
+		msgs[1].Blk.Sign = msgs[0].Blk.Sign
+		convey.So(prepareBlock(msgs[1].Blk, &blockConfig{}), convey.ShouldEqual, errSignature)
+	})
+}
+
+func TestCheckBlockTime(t *testing.T) {
+	convey.Convey("Test of checkBlockTime", t, func() {
+		now := time.Now()
+		head := &block.BlockHead{Time: now.UnixNano() + cverifier.MaxBlockTimeGap}
+		convey.So(checkBlockTime(head, now, cverifier.MaxBlockTimeGap), convey.ShouldBeNil)
+		head.Time++
+		convey.So(checkBlockTime(head, now, cverifier.MaxBlockTimeGap), convey.ShouldEqual, cverifier.ErrFutureBlock)
+		conf := &blockConfig{maxBlockTimeGap: 2 * cverifier.MaxBlockTimeGap}
+		convey.So(checkBlockTime(head, now, conf.blockTimeGap()), convey.ShouldBeNil)
+
+		msgs := genSignedBlocks(t, 1, 1)
+		blk := msgs[0].Blk
+		acc, err := account.NewKeyPair(nil, crypto.Ed25519)
+		convey.So(err, convey.ShouldBeNil)
+		blk.Head.Witness = acc.ReadablePubkey()
+		blk.Head.Time = time.Now().Add(time.Hour).UnixNano()
+		blk.CalculateHeadHash()
+		blk.Sign = acc.Sign(blk.HeadHash())
+		convey.So(prepareBlock(blk, &blockConfig{}), convey.ShouldEqual, cverifier.ErrFutureBlock)
+	})
+}
+
+func benchmarkApply(msg *synchro.BlockMessage, err error) {
+	msg.Blk.CalculateTxMerkleHash()
+}
+
+func BenchmarkVerifySerial(b *testing.B) {
+	msgs := genSignedBlocks(b, 100, 50)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, msg := range msgs {
+			benchmarkApply(msg, prepareBlock(msg.Blk, &blockConfig{}))
+		}
+	}
+}
+
+func BenchmarkVerifyPipeline(b *testing.B) {
+	msgs := genSignedBlocks(b, 100, 50)
+	prepare := func(msg *synchro.BlockMessage) error {
+		return prepareBlock(msg.Blk, &blockConfig{})
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		runPipeline(msgs, defaultVerifyPipelineDepth, prepare, benchmarkApply)
+	}
+}
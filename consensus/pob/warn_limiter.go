@@ -0,0 +1,73 @@
+package pob
+
+import (
+	"sync"
+	"time"
+
+	"github.com/iost-official/go-iost/ilog"
+)
+
+// blockWarnInterval is the min interval between the logs of the same block verification warning.
+var blockWarnInterval = 10 * time.Second
+
+const maxWarnKeys = 1024
+
+// warnLimiter logs a warning at most once per interval for each key,
+// and counts the warnings suppressed in between.
+type warnLimiter struct {
+	interval time.Duration
+
+	mu         sync.Mutex
+	last       map[string]time.Time
+	suppressed map[string]int64
+}
+
+func newWarnLimiter(interval time.Duration) *warnLimiter {
+	return &warnLimiter{
+		interval:   interval,
+		last:       make(map[string]time.Time),
+		suppressed: make(map[string]int64),
+	}
+}
+
+// allow returns whether the warning of key should be logged at now,
+// and the number of its warnings suppressed since it was logged last time.
+func (l *warnLimiter) allow(key string, now time.Time) (bool, int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if last, ok := l.last[key]; ok && now.Sub(last) < l.interval {
+		l.suppressed[key]++
+		metricsSuppressedWarnCount.Add(1, nil)
+		return false, 0
+	}
+	if len(l.last) >= maxWarnKeys {
+		l.prune(now)
+	}
+	n := l.suppressed[key]
+	delete(l.suppressed, key)
+	l.last[key] = now
+	return true, n
+}
+
+// prune drops the keys which are not logged within the interval.
+func (l *warnLimiter) prune(now time.Time) {
+	for key, last := range l.last {
+		if now.Sub(last) >= l.interval {
+			delete(l.last, key)
+			delete(l.suppressed, key)
+		}
+	}
+}
+
+// Warnf logs the warning unless one of the same key was logged within the interval.
+func (l *warnLimiter) Warnf(key string, format string, v ...interface{}) {
+	ok, n := l.allow(key, time.Now())
+	if !ok {
+		return
+	}
+	if n > 0 {
+		format += ", %v similar warnings suppressed"
+		v = append(v, n)
+	}
+	ilog.Warnf(format, v...)
+}
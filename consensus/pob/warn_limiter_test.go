@@ -0,0 +1,61 @@
+package pob
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+type counterRecorder struct {
+	n float64
+}
+
+func (c *counterRecorder) Add(v float64, tagkv map[string]string) error {
+	c.n += v
+	return nil
+}
+
+func TestWarnLimiter(t *testing.T) {
+	recorder := &counterRecorder{}
+	origin := metricsSuppressedWarnCount
+	metricsSuppressedWarnCount = recorder
+	defer func() { metricsSuppressedWarnCount = origin }()
+
+	l := newWarnLimiter(10 * time.Second)
+	now := time.Unix(1000, 0)
+	if ok, n := l.allow("new/peer1/wrong witness", now); !ok || n != 0 {
+		t.Fatalf("allow = %v, %v, want the first warning logged", ok, n)
+	}
+	for i := 1; i <= 5; i++ {
+		if ok, _ := l.allow("new/peer1/wrong witness", now.Add(time.Duration(i)*time.Second)); ok {
+			t.Fatalf("repeated warning %v within the interval is logged", i)
+		}
+	}
+	if recorder.n != 5 {
+		t.Fatalf("suppressed count = %v, want 5", recorder.n)
+	}
+	if ok, _ := l.allow("new/peer2/wrong witness", now.Add(time.Second)); !ok {
+		t.Fatal("warning of another peer is suppressed")
+	}
+	if ok, n := l.allow("new/peer1/wrong witness", now.Add(10*time.Second)); !ok || n != 5 {
+		t.Fatalf("allow = %v, %v, want logged with 5 suppressed after the interval", ok, n)
+	}
+	if ok, _ := l.allow("new/peer1/wrong witness", now.Add(11*time.Second)); ok {
+		t.Fatal("repeated warning within the new interval is logged")
+	}
+}
+
+func TestBlockWarnKey(t *testing.T) {
+	if k := blockWarnKey("new", "peer1", errWitness); k != "new/peer1/wrong witness" {
+		t.Fatalf("key = %v, want new/peer1/wrong witness", k)
+	}
+	k1 := blockWarnKey("sync", "peer1", fmt.Errorf("%v, tx: %v", errors.New("gas limit illegal"), "hash1"))
+	k2 := blockWarnKey("sync", "peer1", fmt.Errorf("%v, tx: %v", errors.New("gas limit illegal"), "hash2"))
+	if k1 != k2 || k1 != "sync/peer1/other" {
+		t.Fatalf("keys = %v, %v, want the errors with a varying message to share sync/peer1/other", k1, k2)
+	}
+	if blockWarnKey("sync", "peer2", errWitness) == blockWarnKey("sync", "peer1", errWitness) {
+		t.Fatal("peers share a key")
+	}
+}
@@ -1,6 +1,7 @@
 package synchro
 
 import (
+	"math/rand"
 	"sync"
 	"time"
 
@@ -20,6 +21,15 @@ const (
 	responseCachePurgeInterval = 1 * time.Minute
 )
 
+var (
+	blockRequestTimeout     = 2 * time.Second
+	blockRetryCheckInterval = 500 * time.Millisecond
+	defaultMaxBlockRetries  = 3
+)
+
+// DefaultIncomingBlockBufferSize is the default number of received blocks buffered in IncomingBlock.
+const DefaultIncomingBlockBufferSize = 1024
+
 // BlockMessage define a block from a neighbor node.
 type BlockMessage struct {
 	Blk     *block.Block
@@ -27,12 +37,27 @@ type BlockMessage struct {
 	From    string
 }
 
+type blockRequest struct {
+	hash     []byte
+	peerIDs  []p2p.PeerID
+	peerIdx  int
+	mtype    p2p.MessageType
+	attempts int
+	deadline time.Time
+	sentAt   time.Time
+}
+
 // blockSync is responsible for receiving neighbor's block and removing duplicate requests and responses.
 type blockSync struct {
 	p             p2p.Service
 	requestCache  *cache.Cache
 	responseCache *cache.Cache
 
+	mu         sync.Mutex
+	pending    map[string]*blockRequest
+	maxRetries int
+	stats      *peerStats
+
 	msgCh   chan p2p.IncomingMessage
 	blockCh chan *BlockMessage
 
@@ -40,14 +65,25 @@ type blockSync struct {
 	done   *sync.WaitGroup
 }
 
-func newBlockSync(p p2p.Service) *blockSync {
+func newBlockSync(p p2p.Service, bufferSize int) (*blockSync, error) {
+	if bufferSize <= 0 {
+		bufferSize = DefaultIncomingBlockBufferSize
+	}
+	msgCh, err := p2p.Subscribe(p, "block from other nodes", p2p.SyncBlockResponse, p2p.NewBlock)
+	if err != nil {
+		return nil, err
+	}
 	b := &blockSync{
 		p:             p,
 		requestCache:  cache.New(requestCacheExpiration, requestCachePurgeInterval),
 		responseCache: cache.New(responseCacheExpiration, responseCachePurgeInterval),
 
-		msgCh:   p.Register("block from other nodes", p2p.SyncBlockResponse, p2p.NewBlock),
-		blockCh: make(chan *BlockMessage, 1024),
+		pending:    make(map[string]*blockRequest),
+		maxRetries: defaultMaxBlockRetries,
+		stats:      newPeerStats(),
+
+		msgCh:   msgCh,
+		blockCh: make(chan *BlockMessage, bufferSize),
 
 		quitCh: make(chan struct{}),
 		done:   new(sync.WaitGroup),
@@ -56,7 +92,7 @@ func newBlockSync(p p2p.Service) *blockSync {
 	b.done.Add(1)
 	go b.controller()
 
-	return b
+	return b, nil
 }
 
 func (b *blockSync) Close() {
@@ -69,7 +105,18 @@ func (b *blockSync) IncomingBlock() <-chan *BlockMessage {
 	return b.blockCh
 }
 
-func (b *blockSync) RequestBlock(hash []byte, peerID p2p.PeerID, mtype p2p.MessageType) {
+func (b *blockSync) SetMaxRetries(n int) {
+	b.mu.Lock()
+	b.maxRetries = n
+	b.mu.Unlock()
+}
+
+// RequestBlock requests the block from one of the peers.
+// If no response arrives in time, the request is retried on the next peer with exponential backoff.
+func (b *blockSync) RequestBlock(hash []byte, peerIDs []p2p.PeerID, mtype p2p.MessageType) {
+	if len(peerIDs) == 0 {
+		return
+	}
 	// Filter duplicate requests in the short term
 	_, found := b.requestCache.Get(string(hash))
 	if found {
@@ -78,6 +125,22 @@ func (b *blockSync) RequestBlock(hash []byte, peerID p2p.PeerID, mtype p2p.Messa
 	}
 	b.requestCache.Set(string(hash), "", cache.DefaultExpiration)
 
+	req := &blockRequest{
+		hash:     hash,
+		peerIDs:  peerIDs,
+		peerIdx:  rand.Intn(len(peerIDs)),
+		mtype:    mtype,
+		deadline: time.Now().Add(blockRequestTimeout),
+		sentAt:   time.Now(),
+	}
+	b.mu.Lock()
+	b.pending[string(hash)] = req
+	b.mu.Unlock()
+
+	b.sendRequest(hash, peerIDs[req.peerIdx], mtype)
+}
+
+func (b *blockSync) sendRequest(hash []byte, peerID p2p.PeerID, mtype p2p.MessageType) {
 	// Historical issues cause number to be useless.
 	blockInfo := &msgpb.BlockInfo{
 		Hash:   hash,
@@ -90,6 +153,38 @@ func (b *blockSync) RequestBlock(hash []byte, peerID p2p.PeerID, mtype p2p.Messa
 	}
 
 	b.p.SendToPeer(peerID, msg, mtype, p2p.UrgentMessage)
+	b.stats.recordRequest(peerID)
+}
+
+// PeerStats returns the block sync statistics of the peers.
+func (b *blockSync) PeerStats() map[p2p.PeerID]PeerSyncStat {
+	return b.stats.snapshot()
+}
+
+func (b *blockSync) retryExpired(now time.Time) {
+	retries := make([]*blockRequest, 0)
+	b.mu.Lock()
+	for hash, req := range b.pending {
+		if now.Before(req.deadline) {
+			continue
+		}
+		if req.attempts >= b.maxRetries {
+			ilog.Warnf("Give up requesting block %v after %v retries.", common.Base58Encode(req.hash), req.attempts)
+			delete(b.pending, hash)
+			continue
+		}
+		req.attempts++
+		req.peerIdx = (req.peerIdx + 1) % len(req.peerIDs)
+		req.deadline = now.Add(blockRequestTimeout << uint(req.attempts))
+		req.sentAt = now
+		retries = append(retries, req)
+	}
+	b.mu.Unlock()
+
+	for _, req := range retries {
+		ilog.Debugf("Retry requesting block %v, attempt: %v", common.Base58Encode(req.hash), req.attempts)
+		b.sendRequest(req.hash, req.peerIDs[req.peerIdx], req.mtype)
+	}
 }
 
 func (b *blockSync) handleBlock(msg *p2p.IncomingMessage) {
@@ -105,6 +200,17 @@ func (b *blockSync) handleBlock(msg *p2p.IncomingMessage) {
 		return
 	}
 
+	var sentAt time.Time
+	b.mu.Lock()
+	if req, ok := b.pending[string(blk.HeadHash())]; ok && req.peerIDs[req.peerIdx] == msg.From() {
+		sentAt = req.sentAt
+	}
+	delete(b.pending, string(blk.HeadHash()))
+	b.mu.Unlock()
+	if msg.Type() == p2p.SyncBlockResponse {
+		b.stats.recordResponse(msg.From(), sentAt, time.Now())
+	}
+
 	// Discard the most recently received duplicate block by hash
 	_, found := b.responseCache.Get(string(blk.HeadHash()))
 	if found {
@@ -124,10 +230,14 @@ func (b *blockSync) handleBlock(msg *p2p.IncomingMessage) {
 }
 
 func (b *blockSync) controller() {
+	ticker := time.NewTicker(blockRetryCheckInterval)
+	defer ticker.Stop()
 	for {
 		select {
 		case msg := <-b.msgCh:
 			b.handleBlock(&msg)
+		case now := <-ticker.C:
+			b.retryExpired(now)
 		case <-b.quitCh:
 			b.done.Done()
 			return
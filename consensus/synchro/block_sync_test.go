@@ -0,0 +1,213 @@
+package synchro
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/iost-official/go-iost/account"
+	"github.com/iost-official/go-iost/core/block"
+	"github.com/iost-official/go-iost/crypto"
+	"github.com/iost-official/go-iost/p2p"
+	"github.com/iost-official/go-iost/p2p/mocks"
+)
+
+func TestBlockSyncRetry(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+	mockP2PService := p2p_mock.NewMockService(mockController)
+	mockP2PService.EXPECT().Register(gomock.Any(), gomock.Any(), gomock.Any()).Return(make(chan p2p.IncomingMessage))
+
+	b, err := newBlockSync(mockP2PService, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+
+	blk := &block.Block{
+		Head: &block.BlockHead{Number: 1},
+	}
+	blk.CalculateHeadHash()
+	acc, err := account.NewKeyPair(nil, crypto.Ed25519)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blk.Sign = acc.Sign(blk.HeadHash())
+	data, err := blk.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sent := make([]p2p.PeerID, 0)
+	mockP2PService.EXPECT().SendToPeer(gomock.Any(), gomock.Any(), p2p.SyncBlockRequest, gomock.Any()).Do(
+		func(peerID p2p.PeerID, _ []byte, _ p2p.MessageType, _ p2p.MessagePriority) {
+			sent = append(sent, peerID)
+		}).Times(3)
+
+	b.RequestBlock(blk.HeadHash(), []p2p.PeerID{"peerA", "peerB"}, p2p.SyncBlockRequest)
+	now := time.Now()
+	b.retryExpired(now.Add(blockRequestTimeout))
+	b.retryExpired(now.Add(4 * blockRequestTimeout))
+
+	if len(sent) != 3 {
+		t.Fatalf("expect 3 requests, got %v", len(sent))
+	}
+	if sent[0] == sent[1] || sent[1] == sent[2] {
+		t.Fatalf("retry should move to a different peer, got %v", sent)
+	}
+
+	b.handleBlock(p2p.NewIncomingMessage("peerA", data, p2p.SyncBlockResponse))
+	select {
+	case msg := <-b.IncomingBlock():
+		if string(msg.Blk.HeadHash()) != string(blk.HeadHash()) {
+			t.Fatal("unexpected block")
+		}
+	default:
+		t.Fatal("block should be delivered")
+	}
+
+	b.retryExpired(now.Add(time.Hour))
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.pending) != 0 {
+		t.Fatalf("pending requests should be cleared, got %v", len(b.pending))
+	}
+}
+
+func TestBlockSyncMaxRetries(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+	mockP2PService := p2p_mock.NewMockService(mockController)
+	mockP2PService.EXPECT().Register(gomock.Any(), gomock.Any(), gomock.Any()).Return(make(chan p2p.IncomingMessage))
+
+	b, err := newBlockSync(mockP2PService, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+	b.SetMaxRetries(1)
+
+	mockP2PService.EXPECT().SendToPeer(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(2)
+	b.RequestBlock([]byte("hash"), []p2p.PeerID{"peerA"}, p2p.NewBlockRequest)
+	now := time.Now()
+	b.retryExpired(now.Add(blockRequestTimeout))
+	b.retryExpired(now.Add(time.Hour))
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.pending) != 0 {
+		t.Fatalf("request should be dropped after max retries, got %v", len(b.pending))
+	}
+}
+
+func TestBlockSyncBufferSize(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+	mockP2PService := p2p_mock.NewMockService(mockController)
+	mockP2PService.EXPECT().Register(gomock.Any(), gomock.Any(), gomock.Any()).Return(make(chan p2p.IncomingMessage)).Times(2)
+
+	b, err := newBlockSync(mockP2PService, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+	if cap(b.IncomingBlock()) != 2 {
+		t.Fatalf("expect buffer size 2, got %v", cap(b.IncomingBlock()))
+	}
+
+	acc, err := account.NewKeyPair(nil, crypto.Ed25519)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msgs := make([]*p2p.IncomingMessage, 0)
+	for i := int64(1); i <= 3; i++ {
+		blk := &block.Block{
+			Head: &block.BlockHead{Number: i},
+		}
+		blk.CalculateHeadHash()
+		blk.Sign = acc.Sign(blk.HeadHash())
+		data, err := blk.Encode()
+		if err != nil {
+			t.Fatal(err)
+		}
+		msgs = append(msgs, p2p.NewIncomingMessage("peerA", data, p2p.NewBlock))
+	}
+
+	b.handleBlock(msgs[0])
+	b.handleBlock(msgs[1])
+	done := make(chan struct{})
+	go func() {
+		b.handleBlock(msgs[2])
+		close(done)
+	}()
+	select {
+	case <-done:
+		t.Fatal("the third block should wait for the full buffer")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if msg := <-b.IncomingBlock(); msg.Blk.Head.Number != 1 {
+		t.Fatalf("expect block 1, got %v", msg.Blk.Head.Number)
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("the third block should be buffered after a block is consumed")
+	}
+	if len(b.IncomingBlock()) != 2 {
+		t.Fatalf("expect 2 buffered blocks, got %v", len(b.IncomingBlock()))
+	}
+
+	d, err := newBlockSync(mockP2PService, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+	if cap(d.IncomingBlock()) != DefaultIncomingBlockBufferSize {
+		t.Fatalf("expect default buffer size %v, got %v", DefaultIncomingBlockBufferSize, cap(d.IncomingBlock()))
+	}
+}
+
+func TestBlockSyncPeerStats(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+	mockP2PService := p2p_mock.NewMockService(mockController)
+	mockP2PService.EXPECT().Register(gomock.Any(), gomock.Any(), gomock.Any()).Return(make(chan p2p.IncomingMessage))
+	mockP2PService.EXPECT().SendToPeer(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+
+	b, err := newBlockSync(mockP2PService, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+
+	blk := &block.Block{
+		Head: &block.BlockHead{Number: 1},
+	}
+	blk.CalculateHeadHash()
+	acc, err := account.NewKeyPair(nil, crypto.Ed25519)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blk.Sign = acc.Sign(blk.HeadHash())
+	data, err := blk.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b.RequestBlock(blk.HeadHash(), []p2p.PeerID{"peerA"}, p2p.SyncBlockRequest)
+	b.mu.Lock()
+	b.pending[string(blk.HeadHash())].sentAt = time.Now().Add(-time.Second)
+	b.mu.Unlock()
+	b.handleBlock(p2p.NewIncomingMessage("peerA", data, p2p.SyncBlockResponse))
+	b.handleBlock(p2p.NewIncomingMessage("peerB", data, p2p.SyncBlockResponse))
+
+	stats := b.PeerStats()
+	a := stats["peerA"]
+	if a.Requested != 1 || a.Received != 1 || a.AvgLatency < time.Second {
+		t.Fatalf("unexpected stat of peerA %+v", a)
+	}
+	if s := stats["peerB"]; s.Requested != 0 || s.Received != 1 || s.AvgLatency != 0 {
+		t.Fatalf("unexpected stat of peerB %+v", s)
+	}
+}
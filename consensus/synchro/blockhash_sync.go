@@ -33,6 +33,7 @@ type blockHashSync struct {
 	p                  p2p.Service
 	newBlockHashCh     chan *BlockHash
 	neighborBlockHashs map[p2p.PeerID]*blockHashs
+	peerFilter         func(p2p.PeerID) bool
 	mutex              *sync.RWMutex
 
 	msg1Ch chan p2p.IncomingMessage
@@ -42,15 +43,24 @@ type blockHashSync struct {
 	done   *sync.WaitGroup
 }
 
-func newBlockHashSync(p p2p.Service) *blockHashSync {
+func newBlockHashSync(p p2p.Service) (*blockHashSync, error) {
+	msg1Ch, err := p2p.Subscribe(p, "new block hash", p2p.NewBlockHash)
+	if err != nil {
+		return nil, err
+	}
+	msg2Ch, err := p2p.Subscribe(p, "sync block hash response", p2p.SyncBlockHashResponse)
+	if err != nil {
+		p.Deregister("new block hash", p2p.NewBlockHash)
+		return nil, err
+	}
 	b := &blockHashSync{
 		p:                  p,
 		newBlockHashCh:     make(chan *BlockHash, 1024),
 		neighborBlockHashs: make(map[p2p.PeerID]*blockHashs),
 		mutex:              new(sync.RWMutex),
 
-		msg1Ch: p.Register("new block hash", p2p.NewBlockHash),
-		msg2Ch: p.Register("sync block hash response", p2p.SyncBlockHashResponse),
+		msg1Ch: msg1Ch,
+		msg2Ch: msg2Ch,
 
 		quitCh: make(chan struct{}),
 		done:   new(sync.WaitGroup),
@@ -61,7 +71,7 @@ func newBlockHashSync(p p2p.Service) *blockHashSync {
 	go b.syncBlockHashResponseController()
 	go b.expirationController()
 
-	return b
+	return b, nil
 }
 
 func (b *blockHashSync) Close() {
@@ -70,6 +80,24 @@ func (b *blockHashSync) Close() {
 	ilog.Infof("Stopped block hash sync.")
 }
 
+// SetPeerFilter sets the filter of the peers whose block hashs are accepted. nil accepts all peers.
+func (b *blockHashSync) SetPeerFilter(fn func(p2p.PeerID) bool) {
+	b.mutex.Lock()
+	b.peerFilter = fn
+	b.mutex.Unlock()
+}
+
+// Allowed will return whether the block hashs of the peer are accepted.
+func (b *blockHashSync) Allowed(peerID p2p.PeerID) bool {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	return b.allowed(peerID)
+}
+
+func (b *blockHashSync) allowed(peerID p2p.PeerID) bool {
+	return b.peerFilter == nil || b.peerFilter(peerID)
+}
+
 // NewBlockHashs will return received new block hash.
 func (b *blockHashSync) NewBlockHashs() <-chan *BlockHash {
 	return b.newBlockHashCh
@@ -84,6 +112,9 @@ func (b *blockHashSync) NeighborBlockHashs(start, end int64) <-chan *BlockHash {
 			hashs := make(map[string]*BlockHash)
 			b.mutex.RLock()
 			for peerID, blockHashs := range b.neighborBlockHashs {
+				if !b.allowed(peerID) {
+					continue
+				}
 				key := string(blockHashs.hashs[num])
 				if blockHash, ok := hashs[key]; ok {
 					blockHash.PeerID = append(blockHash.PeerID, peerID)
@@ -149,6 +180,10 @@ func (b *blockHashSync) handleNewBlockHash(msg *p2p.IncomingMessage) {
 		ilog.Warnf("Unmarshal new block hash failed: %v", err)
 		return
 	}
+	if !b.Allowed(msg.From()) {
+		ilog.Debugf("Ignore new block hash from denied peer %v.", msg.From().Pretty())
+		return
+	}
 
 	blockHash := &BlockHash{
 		Hash:   blockInfo.Hash,
@@ -199,6 +234,10 @@ func (b *blockHashSync) handleSyncBlockHashResponse(msg *p2p.IncomingMessage) {
 	b.mutex.Lock()
 	defer b.mutex.Unlock()
 
+	if !b.allowed(msg.From()) {
+		ilog.Debugf("Ignore block hash from denied peer %v.", msg.From().Pretty())
+		return
+	}
 	if bHashs, ok := b.neighborBlockHashs[msg.From()]; ok {
 		for k, v := range hashs {
 			bHashs.hashs[k] = v
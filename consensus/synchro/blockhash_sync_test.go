@@ -0,0 +1,73 @@
+package synchro
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/proto"
+	"github.com/iost-official/go-iost/consensus/synchro/pb"
+	"github.com/iost-official/go-iost/p2p"
+	"github.com/iost-official/go-iost/p2p/mocks"
+)
+
+func TestPeerFilter(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+	mockP2PService := p2p_mock.NewMockService(mockController)
+	mockP2PService.EXPECT().Register(gomock.Any(), gomock.Any()).Return(make(chan p2p.IncomingMessage)).Times(2)
+	mockP2PService.EXPECT().Register(gomock.Any(), gomock.Any(), gomock.Any()).Return(make(chan p2p.IncomingMessage))
+
+	blockhashSync, err := newBlockHashSync(mockP2PService)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blockSync, err := newBlockSync(mockP2PService, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &Sync{
+		p:             mockP2PService,
+		blockhashSync: blockhashSync,
+		blockSync:     blockSync,
+	}
+	defer s.blockhashSync.Close()
+	defer s.blockSync.Close()
+	s.SetPeerFilter(func(peerID p2p.PeerID) bool {
+		return peerID != "bad1" && peerID != "bad2"
+	})
+
+	// No SendToPeer is expected for the block hash of a denied peer.
+	s.doNewBlockSync(&BlockHash{Hash: []byte("hash"), PeerID: []p2p.PeerID{"bad1"}})
+
+	data, err := proto.Marshal(&msgpb.BlockInfo{Hash: []byte("hash")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.blockhashSync.handleNewBlockHash(p2p.NewIncomingMessage("bad1", data, p2p.NewBlockHash))
+	if len(s.blockhashSync.NewBlockHashs()) != 0 {
+		t.Fatal("new block hash of denied peer should be ignored")
+	}
+
+	data, err = proto.Marshal(&msgpb.BlockHashResponse{
+		BlockInfos: []*msgpb.BlockInfo{{Number: 5, Hash: []byte("hash")}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.blockhashSync.handleSyncBlockHashResponse(p2p.NewIncomingMessage("bad1", data, p2p.SyncBlockHashResponse))
+	s.blockhashSync.handleSyncBlockHashResponse(p2p.NewIncomingMessage("bad2", data, p2p.SyncBlockHashResponse))
+	for blockHash := range s.blockhashSync.NeighborBlockHashs(5, 5) {
+		t.Fatalf("block hash of denied peers should be ignored, got %v", blockHash)
+	}
+
+	s.SetPeerFilter(nil)
+	s.blockhashSync.handleSyncBlockHashResponse(p2p.NewIncomingMessage("bad1", data, p2p.SyncBlockHashResponse))
+	s.blockhashSync.handleSyncBlockHashResponse(p2p.NewIncomingMessage("bad2", data, p2p.SyncBlockHashResponse))
+	count := 0
+	for range s.blockhashSync.NeighborBlockHashs(5, 5) {
+		count++
+	}
+	if count != 1 {
+		t.Fatalf("expect 1 block hash without filter, got %v", count)
+	}
+}
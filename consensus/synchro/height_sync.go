@@ -1,6 +1,7 @@
 package synchro
 
 import (
+	"math"
 	"sort"
 	"sync"
 	"time"
@@ -16,9 +17,15 @@ const (
 	heightExpiredSeconds = 60
 )
 
+var (
+	defaultNeighborHeightTTL = 30 * time.Second
+)
+
 // heightSync is responsible for maintaining the height of neighbor nodes.
 type heightSync struct {
 	neighborHeight map[p2p.PeerID]*msgpb.SyncHeight
+	lastUpdate     time.Time
+	ttl            time.Duration
 	mutex          *sync.RWMutex
 
 	msgCh chan p2p.IncomingMessage
@@ -27,12 +34,17 @@ type heightSync struct {
 	done   *sync.WaitGroup
 }
 
-func newHeightSync(p p2p.Service) *heightSync {
+func newHeightSync(p p2p.Service) (*heightSync, error) {
+	msgCh, err := p2p.Subscribe(p, "sync height response", p2p.SyncHeight)
+	if err != nil {
+		return nil, err
+	}
 	h := &heightSync{
 		neighborHeight: make(map[p2p.PeerID]*msgpb.SyncHeight),
+		ttl:            defaultNeighborHeightTTL,
 		mutex:          new(sync.RWMutex),
 
-		msgCh: p.Register("sync height response", p2p.SyncHeight),
+		msgCh: msgCh,
 
 		quitCh: make(chan struct{}),
 		done:   new(sync.WaitGroup),
@@ -42,7 +54,7 @@ func newHeightSync(p p2p.Service) *heightSync {
 	go h.syncHeightController()
 	go h.expirationController()
 
-	return h
+	return h, nil
 }
 
 // Close will close the height synchronizer of blockchain.
@@ -53,7 +65,8 @@ func (h *heightSync) Close() {
 }
 
 // NeighborHeight will return the median of the head height of the neighbor nodes.
-// If the number of neighbor nodes is less than leastNeighborNumber, return -1.
+// If the number of neighbor nodes is less than leastNeighborNumber,
+// or no height has been received within the ttl, return -1.
 func (h *heightSync) NeighborHeight() int64 {
 	h.mutex.RLock()
 	defer h.mutex.RUnlock()
@@ -61,6 +74,9 @@ func (h *heightSync) NeighborHeight() int64 {
 	if len(h.neighborHeight) < leastNeighborNumber {
 		return -1
 	}
+	if time.Since(h.lastUpdate) > h.ttl {
+		return -1
+	}
 
 	t := make([]int64, 0)
 	for _, v := range h.neighborHeight {
@@ -71,6 +87,25 @@ func (h *heightSync) NeighborHeight() int64 {
 	return t[len(t)/2]
 }
 
+// NeighborHeightAge will return the time since the last height update of the neighbor nodes.
+// If no height has been received, return the max duration.
+func (h *heightSync) NeighborHeightAge() time.Duration {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	if h.lastUpdate.IsZero() {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Since(h.lastUpdate)
+}
+
+// SetTTL sets how long the received heights are trusted.
+func (h *heightSync) SetTTL(ttl time.Duration) {
+	h.mutex.Lock()
+	h.ttl = ttl
+	h.mutex.Unlock()
+}
+
 func (h *heightSync) handleHeightSync(msg *p2p.IncomingMessage) {
 	if msg.Type() != p2p.SyncHeight {
 		ilog.Warnf("Expect the type %v, but get a unexpected type %v", p2p.SyncHeight, msg.Type())
@@ -92,9 +127,11 @@ func (h *heightSync) handleHeightSync(msg *p2p.IncomingMessage) {
 	if old, ok := h.neighborHeight[msg.From()]; ok {
 		if old.Time < syncHeight.Time {
 			h.neighborHeight[msg.From()] = syncHeight
+			h.lastUpdate = time.Now()
 		}
 	} else {
 		h.neighborHeight[msg.From()] = syncHeight
+		h.lastUpdate = time.Now()
 	}
 }
 
@@ -0,0 +1,55 @@
+package synchro
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/proto"
+	"github.com/iost-official/go-iost/consensus/synchro/pb"
+	"github.com/iost-official/go-iost/p2p"
+	"github.com/iost-official/go-iost/p2p/mocks"
+)
+
+func TestNeighborHeightTTL(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+	mockP2PService := p2p_mock.NewMockService(mockController)
+	mockP2PService.EXPECT().Register(gomock.Any(), gomock.Any()).Return(make(chan p2p.IncomingMessage))
+
+	h, err := newHeightSync(mockP2PService)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+
+	if h.NeighborHeight() != -1 {
+		t.Fatal("neighbor height should be unknown before any update")
+	}
+	for i := 0; i < leastNeighborNumber; i++ {
+		data, err := proto.Marshal(&msgpb.SyncHeight{Height: 100, Time: time.Now().Unix()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		h.handleHeightSync(p2p.NewIncomingMessage(p2p.PeerID(fmt.Sprintf("peer%v", i)), data, p2p.SyncHeight))
+	}
+	if height := h.NeighborHeight(); height != 100 {
+		t.Fatalf("expect neighbor height 100, got %v", height)
+	}
+	if age := h.NeighborHeightAge(); age > time.Second {
+		t.Fatalf("neighbor height age should be fresh, got %v", age)
+	}
+
+	h.mutex.Lock()
+	h.lastUpdate = time.Now().Add(-2 * defaultNeighborHeightTTL)
+	h.mutex.Unlock()
+	if height := h.NeighborHeight(); height != -1 {
+		t.Fatalf("expired neighbor height should be ignored, got %v", height)
+	}
+
+	h.SetTTL(3 * defaultNeighborHeightTTL)
+	if height := h.NeighborHeight(); height != 100 {
+		t.Fatalf("expect neighbor height 100 after raising the ttl, got %v", height)
+	}
+}
@@ -0,0 +1,76 @@
+package synchro
+
+import (
+	"sync"
+	"time"
+
+	"github.com/iost-official/go-iost/p2p"
+)
+
+// PeerSyncStat is the block sync statistics of a peer.
+// AvgLatency is the average time from requesting a block of the peer to receiving it.
+type PeerSyncStat struct {
+	Requested  int64
+	Received   int64
+	AvgLatency time.Duration
+}
+
+type peerStat struct {
+	requested    int64
+	received     int64
+	latencyTotal time.Duration
+	latencyCount int64
+}
+
+// peerStats records the block requests sent to the peers and the responses of them.
+type peerStats struct {
+	mu    sync.Mutex
+	stats map[p2p.PeerID]*peerStat
+}
+
+func newPeerStats() *peerStats {
+	return &peerStats{
+		stats: make(map[p2p.PeerID]*peerStat),
+	}
+}
+
+func (ps *peerStats) get(peerID p2p.PeerID) *peerStat {
+	s, ok := ps.stats[peerID]
+	if !ok {
+		s = &peerStat{}
+		ps.stats[peerID] = s
+	}
+	return s
+}
+
+func (ps *peerStats) recordRequest(peerID p2p.PeerID) {
+	ps.mu.Lock()
+	ps.get(peerID).requested++
+	ps.mu.Unlock()
+}
+
+// recordResponse records a block received from the peer, the zero sentAt means the block wasn't requested from it.
+func (ps *peerStats) recordResponse(peerID p2p.PeerID, sentAt, now time.Time) {
+	ps.mu.Lock()
+	s := ps.get(peerID)
+	s.received++
+	if !sentAt.IsZero() {
+		s.latencyTotal += now.Sub(sentAt)
+		s.latencyCount++
+	}
+	ps.mu.Unlock()
+}
+
+func (ps *peerStats) snapshot() map[p2p.PeerID]PeerSyncStat {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	ret := make(map[p2p.PeerID]PeerSyncStat, len(ps.stats))
+	for peerID, s := range ps.stats {
+		stat := PeerSyncStat{Requested: s.requested, Received: s.received}
+		if s.latencyCount > 0 {
+			stat.AvgLatency = s.latencyTotal / time.Duration(s.latencyCount)
+		}
+		ret[peerID] = stat
+	}
+	return ret
+}
@@ -24,13 +24,17 @@ type requestHandler struct {
 	done   *sync.WaitGroup
 }
 
-func newRequestHandler(p p2p.Service, bCache blockcache.BlockCache, bChain block.Chain) *requestHandler {
+func newRequestHandler(p p2p.Service, bCache blockcache.BlockCache, bChain block.Chain) (*requestHandler, error) {
+	requestCh, err := p2p.Subscribe(p, "sync request", p2p.SyncBlockHashRequest, p2p.SyncBlockRequest, p2p.NewBlockRequest)
+	if err != nil {
+		return nil, err
+	}
 	rHandler := &requestHandler{
 		p:      p,
 		bCache: bCache,
 		bChain: bChain,
 
-		requestCh: p.Register("sync request", p2p.SyncBlockHashRequest, p2p.SyncBlockRequest, p2p.NewBlockRequest),
+		requestCh: requestCh,
 
 		quitCh: make(chan struct{}),
 		done:   new(sync.WaitGroup),
@@ -39,7 +43,7 @@ func newRequestHandler(p p2p.Service, bCache blockcache.BlockCache, bChain block
 	rHandler.done.Add(1)
 	go rHandler.controller()
 
-	return rHandler
+	return rHandler, nil
 }
 
 // Close will close the sync request handler.
@@ -1,7 +1,6 @@
 package synchro
 
 import (
-	"math/rand"
 	"sync"
 	"time"
 
@@ -31,25 +30,51 @@ type Sync struct {
 	blockhashSync   *blockHashSync
 	blockSync       *blockSync
 
-	quitCh chan struct{}
-	done   *sync.WaitGroup
+	quitCh    chan struct{}
+	closeOnce *sync.Once
+	done      *sync.WaitGroup
 }
 
 // New will return a new synchronizer of blockchain.
-func New(p p2p.Service, bCache blockcache.BlockCache, bChain block.Chain) *Sync {
+// The incoming blocks are buffered up to bufferSize, 0 means DefaultIncomingBlockBufferSize.
+func New(p p2p.Service, bCache blockcache.BlockCache, bChain block.Chain, bufferSize int) (*Sync, error) {
+	handler, err := newRequestHandler(p, bCache, bChain)
+	if err != nil {
+		return nil, err
+	}
+	heightSync, err := newHeightSync(p)
+	if err != nil {
+		handler.Close()
+		return nil, err
+	}
+	blockhashSync, err := newBlockHashSync(p)
+	if err != nil {
+		handler.Close()
+		heightSync.Close()
+		return nil, err
+	}
+	blockSync, err := newBlockSync(p, bufferSize)
+	if err != nil {
+		handler.Close()
+		heightSync.Close()
+		blockhashSync.Close()
+		return nil, err
+	}
+
 	sync := &Sync{
 		p:      p,
 		bCache: bCache,
 		bChain: bChain,
 
-		handler:         newRequestHandler(p, bCache, bChain),
+		handler:         handler,
 		rangeController: newRangeController(bCache),
-		heightSync:      newHeightSync(p),
-		blockhashSync:   newBlockHashSync(p),
-		blockSync:       newBlockSync(p),
+		heightSync:      heightSync,
+		blockhashSync:   blockhashSync,
+		blockSync:       blockSync,
 
-		quitCh: make(chan struct{}),
-		done:   new(sync.WaitGroup),
+		quitCh:    make(chan struct{}),
+		closeOnce: new(sync.Once),
+		done:      new(sync.WaitGroup),
 	}
 
 	sync.done.Add(5)
@@ -59,19 +84,23 @@ func New(p p2p.Service, bCache blockcache.BlockCache, bChain block.Chain) *Sync
 	go sync.syncNewBlockController()
 	go sync.metricsController()
 
-	return sync
+	return sync, nil
 }
 
 // Close will close the synchronizer of blockchain.
+// Repeated calls are no-ops.
 func (s *Sync) Close() {
-	s.handler.Close()
-	s.heightSync.Close()
-	s.blockhashSync.Close()
-	s.blockSync.Close()
+	s.closeOnce.Do(func() {
+		s.handler.Close()
+		s.rangeController.Close()
+		s.heightSync.Close()
+		s.blockhashSync.Close()
+		s.blockSync.Close()
 
-	close(s.quitCh)
-	s.done.Wait()
-	ilog.Infof("Stopped sync.")
+		close(s.quitCh)
+		s.done.Wait()
+		ilog.Infof("Stopped sync.")
+	})
 }
 
 // IncomingBlock will return the blocks from other nodes.
@@ -80,12 +109,50 @@ func (s *Sync) IncomingBlock() <-chan *BlockMessage {
 	return s.blockSync.IncomingBlock()
 }
 
+// SetMaxBlockRetries sets the max number of retries of a block request.
+func (s *Sync) SetMaxBlockRetries(n int) {
+	s.blockSync.SetMaxRetries(n)
+}
+
+// SetPeerFilter sets the filter of the peers to sync blocks from.
+// The block hashs of the denied peers are ignored. nil allows all peers.
+func (s *Sync) SetPeerFilter(fn func(p2p.PeerID) bool) {
+	s.blockhashSync.SetPeerFilter(fn)
+}
+
+// PeerStats returns the number of blocks requested from and received from each peer,
+// and the average latency of the responses.
+func (s *Sync) PeerStats() map[p2p.PeerID]PeerSyncStat {
+	return s.blockSync.PeerStats()
+}
+
+func (s *Sync) allowedPeers(peerIDs []p2p.PeerID) []p2p.PeerID {
+	allowed := make([]p2p.PeerID, 0, len(peerIDs))
+	for _, peerID := range peerIDs {
+		if s.blockhashSync.Allowed(peerID) {
+			allowed = append(allowed, peerID)
+		}
+	}
+	return allowed
+}
+
 // NeighborHeight will return the median of the head height of the neighbor nodes.
-// If the number of neighbor nodes is less than leastNeighborNumber, return -1.
+// If the number of neighbor nodes is less than leastNeighborNumber,
+// or no height has been received within the ttl, return -1.
 func (s *Sync) NeighborHeight() int64 {
 	return s.heightSync.NeighborHeight()
 }
 
+// NeighborHeightAge will return the time since the last height update of the neighbor nodes.
+func (s *Sync) NeighborHeightAge() time.Duration {
+	return s.heightSync.NeighborHeightAge()
+}
+
+// SetNeighborHeightTTL sets how long the neighbor heights are trusted.
+func (s *Sync) SetNeighborHeightTTL(ttl time.Duration) {
+	s.heightSync.SetTTL(ttl)
+}
+
 func (s *Sync) doHeightSync() {
 	syncHeight := &msgpb.SyncHeight{
 		Height: s.bCache.Head().Head.Number,
@@ -162,9 +229,11 @@ func (s *Sync) doBlockSync() {
 			continue
 		}
 
-		rand.Seed(time.Now().UnixNano())
-		peerID := blockHash.PeerID[rand.Int()%len(blockHash.PeerID)]
-		s.blockSync.RequestBlock(blockHash.Hash, peerID, p2p.SyncBlockRequest)
+		peerIDs := s.allowedPeers(blockHash.PeerID)
+		if len(peerIDs) == 0 {
+			continue
+		}
+		s.blockSync.RequestBlock(blockHash.Hash, peerIDs, p2p.SyncBlockRequest)
 	}
 }
 
@@ -181,6 +250,10 @@ func (s *Sync) syncBlockController() {
 }
 
 func (s *Sync) doNewBlockSync(blockHash *BlockHash) {
+	// New block hash just have 0 number peer ID.
+	if len(blockHash.PeerID) == 0 || !s.blockhashSync.Allowed(blockHash.PeerID[0]) {
+		return
+	}
 	// TODO: Confirm whether you need to judge the synchronization mode to skip directly.
 	_, err := s.bCache.Find(blockHash.Hash)
 	if err == nil {
@@ -188,8 +261,7 @@ func (s *Sync) doNewBlockSync(blockHash *BlockHash) {
 		return
 	}
 
-	// New block hash just have 0 number peer ID.
-	s.blockSync.RequestBlock(blockHash.Hash, blockHash.PeerID[0], p2p.NewBlockRequest)
+	s.blockSync.RequestBlock(blockHash.Hash, blockHash.PeerID[:1], p2p.NewBlockRequest)
 }
 
 func (s *Sync) syncNewBlockController() {
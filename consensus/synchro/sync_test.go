@@ -0,0 +1,25 @@
+package synchro
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/iost-official/go-iost/p2p"
+	"github.com/iost-official/go-iost/p2p/mocks"
+)
+
+func TestSyncCloseTwice(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+	mockP2PService := p2p_mock.NewMockService(mockController)
+	mockP2PService.EXPECT().Register(gomock.Any(), gomock.Any()).Return(make(chan p2p.IncomingMessage)).AnyTimes()
+	mockP2PService.EXPECT().Register(gomock.Any(), gomock.Any(), gomock.Any()).Return(make(chan p2p.IncomingMessage)).AnyTimes()
+	mockP2PService.EXPECT().Register(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(make(chan p2p.IncomingMessage)).AnyTimes()
+
+	s, err := New(mockP2PService, nil, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+	s.Close()
+}
@@ -72,13 +72,19 @@ func NewSynchronizer(basevariable global.BaseVariable, blkcache blockcache.Block
 		return nil, err
 	}
 
-	sy.messageChan = sy.p2pService.Register("sync message",
+	sy.messageChan, err = p2p.Subscribe(sy.p2pService, "sync message",
 		p2p.SyncBlockRequest,
 		p2p.SyncBlockHashRequest,
 		p2p.SyncBlockHashResponse,
 	)
+	if err != nil {
+		return nil, err
+	}
 
-	sy.syncHeightChan = sy.p2pService.Register("sync height", p2p.SyncHeight)
+	sy.syncHeightChan, err = p2p.Subscribe(sy.p2pService, "sync height", p2p.SyncHeight)
+	if err != nil {
+		return nil, err
+	}
 	sy.exitSignal = make(chan struct{})
 
 	continuousNum = basevariable.Continuous()
@@ -1,6 +1,7 @@
 package block
 
 import (
+	"bytes"
 	"errors"
 
 	"github.com/golang/protobuf/proto"
@@ -121,6 +122,29 @@ func (b *Block) CalculateTxMerkleHash() []byte {
 	return m.RootHash()
 }
 
+// TxMerkleProof returns the index of the tx in the block and its merkle path to the tx merkle hash.
+// They can be checked by merkletree.VerifyMerkleProof.
+func (b *Block) TxMerkleProof(txHash []byte) (int32, [][]byte, error) {
+	index := int32(-1)
+	hashes := make([][]byte, 0, len(b.Txs))
+	for i, t := range b.Txs {
+		if index < 0 && bytes.Equal(t.Hash(), txHash) {
+			index = int32(i)
+		}
+		hashes = append(hashes, t.Hash())
+	}
+	if index < 0 {
+		return 0, nil, errors.New("tx isn't in the block")
+	}
+	m := merkletree.MerkleTree{}
+	m.Build(hashes)
+	mp, err := m.MerklePath(txHash)
+	if err != nil {
+		return 0, nil, err
+	}
+	return index, mp, nil
+}
+
 // CalculateTxReceiptMerkleHash calculate the merkle hash of the transaction receipt.
 func (b *Block) CalculateTxReceiptMerkleHash() []byte {
 	m := merkletree.TXRMerkleTree{}
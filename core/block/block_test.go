@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/iost-official/go-iost/account"
+	"github.com/iost-official/go-iost/core/merkletree"
 	"github.com/iost-official/go-iost/core/tx"
 	"github.com/iost-official/go-iost/crypto"
 	"github.com/smartystreets/goconvey/convey"
@@ -77,3 +78,23 @@ func TestBlockSerialize(t *testing.T) {
 		})
 	})
 }
+
+func TestTxMerkleProof(t *testing.T) {
+	convey.Convey("Test of tx merkle proof", t, func() {
+		blk := Block{Head: &BlockHead{Number: 1}}
+		for i := 0; i < 5; i++ {
+			blk.Txs = append(blk.Txs, tx.NewTx(nil, nil, 100000, 100, int64(i+1), 0, 0))
+		}
+		blk.Head.TxMerkleHash = blk.CalculateTxMerkleHash()
+
+		for i, t := range blk.Txs {
+			index, mp, err := blk.TxMerkleProof(t.Hash())
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(index, convey.ShouldEqual, i)
+			convey.So(merkletree.VerifyMerkleProof(t.Hash(), blk.Head.TxMerkleHash, index, mp), convey.ShouldBeTrue)
+		}
+
+		_, _, err := blk.TxMerkleProof([]byte("nothing"))
+		convey.So(err, convey.ShouldNotBeNil)
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"sync"
 
 	"os"
+	"path/filepath"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/iost-official/go-iost/common"
@@ -55,6 +56,7 @@ var (
 type BlockCacheNode struct { //nolint:golint
 	*block.Block
 	rw           sync.RWMutex
+	witnessRW    sync.RWMutex
 	parent       *BlockCacheNode
 	Children     map[*BlockCacheNode]bool
 	Type         BCNType
@@ -331,7 +333,8 @@ func (bc *BlockCacheImpl) nmdel(num int64) {
 
 // NewBlockCache return a new BlockCache instance
 func NewBlockCache(baseVariable global.BaseVariable) (*BlockCacheImpl, error) {
-	w, err := wal.Create(baseVariable.Config().DB.LdbPath+blockCacheWALDir, []byte("block_cache_wal"))
+	conf := baseVariable.Config().DB
+	w, err := wal.CreateWithSegmentSize(walDir(conf), []byte("block_cache_wal"), conf.WALSegmentSize)
 	if err != nil {
 		return nil, err
 	}
@@ -385,14 +388,22 @@ func NewBlockCache(baseVariable global.BaseVariable) (*BlockCacheImpl, error) {
 
 // NewWAL New wal when old one is not recoverable. Move Old File into Corrupted for later analysis.
 func (bc *BlockCacheImpl) NewWAL(config *common.Config) (err error) {
-	walPath := config.DB.LdbPath + blockCacheWALDir
-	corruptWalPath := config.DB.LdbPath + blockCacheWALDir + "Corrupted"
+	walPath := walDir(config.DB)
+	corruptWalPath := walPath + "Corrupted"
 	os.Rename(walPath, corruptWalPath)
-	bc.wal, err = wal.Create(walPath, []byte("block_cache_wal"))
+	bc.wal, err = wal.CreateWithSegmentSize(walPath, []byte("block_cache_wal"), config.DB.WALSegmentSize)
 	return
 
 }
 
+// walDir returns the directory of the block cache wal, LdbPath+blockCacheWALDir if WALPath isn't set.
+func walDir(conf *common.DBConfig) string {
+	if conf.WALPath != "" {
+		return filepath.Clean(conf.WALPath)
+	}
+	return conf.LdbPath + blockCacheWALDir
+}
+
 // Recover recover previews block cache
 func (bc *BlockCacheImpl) Recover(p conAlgo) (err error) {
 	if bc.wal.HasDecoder() {
@@ -442,7 +453,7 @@ func (bc *BlockCacheImpl) apply(entry wal.Entry, p conAlgo) (err error) {
 func (bc *BlockCacheImpl) applyLink(b []byte, p conAlgo) (err error) {
 	block, witnessList, serialNum, err := decodeBCN(b)
 	if string(bc.LinkedRoot().HeadHash()) == string(block.HeadHash()) {
-		bc.LinkedRoot().WitnessList = witnessList
+		bc.LinkedRoot().SetWitnessList(witnessList)
 		bc.LinkedRoot().SerialNum = serialNum
 	}
 	p.RecoverBlock(&block)
@@ -304,3 +304,49 @@ func StringSliceEqual(a, b []string) bool {
 	}
 	return true
 }
+
+func TestWALDir(t *testing.T) {
+	Convey("wal dir", t, func() {
+		So(walDir(&common.DBConfig{LdbPath: "storage/"}), ShouldEqual, "storage/"+blockCacheWALDir)
+		So(walDir(&common.DBConfig{LdbPath: "storage/", WALPath: "/data/wal/"}), ShouldEqual, "/data/wal")
+	})
+}
+
+func TestConcurrentWitnessAccess(t *testing.T) {
+	Convey("witness lists are read while blocks are added", t, func() {
+		root := NewBCN(nil, &block.Block{Head: &block.BlockHead{Number: 0}})
+		root.Type = Linked
+		root.SetActive([]string{"w0"})
+		root.SetPending([]string{"w1"})
+		bc := &BlockCacheImpl{}
+		bc.SetHead(root)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			parent := root
+			for i := int64(1); i <= 200; i++ {
+				node := NewBCN(parent, &block.Block{Head: &block.BlockHead{Number: i}})
+				node.CopyWitness(parent)
+				bc.SetHead(node)
+				parent.SetActive(node.Pending())
+				parent = node
+			}
+		}()
+
+		for running := true; running; {
+			select {
+			case <-done:
+				running = false
+			default:
+			}
+			head := bc.Head()
+			So(len(head.Active()), ShouldEqual, 1)
+			So(head.Pending(), ShouldResemble, []string{"w1"})
+			wl := head.CopyWitnessList()
+			So(len(wl.Active()), ShouldEqual, 1)
+			So(head.NetID(), ShouldBeEmpty)
+		}
+		So(bc.Head().Head.Number, ShouldEqual, 200)
+	})
+}
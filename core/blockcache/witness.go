@@ -86,3 +86,87 @@ func (wl *WitnessList) CopyWitness(n *BlockCacheNode) {
 	wl.SetActive(n.Active())
 	wl.SetPending(n.Pending())
 }
+
+// The witness lists of a node are written by the goroutine adding blocks while other goroutines may read them,
+// so the methods of BlockCacheNode below lock the node and shadow the ones of the embedded WitnessList.
+// Only the goroutine adding blocks may use the embedded WitnessList directly.
+
+// Active returns the active witness list of the node.
+func (bcn *BlockCacheNode) Active() []string {
+	bcn.witnessRW.RLock()
+	defer bcn.witnessRW.RUnlock()
+	return bcn.WitnessList.Active()
+}
+
+// Pending returns the pending witness list of the node.
+func (bcn *BlockCacheNode) Pending() []string {
+	bcn.witnessRW.RLock()
+	defer bcn.witnessRW.RUnlock()
+	return bcn.WitnessList.Pending()
+}
+
+// NetID returns the net ids of the pending witnesses of the node.
+func (bcn *BlockCacheNode) NetID() []string {
+	bcn.witnessRW.RLock()
+	defer bcn.witnessRW.RUnlock()
+	return bcn.WitnessList.NetID()
+}
+
+// SetActive sets the active witness list of the node.
+func (bcn *BlockCacheNode) SetActive(al []string) {
+	bcn.witnessRW.Lock()
+	bcn.WitnessList.SetActive(al)
+	bcn.witnessRW.Unlock()
+}
+
+// SetPending sets the pending witness list of the node.
+func (bcn *BlockCacheNode) SetPending(pl []string) {
+	bcn.witnessRW.Lock()
+	bcn.WitnessList.SetPending(pl)
+	bcn.witnessRW.Unlock()
+}
+
+// SetWitnessList replaces the witness lists of the node.
+func (bcn *BlockCacheNode) SetWitnessList(wl WitnessList) {
+	bcn.witnessRW.Lock()
+	bcn.WitnessList = wl
+	bcn.witnessRW.Unlock()
+}
+
+// CopyWitnessList returns a copy of the witness lists of the node.
+func (bcn *BlockCacheNode) CopyWitnessList() WitnessList {
+	bcn.witnessRW.RLock()
+	defer bcn.witnessRW.RUnlock()
+	return WitnessList{
+		ActiveWitnessList:    bcn.ActiveWitnessList,
+		PendingWitnessList:   bcn.PendingWitnessList,
+		PendingWitnessNumber: bcn.PendingWitnessNumber,
+		WitnessInfo:          bcn.WitnessInfo,
+	}
+}
+
+// CopyWitness copies the active and pending witness lists of n to the node.
+func (bcn *BlockCacheNode) CopyWitness(n *BlockCacheNode) {
+	if n == nil {
+		return
+	}
+	active, pending := n.Active(), n.Pending()
+	bcn.witnessRW.Lock()
+	bcn.WitnessList.SetActive(active)
+	bcn.WitnessList.SetPending(pending)
+	bcn.witnessRW.Unlock()
+}
+
+// UpdatePending updates the pending witness list of the node from the state db.
+func (bcn *BlockCacheNode) UpdatePending(mv db.MVCCDB) error {
+	bcn.witnessRW.Lock()
+	defer bcn.witnessRW.Unlock()
+	return bcn.WitnessList.UpdatePending(mv)
+}
+
+// UpdateInfo updates the net ids of the pending witnesses of the node from the state db.
+func (bcn *BlockCacheNode) UpdateInfo(mv db.MVCCDB) error {
+	bcn.witnessRW.Lock()
+	defer bcn.witnessRW.Unlock()
+	return bcn.WitnessList.UpdateInfo(mv)
+}
@@ -0,0 +1,65 @@
+package contract
+
+// ABIDiff is the difference between the abis of two versions of a contract.
+type ABIDiff struct {
+	Added   []*ABI       // abis only in the new version, in its order
+	Removed []*ABI       // abis only in the old version, in its order
+	Changed []*ABIChange // abis whose args changed, in the order of the new version
+}
+
+// ABIChange is an abi whose args changed between two versions of a contract.
+type ABIChange struct {
+	Name    string
+	OldArgs []string
+	NewArgs []string
+}
+
+// Empty returns whether the two versions have the same abis.
+func (d ABIDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// DiffABI returns the abis added, removed and changed from old to new, a nil info has no abi.
+func DiffABI(old, new *Info) ABIDiff {
+	var d ABIDiff
+	if new == nil {
+		new = &Info{}
+	}
+	if old == nil {
+		old = &Info{}
+	}
+	for _, a := range new.Abi {
+		if first, _ := new.ABIByName(a.Name); first != a {
+			continue
+		}
+		oa, ok := old.ABIByName(a.Name)
+		if !ok {
+			d.Added = append(d.Added, a)
+			continue
+		}
+		if !sameArgs(oa.Args, a.Args) {
+			d.Changed = append(d.Changed, &ABIChange{Name: a.Name, OldArgs: oa.Args, NewArgs: a.Args})
+		}
+	}
+	for _, oa := range old.Abi {
+		if first, _ := old.ABIByName(oa.Name); first != oa {
+			continue
+		}
+		if _, ok := new.ABIByName(oa.Name); !ok {
+			d.Removed = append(d.Removed, oa)
+		}
+	}
+	return d
+}
+
+func sameArgs(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -1,8 +1,12 @@
 package contract
 
 import (
+	"bytes"
 	"encoding/base64"
 	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
 
 	"encoding/json"
 	"io/ioutil"
@@ -91,6 +95,30 @@ func (c *Contract) VerifySelf() error {
 	return nil
 }
 
+// NormalizeCode normalizes contract code before hashing. Line endings "\r\n" and "\r"
+// are converted to "\n", trailing spaces and tabs of every line are removed, and
+// leading and trailing blank lines are dropped. Off-chain tooling must apply the
+// same steps to reproduce CodeHash.
+func NormalizeCode(code string) string {
+	code = strings.Replace(code, "\r\n", "\n", -1)
+	code = strings.Replace(code, "\r", "\n", -1)
+	lines := strings.Split(code, "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimRight(l, " \t")
+	}
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
+
+// CodeHash returns the sha3 hash of the normalized contract code.
+func (c *Contract) CodeHash() []byte {
+	return common.Sha3([]byte(NormalizeCode(c.Code)))
+}
+
+// VerifyCodeHash returns whether the contract code matches the expected hash.
+func (c *Contract) VerifyCodeHash(expected []byte) bool {
+	return bytes.Equal(c.CodeHash(), expected)
+}
+
 // DecodeContract static method to decode contract from string
 func DecodeContract(str string) *Contract {
 	var c Contract
@@ -103,9 +131,70 @@ func DecodeContract(str string) *Contract {
 
 // ABI get abi from contract with specific name
 func (c *Contract) ABI(name string) *ABI {
-	for _, a := range c.Info.Abi {
-		if a.Name == name {
-			return a
+	a, _ := c.Info.ABIByName(name)
+	return a
+}
+
+// abiIndexCache holds the name index of Info.Abi. It is the type of Info.XXX_NoUnkeyedLiteral,
+// the only field the protobuf runtime neither encodes nor requires a tag on, so keep it there
+// when contract.pb.go is regenerated.
+type abiIndexCache struct {
+	v atomic.Value
+}
+
+type abiIndex struct {
+	abi    []*ABI
+	byName map[string]*ABI
+}
+
+func (x *abiIndex) valid(abi []*ABI) bool {
+	if len(x.abi) != len(abi) {
+		return false
+	}
+	return len(abi) == 0 || &x.abi[0] == &abi[0]
+}
+
+// ABIByName returns the first abi with specific name. The name index is built on first call
+// and rebuilt once Abi is reassigned or resized.
+func (i *Info) ABIByName(name string) (*ABI, bool) {
+	x, _ := i.XXX_NoUnkeyedLiteral.v.Load().(*abiIndex)
+	if x == nil || !x.valid(i.Abi) {
+		x = &abiIndex{
+			abi:    i.Abi,
+			byName: make(map[string]*ABI, len(i.Abi)),
+		}
+		for _, a := range i.Abi {
+			if _, ok := x.byName[a.Name]; !ok {
+				x.byName[a.Name] = a
+			}
+		}
+		i.XXX_NoUnkeyedLiteral.v.Store(x)
+	}
+	a, ok := x.byName[name]
+	return a, ok
+}
+
+// CheckCompatible checks that every abi of old is still provided by i with the same args.
+// The lifecycle abis "init" and "can_update" are only invoked by the system and are not checked.
+func (i *Info) CheckCompatible(old *Info) error {
+	if old == nil {
+		return nil
+	}
+	if i == nil {
+		return errors.New("contract info is nil")
+	}
+	lifecycle := func(name string) bool {
+		return name == "init" || name == "can_update"
+	}
+	d := DiffABI(old, i)
+	for _, a := range d.Removed {
+		if !lifecycle(a.Name) {
+			return fmt.Errorf("abi %v is removed", a.Name)
+		}
+	}
+	for _, c := range d.Changed {
+		if !lifecycle(c.Name) {
+			return fmt.Errorf("args of abi %v changed from %v to %v", c.Name, c.OldArgs, c.NewArgs)
 		}
 	}
 	return nil
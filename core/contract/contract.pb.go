@@ -23,10 +23,10 @@ const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 type Info struct {
 	Lang                 string   `protobuf:"bytes,1,opt,name=lang,proto3" json:"lang,omitempty"`
 	Version              string   `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
-	Abi                  []*ABI   `protobuf:"bytes,3,rep,name=abi,proto3" json:"abi,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Abi                  []*ABI        `protobuf:"bytes,3,rep,name=abi,proto3" json:"abi,omitempty"`
+	XXX_NoUnkeyedLiteral abiIndexCache `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
 }
 
 func (m *Info) Reset()         { *m = Info{} }
@@ -79,6 +79,7 @@ type ABI struct {
 	Name                 string    `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	Args                 []string  `protobuf:"bytes,2,rep,name=args,proto3" json:"args,omitempty"`
 	AmountLimit          []*Amount `protobuf:"bytes,3,rep,name=amountLimit,proto3" json:"amountLimit,omitempty"`
+	Payment              int32     `protobuf:"varint,4,opt,name=payment,proto3" json:"payment,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
 	XXX_unrecognized     []byte    `json:"-"`
 	XXX_sizecache        int32     `json:"-"`
@@ -130,6 +131,13 @@ func (m *ABI) GetAmountLimit() []*Amount {
 	return nil
 }
 
+func (m *ABI) GetPayment() int32 {
+	if m != nil {
+		return m.Payment
+	}
+	return 0
+}
+
 type Amount struct {
 	Token                string   `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
 	Val                  string   `protobuf:"bytes,2,opt,name=val,proto3" json:"val,omitempty"`
@@ -242,21 +250,22 @@ func init() {
 func init() { proto.RegisterFile("core/contract/contract.proto", fileDescriptor_f74c2661e7246774) }
 
 var fileDescriptor_f74c2661e7246774 = []byte{
-	// 245 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x4c, 0x90, 0xc1, 0x6e, 0xc2, 0x30,
-	0x0c, 0x86, 0x45, 0xd3, 0x31, 0xe6, 0x6a, 0x08, 0x59, 0x3b, 0xe4, 0x30, 0x69, 0x55, 0x4f, 0x9c,
-	0xd8, 0xd4, 0x3d, 0x01, 0x8c, 0x4b, 0xa4, 0x9d, 0x22, 0xed, 0x01, 0x42, 0x17, 0x50, 0x34, 0x1a,
-	0x4f, 0x21, 0xe3, 0xf9, 0x27, 0xa7, 0x29, 0x70, 0xfb, 0xfd, 0xff, 0xce, 0x67, 0xc7, 0xf0, 0xdc,
-	0x51, 0xb0, 0xaf, 0x1d, 0xf9, 0x18, 0x4c, 0x17, 0x2f, 0x62, 0xf5, 0x1b, 0x28, 0x12, 0xce, 0xc6,
-	0xba, 0xf9, 0x82, 0x52, 0xf9, 0x3d, 0x21, 0x42, 0x79, 0x34, 0xfe, 0x20, 0x27, 0xf5, 0x64, 0xf9,
-	0xa0, 0x93, 0x46, 0x09, 0xf7, 0x67, 0x1b, 0x4e, 0x8e, 0xbc, 0x2c, 0x92, 0x3d, 0x96, 0xf8, 0x02,
-	0xc2, 0xec, 0x9c, 0x14, 0xb5, 0x58, 0x56, 0xed, 0xe3, 0xea, 0x42, 0x5f, 0x6f, 0x94, 0xe6, 0xa4,
-	0x31, 0x20, 0xd6, 0x1b, 0xc5, 0x54, 0x6f, 0x7a, 0x3b, 0x52, 0x59, 0xb3, 0x67, 0xc2, 0xe1, 0x24,
-	0x8b, 0x5a, 0xb0, 0xc7, 0x1a, 0x5b, 0xa8, 0x4c, 0x4f, 0x7f, 0x3e, 0x7e, 0xba, 0xde, 0xc5, 0xcc,
-	0x5d, 0xdc, 0x70, 0x53, 0xa8, 0x6f, 0x9b, 0x9a, 0x37, 0x98, 0x0e, 0x36, 0x3e, 0xc1, 0x5d, 0xa4,
-	0x1f, 0xeb, 0xf3, 0x98, 0xa1, 0xc0, 0x05, 0x88, 0xb3, 0x39, 0xe6, 0xcd, 0x59, 0x36, 0x1a, 0x66,
-	0x1f, 0x99, 0x88, 0x73, 0x28, 0xd4, 0x36, 0x3f, 0x28, 0xd4, 0x16, 0x1b, 0x28, 0x9d, 0xdf, 0x53,
-	0x6a, 0xaf, 0xda, 0xf9, 0x75, 0x34, 0x5f, 0x47, 0xa7, 0x8c, 0x37, 0xef, 0xe8, 0xdb, 0x4a, 0x31,
-	0xfc, 0x86, 0xf5, 0x6e, 0x9a, 0x0e, 0xfa, 0xfe, 0x1f, 0x00, 0x00, 0xff, 0xff, 0x37, 0x75, 0x1b,
-	0x8b, 0x70, 0x01, 0x00, 0x00,
+	// 259 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x4c, 0x90, 0xc1, 0x6a, 0xc3, 0x30,
+	0x0c, 0x86, 0x49, 0x9c, 0x76, 0x9d, 0xc2, 0x4a, 0x11, 0x3b, 0xf8, 0x30, 0x58, 0xc8, 0x29, 0xa7,
+	0x6e, 0x64, 0x4f, 0xd0, 0xae, 0x97, 0xc0, 0x4e, 0x86, 0x3d, 0x80, 0x9b, 0xb9, 0x25, 0xac, 0xb1,
+	0x8a, 0xeb, 0x15, 0xc6, 0x5e, 0x7e, 0xc8, 0x71, 0xda, 0xde, 0xbe, 0x5f, 0xb2, 0x7e, 0xeb, 0x17,
+	0x3c, 0xb5, 0xe4, 0xcc, 0x4b, 0x4b, 0xd6, 0x3b, 0xdd, 0xfa, 0x0b, 0x2c, 0x8f, 0x8e, 0x3c, 0xe1,
+	0x6c, 0xd4, 0xe5, 0x27, 0x64, 0x8d, 0xdd, 0x11, 0x22, 0x64, 0x07, 0x6d, 0xf7, 0x32, 0x29, 0x92,
+	0xea, 0x5e, 0x05, 0x46, 0x09, 0x77, 0x67, 0xe3, 0x4e, 0x1d, 0x59, 0x99, 0x86, 0xf2, 0x28, 0xf1,
+	0x19, 0x84, 0xde, 0x76, 0x52, 0x14, 0xa2, 0xca, 0xeb, 0x87, 0xe5, 0xc5, 0x7d, 0xb5, 0x6e, 0x14,
+	0x77, 0xca, 0x3f, 0x10, 0xab, 0x75, 0xc3, 0xae, 0x56, 0xf7, 0x66, 0x74, 0x65, 0xe6, 0x9a, 0x76,
+	0xfb, 0x93, 0x4c, 0x0b, 0xc1, 0x35, 0x66, 0xac, 0x21, 0xd7, 0x3d, 0xfd, 0x58, 0xff, 0xd1, 0xf5,
+	0x9d, 0x8f, 0xbe, 0x8b, 0x1b, 0xdf, 0xd0, 0x54, 0xb7, 0x8f, 0x78, 0xbb, 0xa3, 0xfe, 0xed, 0x8d,
+	0xf5, 0x32, 0x2b, 0x92, 0x6a, 0xa2, 0x46, 0x59, 0xbe, 0xc2, 0x74, 0x18, 0xc0, 0x47, 0x98, 0x78,
+	0xfa, 0x36, 0x36, 0x2e, 0x30, 0x08, 0x5c, 0x80, 0x38, 0xeb, 0x43, 0xcc, 0xc4, 0x58, 0x2a, 0x98,
+	0xbd, 0xc7, 0xbf, 0x70, 0x0e, 0x69, 0xb3, 0x89, 0x03, 0x69, 0xb3, 0xc1, 0x12, 0xb2, 0xce, 0xee,
+	0x28, 0x3c, 0xcf, 0xeb, 0xf9, 0x75, 0x29, 0xbe, 0x9b, 0x0a, 0x3d, 0xce, 0xd4, 0xd2, 0x97, 0x91,
+	0x62, 0xc8, 0xc9, 0xbc, 0x9d, 0x86, 0x53, 0xbf, 0xfd, 0x0f, 0x00, 0x47, 0x0d, 0x15, 0x35, 0x8a,
+	0x01, 0x00, 0x00,
 }
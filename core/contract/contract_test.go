@@ -1,7 +1,13 @@
 package contract
 
 import (
+	"bytes"
+	"encoding/json"
 	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/protoc-gen-go/descriptor"
+	"github.com/iost-official/go-iost/common"
 )
 
 func TestCodec(t *testing.T) {
@@ -17,10 +23,229 @@ func TestCodec(t *testing.T) {
 			},
 		},
 	}
+	if c.ABI("abi1") == nil {
+		t.Fatal("abi1 not found")
+	}
 	buf := c.Encode()
 	var d Contract
 	d.Decode(buf)
 	if d.String() != c.String() {
 		t.Fatal(d.String())
 	}
+	if d.ABI("abi1") == nil || d.Encode() != buf {
+		t.Fatal(d.String())
+	}
+}
+
+func TestCodeHash(t *testing.T) {
+	c := Contract{Code: "function f() {\n\treturn 1;\n}\n"}
+	hash := c.CodeHash()
+	if !c.VerifyCodeHash(hash) {
+		t.Fatal("code hash mismatch")
+	}
+	if c.VerifyCodeHash(common.Sha3([]byte("other"))) {
+		t.Fatal("code hash should not match other hash")
+	}
+
+	d := Contract{Code: "\r\nfunction f() {  \r\n\treturn 1;\t\r\n}\r\n\r\n"}
+	if !d.VerifyCodeHash(hash) {
+		t.Fatal("whitespace-only difference should not change code hash")
+	}
+
+	e := Contract{Code: "function f() {\n\treturn 2;\n}\n"}
+	if e.VerifyCodeHash(hash) {
+		t.Fatal("different code should change code hash")
+	}
+}
+
+func TestABIByName(t *testing.T) {
+	info := &Info{
+		Abi: []*ABI{
+			{Name: "abi1"},
+			{Name: "abi2", Args: []string{"string"}},
+		},
+	}
+	a, ok := info.ABIByName("abi2")
+	if !ok || a != info.Abi[1] {
+		t.Fatal("abi2 not found")
+	}
+	if _, ok := info.ABIByName("abi3"); ok {
+		t.Fatal("abi3 should not be found")
+	}
+
+	info.Abi = append(info.Abi, &ABI{Name: "abi3"})
+	a, ok = info.ABIByName("abi3")
+	if !ok || a.Name != "abi3" {
+		t.Fatal("abi3 not found after append")
+	}
+
+	info.Abi = []*ABI{{Name: "abi4"}}
+	if _, ok := info.ABIByName("abi1"); ok {
+		t.Fatal("abi1 should not be found after reassign")
+	}
+	if _, ok := info.ABIByName("abi4"); !ok {
+		t.Fatal("abi4 not found after reassign")
+	}
+}
+
+func TestABISchemaJSON(t *testing.T) {
+	c := Contract{
+		ID: "Contract1",
+		Info: &Info{
+			Lang:    "javascript",
+			Version: "1.0.0",
+			Abi: []*ABI{
+				{
+					Name:        "transfer",
+					Args:        []string{"string", "string", "number"},
+					AmountLimit: []*Amount{{Token: "iost", Val: "100"}},
+				},
+				{
+					Name:    "init",
+					Payment: int32(ContractPay),
+				},
+			},
+		},
+	}
+	buf, err := c.ABISchemaJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var s ABISchema
+	if err := json.Unmarshal(buf, &s); err != nil {
+		t.Fatal(err)
+	}
+	if s.ID != c.ID || s.Lang != c.Info.Lang || s.Version != c.Info.Version {
+		t.Fatal(string(buf))
+	}
+	if len(s.ABIs) != len(c.Info.Abi) {
+		t.Fatal(string(buf))
+	}
+	for i, a := range c.Info.Abi {
+		if s.ABIs[i].Name != a.Name || len(s.ABIs[i].Args) != len(a.Args) || s.ABIs[i].Payment != a.Payment {
+			t.Fatal(string(buf))
+		}
+		for j := range a.Args {
+			if s.ABIs[i].Args[j] != a.Args[j] {
+				t.Fatal(string(buf))
+			}
+		}
+	}
+	if len(s.ABIs[0].AmountLimit) != 1 || !s.ABIs[0].AmountLimit[0].Equal(c.Info.Abi[0].AmountLimit[0]) {
+		t.Fatal(string(buf))
+	}
+}
+
+func TestCheckCompatible(t *testing.T) {
+	old := &Info{
+		Abi: []*ABI{
+			{Name: "init"},
+			{Name: "can_update", Args: []string{"json"}},
+			{Name: "transfer", Args: []string{"string", "number"}},
+		},
+	}
+	compatible := &Info{
+		Abi: []*ABI{
+			{Name: "can_update", Args: []string{"string"}},
+			{Name: "transfer", Args: []string{"string", "number"}},
+			{Name: "burn", Args: []string{"number"}},
+		},
+	}
+	if err := compatible.CheckCompatible(old); err != nil {
+		t.Fatal(err)
+	}
+	removed := &Info{
+		Abi: []*ABI{
+			{Name: "can_update", Args: []string{"string"}},
+		},
+	}
+	if err := removed.CheckCompatible(old); err == nil {
+		t.Fatal("removing transfer should be incompatible")
+	}
+	changed := &Info{
+		Abi: []*ABI{
+			{Name: "can_update", Args: []string{"string"}},
+			{Name: "transfer", Args: []string{"string", "string"}},
+		},
+	}
+	if err := changed.CheckCompatible(old); err == nil {
+		t.Fatal("changing args of transfer should be incompatible")
+	}
+}
+
+func TestContractFileDescriptor(t *testing.T) {
+	b, err := ContractFileDescriptor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fd descriptor.FileDescriptorProto
+	if err := proto.Unmarshal(b, &fd); err != nil {
+		t.Fatal(err)
+	}
+	if fd.GetName() != "core/contract/contract.proto" || len(fd.GetMessageType()) != 4 {
+		t.Fatal(fd.String())
+	}
+}
+
+func TestDecodeOverflowVarint(t *testing.T) {
+	// field 4 of ABI is a varint, the contract holds it in info.abi.
+	abi := func(varint []byte) string {
+		a := append([]byte{0x20}, varint...)
+		info := append([]byte{0x1a, byte(len(a))}, a...)
+		return string(append([]byte{0x12, byte(len(info))}, info...))
+	}
+	tooLong := append(bytes.Repeat([]byte{0xff}, 10), 0x01)
+	highBits := append(bytes.Repeat([]byte{0xff}, 9), 0x02)
+	for _, v := range [][]byte{tooLong, highBits} {
+		var c Contract
+		if err := c.Decode(abi(v)); err == nil {
+			t.Fatalf("decode varint %x should fail", v)
+		}
+	}
+	var c Contract
+	if err := c.Decode(abi([]byte{0x01})); err != nil {
+		t.Fatal(err)
+	}
+	if c.Info.Abi[0].Payment != 1 {
+		t.Fatalf("payment is %v, expected 1", c.Info.Abi[0].Payment)
+	}
+}
+
+func TestDiffABI(t *testing.T) {
+	old := &Info{
+		Abi: []*ABI{
+			{Name: "init"},
+			{Name: "transfer", Args: []string{"string", "number"}},
+			{Name: "mint", Args: []string{"number"}},
+			{Name: "burn", Args: []string{"number"}},
+		},
+	}
+	new := &Info{
+		Abi: []*ABI{
+			{Name: "init"},
+			{Name: "transfer", Args: []string{"string", "string", "number"}},
+			{Name: "burn", Args: []string{"number"}},
+			{Name: "freeze", Args: []string{"string"}},
+		},
+	}
+	d := DiffABI(old, new)
+	if len(d.Added) != 1 || d.Added[0].Name != "freeze" {
+		t.Fatalf("added = %v, want freeze", d.Added)
+	}
+	if len(d.Removed) != 1 || d.Removed[0].Name != "mint" {
+		t.Fatalf("removed = %v, want mint", d.Removed)
+	}
+	if len(d.Changed) != 1 {
+		t.Fatalf("changed = %v, want transfer", d.Changed)
+	}
+	c := d.Changed[0]
+	if c.Name != "transfer" || !sameArgs(c.OldArgs, old.Abi[1].Args) || !sameArgs(c.NewArgs, new.Abi[1].Args) {
+		t.Fatalf("changed = %+v", c)
+	}
+	if d.Empty() || !DiffABI(old, old).Empty() {
+		t.Fatal("only a diff of the same abis should be empty")
+	}
+	if d := DiffABI(nil, old); len(d.Added) != len(old.Abi) || len(d.Removed) != 0 {
+		t.Fatalf("diff from nil = %+v", d)
+	}
 }
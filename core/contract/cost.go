@@ -14,9 +14,28 @@ type Cost struct {
 	DataList []DataItem
 }
 
+// GasRates is the gas price of a unit of each resource
+type GasRates struct {
+	Data int64
+	Net  int64
+	CPU  int64
+}
+
+// DefaultGasRates is used by ToGas, data is paid by ram instead of gas
+var DefaultGasRates = GasRates{
+	Data: 0,
+	Net:  10,
+	CPU:  1,
+}
+
 // ToGas convert cost to gas
 func (c Cost) ToGas() int64 {
-	return 10*c.Net + c.CPU
+	return c.ToGasWithRates(DefaultGasRates)
+}
+
+// ToGasWithRates convert cost to gas with the given rates
+func (c Cost) ToGasWithRates(rates GasRates) int64 {
+	return rates.Data*c.Data + rates.Net*c.Net + rates.CPU*c.CPU
 }
 
 // AddAssign add cost to self
@@ -48,6 +67,19 @@ func (c Cost) IsOverflow(limit Cost) bool {
 	return false
 }
 
+// LessThanOrEqual decide if no field of c exceeds the limit, a nil limit or a zero field of limit is unbounded
+func (c *Cost) LessThanOrEqual(limit *Cost) bool {
+	if limit == nil {
+		return true
+	}
+	if limit.Data != 0 && c.Data > limit.Data ||
+		limit.Net != 0 && c.Net > limit.Net ||
+		limit.CPU != 0 && c.CPU > limit.CPU {
+		return false
+	}
+	return true
+}
+
 // Cost0 construct zero cost
 func Cost0() Cost {
 	return Cost{}
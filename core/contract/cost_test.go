@@ -0,0 +1,52 @@
+package contract
+
+import "testing"
+
+func TestCostLessThanOrEqual(t *testing.T) {
+	limit := NewCost(10, 10, 10)
+	tests := []struct {
+		name  string
+		cost  Cost
+		limit *Cost
+		want  bool
+	}{
+		{"zero cost", Cost0(), &limit, true},
+		{"equal", NewCost(10, 10, 10), &limit, true},
+		{"data exceeds", NewCost(11, 10, 10), &limit, false},
+		{"net exceeds", NewCost(10, 11, 10), &limit, false},
+		{"cpu exceeds", NewCost(10, 10, 11), &limit, false},
+		{"all exceed", NewCost(11, 11, 11), &limit, false},
+		{"nil limit", NewCost(100, 100, 100), nil, true},
+		{"zero limit", NewCost(100, 100, 100), &Cost{}, true},
+		{"unbounded data", NewCost(100, 10, 10), &Cost{Net: 10, CPU: 10}, true},
+		{"unbounded net", NewCost(10, 100, 11), &Cost{Data: 10, CPU: 10}, false},
+		{"unbounded cpu", NewCost(10, 10, 100), &Cost{Data: 10, Net: 10}, true},
+	}
+	for _, tt := range tests {
+		if got := tt.cost.LessThanOrEqual(tt.limit); got != tt.want {
+			t.Errorf("%v: LessThanOrEqual() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCostToGas(t *testing.T) {
+	cost := NewCost(3, 5, 7)
+	tests := []struct {
+		name  string
+		rates GasRates
+		want  int64
+	}{
+		{"default", DefaultGasRates, 57},
+		{"zero", GasRates{}, 0},
+		{"cpu only", GasRates{CPU: 2}, 14},
+		{"storage priced", GasRates{Data: 100, Net: 10, CPU: 1}, 357},
+	}
+	for _, tt := range tests {
+		if got := cost.ToGasWithRates(tt.rates); got != tt.want {
+			t.Errorf("%v: ToGasWithRates() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	if cost.ToGas() != cost.ToGasWithRates(DefaultGasRates) {
+		t.Errorf("ToGas() = %v, want %v", cost.ToGas(), cost.ToGasWithRates(DefaultGasRates))
+	}
+}
@@ -0,0 +1,17 @@
+package contract
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+)
+
+// ContractFileDescriptor returns the decompressed FileDescriptorProto bytes of contract.proto.
+func ContractFileDescriptor() ([]byte, error) { //nolint:golint
+	r, err := gzip.NewReader(bytes.NewReader(fileDescriptor_f74c2661e7246774))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
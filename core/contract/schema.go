@@ -0,0 +1,52 @@
+package contract
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// ABISchema is the json schema of a contract's interface.
+type ABISchema struct {
+	ID      string           `json:"id"`
+	Lang    string           `json:"lang"`
+	Version string           `json:"version"`
+	ABIs    []*ABISchemaItem `json:"abi"`
+}
+
+// ABISchemaItem is the json schema of an abi.
+type ABISchemaItem struct {
+	Name        string    `json:"name"`
+	Args        []string  `json:"args"`
+	AmountLimit []*Amount `json:"amountLimit"`
+	// Payment is the PaymentCode of the abi, ContractPay means the contract pays the gas of the call.
+	Payment int32 `json:"payment"`
+}
+
+// ABISchemaJSON returns the json schema of the contract's abi, in the order of Info.Abi.
+func (c *Contract) ABISchemaJSON() ([]byte, error) {
+	if c.Info == nil {
+		return nil, errors.New("contract info is nil")
+	}
+	s := &ABISchema{
+		ID:      c.ID,
+		Lang:    c.Info.Lang,
+		Version: c.Info.Version,
+		ABIs:    make([]*ABISchemaItem, 0, len(c.Info.Abi)),
+	}
+	for _, a := range c.Info.Abi {
+		item := &ABISchemaItem{
+			Name:        a.Name,
+			Args:        a.Args,
+			AmountLimit: a.AmountLimit,
+			Payment:     a.Payment,
+		}
+		if item.Args == nil {
+			item.Args = []string{}
+		}
+		if item.AmountLimit == nil {
+			item.AmountLimit = []*Amount{}
+		}
+		s.ABIs = append(s.ABIs, item)
+	}
+	return json.Marshal(s)
+}
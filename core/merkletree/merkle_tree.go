@@ -1,6 +1,7 @@
 package merkletree
 
 import (
+	"bytes"
 	"encoding/hex"
 	"errors"
 	"github.com/iost-official/go-iost/common"
@@ -69,6 +70,29 @@ func (m *MerkleTree) MerklePath(hash []byte) ([][]byte, error) {
 	return mp, nil
 }
 
+// VerifyMerkleProof checks that leaf is the index-th leaf of the tree with the root,
+// using the path returned by MerklePath.
+func VerifyMerkleProof(leaf, root []byte, index int32, proof [][]byte) bool {
+	if leaf == nil || root == nil || index < 0 || index >= 1<<uint(len(proof)) {
+		return false
+	}
+	if len(proof) == 0 {
+		// A tree with a single leaf hashes the leaf with itself.
+		return bytes.Equal(common.Sha3(append(leaf, leaf...)), root)
+	}
+	hash := leaf
+	idx := index + 1<<uint(len(proof)) - 1
+	for _, p := range proof {
+		if idx%2 == 1 {
+			hash = common.Sha3(append(append([]byte{}, hash...), p...))
+		} else {
+			hash = common.Sha3(append(append([]byte{}, p...), hash...))
+		}
+		idx = (idx - 1) / 2
+	}
+	return bytes.Equal(hash, root)
+}
+
 // MerkleProve is prove of the merkle tree
 //func (m *MerkleTree) MerkleProve(hash []byte, rootHash []byte, mp [][]byte) (bool, error) {
 //	if hash == nil {
@@ -79,6 +79,28 @@ func BenchmarkBuild(b *testing.B) { // 646503ns = 0.6ms，vs 117729ns = 0.1ms
 	}
 }
 
+func TestVerifyMerkleProof(t *testing.T) {
+	Convey("Test of VerifyMerkleProof", t, func() {
+		for n := 1; n <= 9; n++ {
+			var data [][]byte
+			for i := 0; i < n; i++ {
+				data = append(data, RandHash(32))
+			}
+			m := MerkleTree{}
+			m.Build(data)
+			for i, datum := range data {
+				mp, err := m.MerklePath(datum)
+				So(err, ShouldBeNil)
+				So(VerifyMerkleProof(datum, m.RootHash(), int32(i), mp), ShouldBeTrue)
+				So(VerifyMerkleProof(RandHash(32), m.RootHash(), int32(i), mp), ShouldBeFalse)
+				if n > 1 {
+					So(VerifyMerkleProof(datum, m.RootHash(), int32((i+1)%n), mp), ShouldBeFalse)
+				}
+			}
+		}
+	})
+}
+
 func BenchmarkMerklePath(b *testing.B) { // 183ns
 	rand.Seed(time.Now().UnixNano())
 	var data [][]byte
@@ -55,6 +55,10 @@ func (a *Action) Decode(b []byte) error {
 	if err != nil {
 		return err
 	}
+	err = ac.CheckUnknownFields()
+	if err != nil {
+		return err
+	}
 	a.FromPb(ac)
 	return nil
 }
@@ -0,0 +1,64 @@
+package tx
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/iost-official/go-iost/common"
+	"github.com/iost-official/go-iost/core/contract"
+)
+
+// GenesisPublisher is the publisher of the genesis tx, an account nobody can sign for.
+const GenesisPublisher = "deadaddr"
+
+// errors
+var (
+	ErrGenesisAllocation = errors.New("invalid genesis allocation")
+	ErrGenesisOverflow   = errors.New("genesis allocations overflow")
+	ErrGenesisContract   = errors.New("invalid genesis system contract")
+)
+
+// BuildGenesisTx returns the tx of block 0, which sets the code of the system contracts in order
+// and issues the allocations, in the smallest unit of iost, to the accounts sorted by id.
+func BuildGenesisTx(allocations map[string]int64, systemContracts []*contract.Contract) (*Tx, error) {
+	var acts []*Action
+	for _, c := range systemContracts {
+		if c == nil || c.ID == "" {
+			return nil, ErrGenesisContract
+		}
+		if err := c.VerifySelf(); err != nil {
+			return nil, fmt.Errorf("%v: %v, %v", ErrGenesisContract, c.ID, err)
+		}
+		acts = append(acts, NewAction("system.iost", "initSetCode", fmt.Sprintf(`["%v", "%v"]`, c.ID, c.B64Encode())))
+	}
+
+	ids := make([]string, 0, len(allocations))
+	for id := range allocations {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	var total int64
+	for _, id := range ids {
+		amount := allocations[id]
+		if id == "" || amount <= 0 {
+			return nil, fmt.Errorf("%v: %q, %v", ErrGenesisAllocation, id, amount)
+		}
+		if total > math.MaxInt64-amount {
+			return nil, ErrGenesisOverflow
+		}
+		total += amount
+		value := &common.Fixed{Value: amount, Decimal: TokenDecimals["iost"]}
+		acts = append(acts, NewAction("token.iost", "issue", fmt.Sprintf(`["iost", "%v", "%v"]`, id, value.ToString())))
+	}
+
+	trx := NewTx(acts, nil, 1000000000, 100, 0, 0, ChainID)
+	trx.Time = 0
+	trx, err := SignTx(trx, GenesisPublisher, nil)
+	if err != nil {
+		return nil, err
+	}
+	trx.AmountLimit = append(trx.AmountLimit, &contract.Amount{Token: "*", Val: "unlimited"})
+	return trx, nil
+}
@@ -0,0 +1,38 @@
+package tx
+
+import (
+	"math"
+	"testing"
+
+	"github.com/iost-official/go-iost/core/contract"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestBuildGenesisTx(t *testing.T) {
+	Convey("Test of BuildGenesisTx", t, func() {
+		c := &contract.Contract{
+			ID:   "token.iost",
+			Info: &contract.Info{Lang: "native", Version: "1.0.0"},
+		}
+		trx, err := BuildGenesisTx(map[string]int64{"b": 3 * 1e8, "a": 150000000}, []*contract.Contract{c})
+		So(err, ShouldBeNil)
+		So(trx.Publisher, ShouldEqual, GenesisPublisher)
+		So(trx.Time, ShouldEqual, 0)
+		So(len(trx.Actions), ShouldEqual, 3)
+		So(trx.Actions[0].Contract, ShouldEqual, "system.iost")
+		So(trx.Actions[0].ActionName, ShouldEqual, "initSetCode")
+		So(trx.Actions[0].Data, ShouldEqual, `["token.iost", "`+c.B64Encode()+`"]`)
+		So(trx.Actions[1].Data, ShouldEqual, `["iost", "a", "1.5"]`)
+		So(trx.Actions[2].Data, ShouldEqual, `["iost", "b", "3"]`)
+		So(trx.AmountLimit[0].Val, ShouldEqual, "unlimited")
+
+		Convey("invalid", func() {
+			_, err := BuildGenesisTx(map[string]int64{"a": math.MaxInt64, "b": 1}, nil)
+			So(err, ShouldEqual, ErrGenesisOverflow)
+			_, err = BuildGenesisTx(map[string]int64{"a": 0}, nil)
+			So(err.Error(), ShouldContainSubstring, ErrGenesisAllocation.Error())
+			_, err = BuildGenesisTx(nil, []*contract.Contract{{}})
+			So(err, ShouldEqual, ErrGenesisContract)
+		})
+	})
+}
@@ -0,0 +1,33 @@
+package tx
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+// MaxMemoLen is the max length in bytes of the memo of a tx accepted by CheckMemo.
+var MaxMemoLen = 512
+
+// Memo returns the memo of the tx, "" if there is none.
+func (t *Tx) Memo() string {
+	return t.memo
+}
+
+// SetMemo sets the memo of the tx, it should be called before signing.
+func (t *Tx) SetMemo(memo string) {
+	t.memo = memo
+	t.hash = nil
+}
+
+// CheckMemo checks that the memo of the tx is valid UTF-8 and at most MaxMemoLen bytes.
+func CheckMemo(t *Tx) error {
+	memo := t.Memo()
+	if len(memo) > MaxMemoLen {
+		return fmt.Errorf("memo too long: %v bytes, the limit is %v", len(memo), MaxMemoLen)
+	}
+	if !utf8.ValidString(memo) {
+		return errors.New("memo is not valid UTF-8")
+	}
+	return nil
+}
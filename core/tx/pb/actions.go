@@ -0,0 +1,59 @@
+package txpb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// action validation errors
+var (
+	ErrEmptyContract   = errors.New("action contract is empty")
+	ErrEmptyActionName = errors.New("action name is empty")
+	ErrInvalidData     = errors.New("action data is not valid json")
+)
+
+// Validate checks the fields of a decoded action.
+func (m *Action) Validate() error {
+	if m.GetContract() == "" {
+		return ErrEmptyContract
+	}
+	if m.GetActionName() == "" {
+		return ErrEmptyActionName
+	}
+	if !json.Valid([]byte(m.GetData())) {
+		return ErrInvalidData
+	}
+	return nil
+}
+
+// Validate checks every action of a decoded tx.
+func (m *Tx) Validate() error {
+	for i, a := range m.GetActions() {
+		if err := a.Validate(); err != nil {
+			return fmt.Errorf("action %d %v.%v: %v", i, a.GetContract(), a.GetActionName(), err)
+		}
+	}
+	return nil
+}
+
+// ActionsFor returns the actions of the tx calling the contract.
+func (m *Tx) ActionsFor(contract string) []*Action {
+	var actions []*Action
+	for _, a := range m.GetActions() {
+		if a.GetContract() == contract {
+			actions = append(actions, a)
+		}
+	}
+	return actions
+}
+
+// HasAction returns whether the tx calls the action of the contract.
+func (m *Tx) HasAction(contract, actionName string) bool {
+	for _, a := range m.GetActions() {
+		if a.GetContract() == contract && a.GetActionName() == actionName {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package txpb
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTxActions(t *testing.T) {
+	tx := &Tx{
+		Actions: []*Action{
+			{Contract: "token.iost", ActionName: "transfer", Data: "[]"},
+			{Contract: "system.iost", ActionName: "receipt", Data: "[]"},
+			{Contract: "token.iost", ActionName: "issue", Data: "[]"},
+			{Contract: "token.iost2", ActionName: "transfer", Data: "[]"},
+		},
+	}
+
+	actions := tx.ActionsFor("token.iost")
+	if len(actions) != 2 || actions[0].ActionName != "transfer" || actions[1].ActionName != "issue" {
+		t.Fatalf("ActionsFor(token.iost) got %v", actions)
+	}
+	if actions := tx.ActionsFor("token"); len(actions) != 0 {
+		t.Fatalf("ActionsFor(token) should be empty, got %v", actions)
+	}
+	if actions := (&Tx{}).ActionsFor("token.iost"); len(actions) != 0 {
+		t.Fatalf("ActionsFor on empty tx should be empty, got %v", actions)
+	}
+
+	tests := []struct {
+		contract   string
+		actionName string
+		want       bool
+	}{
+		{"token.iost", "transfer", true},
+		{"token.iost", "issue", true},
+		{"system.iost", "receipt", true},
+		{"token.iost2", "transfer", true},
+		{"system.iost", "transfer", false},
+		{"token.iost", "Transfer", false},
+		{"token.iost", "", false},
+		{"", "transfer", false},
+	}
+	for _, tt := range tests {
+		if got := tx.HasAction(tt.contract, tt.actionName); got != tt.want {
+			t.Errorf("HasAction(%v, %v) = %v, want %v", tt.contract, tt.actionName, got, tt.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		action *Action
+		err    error
+	}{
+		{"valid", &Action{Contract: "token.iost", ActionName: "transfer", Data: `["iost", "a", "b", "1", ""]`}, nil},
+		{"empty contract", &Action{ActionName: "transfer", Data: "[]"}, ErrEmptyContract},
+		{"empty action name", &Action{Contract: "token.iost", Data: "[]"}, ErrEmptyActionName},
+		{"empty data", &Action{Contract: "token.iost", ActionName: "transfer"}, ErrInvalidData},
+		{"malformed data", &Action{Contract: "token.iost", ActionName: "transfer", Data: `["iost",`}, ErrInvalidData},
+	}
+	for _, tt := range tests {
+		if err := tt.action.Validate(); err != tt.err {
+			t.Errorf("%v: Validate() = %v, want %v", tt.name, err, tt.err)
+		}
+	}
+
+	tx := &Tx{Actions: []*Action{tests[0].action}}
+	if err := tx.Validate(); err != nil {
+		t.Fatalf("valid tx got %v", err)
+	}
+	tx.Actions = append(tx.Actions, &Action{Contract: "token.iost", Data: "[]"})
+	if err := tx.Validate(); err == nil || !strings.Contains(err.Error(), ErrEmptyActionName.Error()) {
+		t.Fatalf("tx with invalid action got %v", err)
+	}
+}
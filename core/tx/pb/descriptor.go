@@ -0,0 +1,17 @@
+package txpb
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+)
+
+// TxFileDescriptor returns the decompressed FileDescriptorProto bytes of tx.proto.
+func TxFileDescriptor() ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(fileDescriptor_a5cd2a43d9b9fb36))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
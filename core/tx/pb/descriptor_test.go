@@ -0,0 +1,22 @@
+package txpb
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/protoc-gen-go/descriptor"
+)
+
+func TestTxFileDescriptor(t *testing.T) {
+	b, err := TxFileDescriptor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fd descriptor.FileDescriptorProto
+	if err := proto.Unmarshal(b, &fd); err != nil {
+		t.Fatal(err)
+	}
+	if fd.GetName() != "core/tx/pb/tx.proto" || len(fd.GetMessageType()) == 0 {
+		t.Fatal(fd.String())
+	}
+}
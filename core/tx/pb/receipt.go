@@ -0,0 +1,26 @@
+package txpb
+
+// StatusCodeUnknown is the status code of a receipt without status, same as tx.ErrorUnknown.
+const StatusCodeUnknown int32 = 8
+
+// IsSuccess returns whether the receipt has a success status, a receipt without status is not.
+func (m *TxReceipt) IsSuccess() bool {
+	return m.GetStatus() != nil && m.GetStatus().GetCode() == 0
+}
+
+// StatusCode returns the status code of the receipt, StatusCodeUnknown if status is not set.
+func (m *TxReceipt) StatusCode() int32 {
+	if m.GetStatus() == nil {
+		return StatusCodeUnknown
+	}
+	return m.GetStatus().GetCode()
+}
+
+// GasByContract returns the gas spent by the actions of the tx tallied by contract, in the unit of GasUsage.
+func (m *TxReceipt) GasByContract() map[string]int64 {
+	gas := make(map[string]int64, len(m.GetContractGas()))
+	for k, v := range m.GetContractGas() {
+		gas[k] = v
+	}
+	return gas
+}
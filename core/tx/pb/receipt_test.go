@@ -0,0 +1,26 @@
+package txpb
+
+import "testing"
+
+func TestTxReceiptStatus(t *testing.T) {
+	var nilReceipt *TxReceipt
+	tests := []struct {
+		name    string
+		r       *TxReceipt
+		success bool
+		code    int32
+	}{
+		{"nil receipt", nilReceipt, false, StatusCodeUnknown},
+		{"nil status", &TxReceipt{}, false, StatusCodeUnknown},
+		{"success", &TxReceipt{Status: &Status{Code: 0}}, true, 0},
+		{"runtime error", &TxReceipt{Status: &Status{Code: 4, Message: "error"}}, false, 4},
+	}
+	for _, tt := range tests {
+		if got := tt.r.IsSuccess(); got != tt.success {
+			t.Errorf("%v: IsSuccess() = %v, want %v", tt.name, got, tt.success)
+		}
+		if got := tt.r.StatusCode(); got != tt.code {
+			t.Errorf("%v: StatusCode() = %v, want %v", tt.name, got, tt.code)
+		}
+	}
+}
@@ -0,0 +1,8 @@
+package txpb
+
+import "github.com/golang/protobuf/proto"
+
+// WireSize returns the encoded length of the tx, including the nested actions and signatures, without marshaling it.
+func (m *Tx) WireSize() int {
+	return proto.Size(m)
+}
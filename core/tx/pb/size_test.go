@@ -0,0 +1,40 @@
+package txpb
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/iost-official/go-iost/core/contract"
+	pb "github.com/iost-official/go-iost/crypto/pb"
+)
+
+func TestWireSize(t *testing.T) {
+	txs := []*Tx{
+		{},
+		{
+			Time:       1,
+			Expiration: 2,
+			GasLimit:   100000,
+			GasRatio:   100,
+			Actions: []*Action{
+				{Contract: "token.iost", ActionName: "transfer", Data: `["iost","a","b","1",""]`},
+				{Contract: "system.iost", ActionName: "receipt", Data: `["hello"]`},
+			},
+			Signers:      []string{"a@active"},
+			Signs:        []*pb.Signature{{Algorithm: 2, Sig: make([]byte, 64), PubKey: make([]byte, 32)}},
+			Publisher:    "a",
+			PublishSigns: []*pb.Signature{{Algorithm: 1, Sig: make([]byte, 64), PubKey: make([]byte, 33)}},
+			AmountLimit:  []*contract.Amount{{Token: "iost", Val: "10"}},
+			Reserved:     []byte{1, 2, 3},
+		},
+	}
+	for _, m := range txs {
+		b, err := proto.Marshal(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if m.WireSize() != len(b) {
+			t.Fatalf("WireSize %v != len(Marshal()) %v", m.WireSize(), len(b))
+		}
+	}
+}
@@ -92,6 +92,9 @@ type Tx struct {
 	ReferredTx           []byte             `protobuf:"bytes,12,opt,name=referredTx,proto3" json:"referredTx,omitempty"`
 	AmountLimit          []*contract.Amount `protobuf:"bytes,13,rep,name=amountLimit,proto3" json:"amountLimit,omitempty"`
 	Reserved             []byte             `protobuf:"bytes,14,opt,name=reserved,proto3" json:"reserved,omitempty"`
+	Tip                  int64              `protobuf:"varint,15,opt,name=tip,proto3" json:"tip,omitempty"`
+	SignHash             uint32             `protobuf:"varint,16,opt,name=signHash,proto3" json:"signHash,omitempty"`
+	Memo                 string             `protobuf:"bytes,17,opt,name=memo,proto3" json:"memo,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
 	XXX_unrecognized     []byte             `json:"-"`
 	XXX_sizecache        int32              `json:"-"`
@@ -220,6 +223,27 @@ func (m *Tx) GetReserved() []byte {
 	return nil
 }
 
+func (m *Tx) GetTip() int64 {
+	if m != nil {
+		return m.Tip
+	}
+	return 0
+}
+
+func (m *Tx) GetSignHash() uint32 {
+	if m != nil {
+		return m.SignHash
+	}
+	return 0
+}
+
+func (m *Tx) GetMemo() string {
+	if m != nil {
+		return m.Memo
+	}
+	return ""
+}
+
 type Receipt struct {
 	FuncName             string   `protobuf:"bytes,1,opt,name=funcName,proto3" json:"funcName,omitempty"`
 	Content              string   `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
@@ -321,6 +345,7 @@ type TxReceipt struct {
 	Status               *Status          `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
 	Returns              []string         `protobuf:"bytes,5,rep,name=returns,proto3" json:"returns,omitempty"`
 	Receipts             []*Receipt       `protobuf:"bytes,6,rep,name=receipts,proto3" json:"receipts,omitempty"`
+	ContractGas          map[string]int64 `protobuf:"bytes,7,rep,name=contractGas,proto3" json:"contractGas,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
 	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
 	XXX_unrecognized     []byte           `json:"-"`
 	XXX_sizecache        int32            `json:"-"`
@@ -393,54 +418,66 @@ func (m *TxReceipt) GetReceipts() []*Receipt {
 	return nil
 }
 
+func (m *TxReceipt) GetContractGas() map[string]int64 {
+	if m != nil {
+		return m.ContractGas
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Action)(nil), "txpb.Action")
 	proto.RegisterType((*Tx)(nil), "txpb.Tx")
 	proto.RegisterType((*Receipt)(nil), "txpb.Receipt")
 	proto.RegisterType((*Status)(nil), "txpb.Status")
 	proto.RegisterType((*TxReceipt)(nil), "txpb.TxReceipt")
+	proto.RegisterMapType((map[string]int64)(nil), "txpb.TxReceipt.ContractGasEntry")
 	proto.RegisterMapType((map[string]int64)(nil), "txpb.TxReceipt.RamUsageEntry")
 }
 
 func init() { proto.RegisterFile("core/tx/pb/tx.proto", fileDescriptor_a5cd2a43d9b9fb36) }
 
 var fileDescriptor_a5cd2a43d9b9fb36 = []byte{
-	// 582 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x53, 0x5f, 0x8b, 0x13, 0x3f,
-	0x14, 0xa5, 0x9d, 0xed, 0xbf, 0xdb, 0xf6, 0xc7, 0x92, 0x9f, 0x48, 0x2c, 0x2a, 0xa5, 0xc8, 0x52,
-	0x1f, 0x76, 0x0a, 0xab, 0x88, 0xae, 0x88, 0xec, 0x83, 0xa0, 0x20, 0x3e, 0x64, 0x2b, 0xf8, 0x26,
-	0x99, 0x99, 0x74, 0x1a, 0xec, 0x4c, 0x86, 0x24, 0xb3, 0x4c, 0xbf, 0x8e, 0x5f, 0xc8, 0xaf, 0x24,
-	0xb9, 0xc9, 0x8c, 0xdd, 0x07, 0xf1, 0xed, 0x9e, 0x9c, 0x7b, 0x4f, 0x6e, 0x0e, 0x27, 0xf0, 0x7f,
-	0xaa, 0xb4, 0xd8, 0xd8, 0x66, 0x53, 0x25, 0x1b, 0xdb, 0xc4, 0x95, 0x56, 0x56, 0x91, 0x33, 0xdb,
-	0x54, 0xc9, 0xe2, 0x3a, 0x97, 0x76, 0x5f, 0x27, 0x71, 0xaa, 0x8a, 0x8d, 0x54, 0xc6, 0x5e, 0xaa,
-	0xdd, 0x4e, 0xa6, 0x92, 0x1f, 0x36, 0xb9, 0xba, 0x74, 0x07, 0x9b, 0x54, 0x1f, 0x2b, 0xab, 0xdc,
-	0xa8, 0x91, 0x79, 0xc9, 0x6d, 0xad, 0x85, 0x57, 0x58, 0xbc, 0xfb, 0xf7, 0xac, 0xbb, 0x37, 0x55,
-	0xa5, 0xd5, 0x3c, 0xb5, 0x5d, 0xe1, 0xc7, 0x57, 0xdf, 0x60, 0x78, 0x93, 0x5a, 0xa9, 0x4a, 0xb2,
-	0x80, 0x71, 0xcb, 0xd1, 0xde, 0xb2, 0xb7, 0x9e, 0xb0, 0x0e, 0x93, 0xa7, 0x00, 0x1c, 0xbb, 0xbe,
-	0xf0, 0x42, 0xd0, 0x3e, 0xb2, 0x27, 0x27, 0x84, 0xc0, 0x59, 0xc6, 0x2d, 0xa7, 0x11, 0x32, 0x58,
-	0xaf, 0x7e, 0x45, 0xd0, 0xdf, 0x36, 0x8e, 0xb2, 0xb2, 0x10, 0x28, 0x19, 0x31, 0xac, 0x9d, 0x9c,
-	0x68, 0x2a, 0xa9, 0xb9, 0x13, 0x40, 0xb9, 0x88, 0x9d, 0x9c, 0xb8, 0x55, 0x72, 0x6e, 0x3e, 0xcb,
-	0x42, 0x5a, 0x94, 0x8c, 0x58, 0x87, 0x03, 0xc7, 0x5c, 0x23, 0x3d, 0xeb, 0x38, 0xc4, 0xe4, 0x02,
-	0x46, 0x7e, 0x29, 0x43, 0x07, 0xcb, 0x68, 0x3d, 0xbd, 0x9a, 0xc5, 0xce, 0xdf, 0xd8, 0xbf, 0x90,
-	0xb5, 0x24, 0xa1, 0x30, 0x72, 0x36, 0x0a, 0x6d, 0xe8, 0x70, 0x19, 0xad, 0x27, 0xac, 0x85, 0xe4,
-	0x02, 0x06, 0xae, 0x34, 0x74, 0x84, 0xf3, 0xe7, 0xb1, 0x91, 0x79, 0x95, 0xc4, 0xb7, 0xad, 0xe9,
-	0xcc, 0xd3, 0xe4, 0x31, 0x4c, 0xaa, 0x3a, 0x39, 0x48, 0xb3, 0x17, 0x9a, 0x8e, 0xf1, 0xd5, 0x7f,
-	0x0e, 0xc8, 0x4b, 0x98, 0x05, 0x70, 0x8b, 0x62, 0x93, 0xbf, 0x88, 0xdd, 0xeb, 0x22, 0x0f, 0x60,
-	0x90, 0x89, 0x03, 0x3f, 0x52, 0xc0, 0x67, 0x79, 0x40, 0x1e, 0xc1, 0x38, 0xdd, 0x73, 0x59, 0x7e,
-	0x97, 0x19, 0x9d, 0x2e, 0x7b, 0xeb, 0x39, 0x1b, 0x21, 0xfe, 0x94, 0x39, 0x1b, 0xb5, 0xd8, 0x09,
-	0xad, 0x45, 0xb6, 0x6d, 0xe8, 0x6c, 0xd9, 0x5b, 0xcf, 0xd8, 0xc9, 0x09, 0xb9, 0x82, 0x29, 0x2f,
-	0x54, 0x5d, 0x5a, 0xef, 0xe4, 0x3c, 0x6c, 0xd1, 0x25, 0xe0, 0x06, 0x49, 0x76, 0xda, 0xe4, 0xec,
-	0xd5, 0xc2, 0x08, 0x7d, 0x27, 0x32, 0xfa, 0x1f, 0x2a, 0x76, 0x78, 0xf5, 0x1e, 0x46, 0x4c, 0xa4,
-	0x42, 0x56, 0xd8, 0xb6, 0xab, 0xcb, 0x14, 0xe3, 0x10, 0xc2, 0xd2, 0x62, 0xe7, 0xae, 0xbb, 0x42,
-	0x94, 0x36, 0x24, 0xa5, 0x85, 0xab, 0x57, 0x30, 0xbc, 0xb5, 0xdc, 0xd6, 0xc6, 0xa5, 0x22, 0x55,
-	0x99, 0x9f, 0x1d, 0x30, 0xac, 0xdd, 0x5c, 0x21, 0x8c, 0xe1, 0x79, 0x9b, 0xb0, 0x16, 0xae, 0x7e,
-	0xf6, 0x61, 0xb2, 0x6d, 0xda, 0xbb, 0x1f, 0xc2, 0xd0, 0x36, 0x1f, 0xb9, 0xd9, 0xe3, 0xf4, 0x8c,
-	0x05, 0x14, 0x92, 0xf1, 0xb5, 0x13, 0xf0, 0xc9, 0x40, 0x4c, 0xde, 0xc0, 0x58, 0xf3, 0xc2, 0x73,
-	0x11, 0xfa, 0xf0, 0xc4, 0x47, 0xa3, 0x93, 0x8d, 0x59, 0xe0, 0x3f, 0x94, 0x56, 0x1f, 0x59, 0xd7,
-	0x4e, 0x9e, 0xc1, 0xd0, 0xe0, 0xd2, 0x18, 0xb7, 0x2e, 0x53, 0xfe, 0x21, 0x2c, 0x70, 0x6e, 0x79,
-	0x2d, 0x6c, 0xad, 0x43, 0xf4, 0x26, 0xac, 0x85, 0xe4, 0xb9, 0x73, 0x14, 0xaf, 0xf0, 0x69, 0x9b,
-	0x5e, 0xcd, 0xbd, 0x42, 0xb8, 0x98, 0x75, 0xf4, 0xe2, 0x2d, 0xcc, 0xef, 0x6d, 0x41, 0xce, 0x21,
-	0xfa, 0x21, 0x8e, 0xc1, 0x61, 0x57, 0xba, 0x90, 0xdc, 0xf1, 0x43, 0xdd, 0xbe, 0xd0, 0x83, 0xeb,
-	0xfe, 0xeb, 0x5e, 0x32, 0xc4, 0x0f, 0xfd, 0xe2, 0x77, 0x00, 0x00, 0x00, 0xff, 0xff, 0x09, 0xd7,
-	0x47, 0x80, 0x68, 0x04, 0x00, 0x00,
+	// 642 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x54, 0x5d, 0x8b, 0x13, 0x4b,
+	0x10, 0x25, 0x3b, 0xf9, 0xac, 0x24, 0xf7, 0xe6, 0xf6, 0xbd, 0x5c, 0xda, 0xa0, 0x12, 0x82, 0x2c,
+	0xf1, 0x61, 0x27, 0xb0, 0x8a, 0xe8, 0x8a, 0xca, 0x2a, 0xa2, 0x82, 0xf8, 0xd0, 0xbb, 0x82, 0x6f,
+	0xd2, 0x99, 0xe9, 0x24, 0x8d, 0x99, 0x0f, 0xba, 0x7b, 0x96, 0xc9, 0xcf, 0xf0, 0x4f, 0xf8, 0x3b,
+	0xa5, 0xaa, 0x67, 0x66, 0xb3, 0x0b, 0x22, 0xbe, 0xd5, 0x99, 0x53, 0x75, 0xba, 0x3e, 0x0e, 0x03,
+	0xff, 0x46, 0x99, 0x51, 0x4b, 0x57, 0x2e, 0xf3, 0xd5, 0xd2, 0x95, 0x61, 0x6e, 0x32, 0x97, 0xb1,
+	0xb6, 0x2b, 0xf3, 0xd5, 0xf4, 0x6c, 0xa3, 0xdd, 0xb6, 0x58, 0x85, 0x51, 0x96, 0x2c, 0x75, 0x66,
+	0xdd, 0x49, 0xb6, 0x5e, 0xeb, 0x48, 0xcb, 0xdd, 0x72, 0x93, 0x9d, 0xe0, 0x87, 0x65, 0x64, 0xf6,
+	0xb9, 0xcb, 0xb0, 0xd4, 0xea, 0x4d, 0x2a, 0x5d, 0x61, 0x94, 0x57, 0x98, 0xbe, 0xf8, 0x7d, 0x2d,
+	0xbe, 0x1b, 0x65, 0xa9, 0x33, 0x32, 0x72, 0x4d, 0xe0, 0xcb, 0xe7, 0x5f, 0xa0, 0x7b, 0x1e, 0x39,
+	0x9d, 0xa5, 0x6c, 0x0a, 0xfd, 0x9a, 0xe3, 0xad, 0x59, 0x6b, 0x31, 0x10, 0x0d, 0x66, 0xf7, 0x01,
+	0x24, 0x65, 0x7d, 0x92, 0x89, 0xe2, 0x47, 0xc4, 0x1e, 0x7c, 0x61, 0x0c, 0xda, 0xb1, 0x74, 0x92,
+	0x07, 0xc4, 0x50, 0x3c, 0xff, 0xde, 0x86, 0xa3, 0xcb, 0x12, 0x29, 0xa7, 0x13, 0x45, 0x92, 0x81,
+	0xa0, 0x18, 0xe5, 0x54, 0x99, 0x6b, 0x23, 0x51, 0x80, 0xe4, 0x02, 0x71, 0xf0, 0x05, 0x5b, 0xd9,
+	0x48, 0xfb, 0x51, 0x27, 0xda, 0x91, 0x64, 0x20, 0x1a, 0x5c, 0x71, 0x02, 0x13, 0x79, 0xbb, 0xe1,
+	0x08, 0xb3, 0x63, 0xe8, 0xf9, 0xa6, 0x2c, 0xef, 0xcc, 0x82, 0xc5, 0xf0, 0x74, 0x14, 0xe2, 0x7e,
+	0x43, 0x3f, 0xa1, 0xa8, 0x49, 0xc6, 0xa1, 0x87, 0x6b, 0x54, 0xc6, 0xf2, 0xee, 0x2c, 0x58, 0x0c,
+	0x44, 0x0d, 0xd9, 0x31, 0x74, 0x30, 0xb4, 0xbc, 0x47, 0xf5, 0x93, 0xd0, 0xea, 0x4d, 0xbe, 0x0a,
+	0x2f, 0xea, 0xa5, 0x0b, 0x4f, 0xb3, 0xbb, 0x30, 0xc8, 0x8b, 0xd5, 0x4e, 0xdb, 0xad, 0x32, 0xbc,
+	0x4f, 0x53, 0x5f, 0x7f, 0x60, 0x8f, 0x61, 0x54, 0x81, 0x0b, 0x12, 0x1b, 0xfc, 0x42, 0xec, 0x46,
+	0x16, 0xfb, 0x0f, 0x3a, 0xb1, 0xda, 0xc9, 0x3d, 0x07, 0x1a, 0xcb, 0x03, 0x76, 0x07, 0xfa, 0xd1,
+	0x56, 0xea, 0xf4, 0xab, 0x8e, 0xf9, 0x70, 0xd6, 0x5a, 0x8c, 0x45, 0x8f, 0xf0, 0x87, 0x18, 0xd7,
+	0x68, 0xd4, 0x5a, 0x19, 0xa3, 0xe2, 0xcb, 0x92, 0x8f, 0x66, 0xad, 0xc5, 0x48, 0x1c, 0x7c, 0x61,
+	0xa7, 0x30, 0x94, 0x49, 0x56, 0xa4, 0xce, 0x6f, 0x72, 0x5c, 0x75, 0xd1, 0x38, 0xe0, 0x9c, 0x48,
+	0x71, 0x98, 0x84, 0xeb, 0x35, 0xca, 0x2a, 0x73, 0xa5, 0x62, 0xfe, 0x17, 0x29, 0x36, 0x98, 0x4d,
+	0x20, 0x70, 0x3a, 0xe7, 0x7f, 0x53, 0x7b, 0x18, 0x62, 0x36, 0xee, 0xe3, 0xbd, 0xb4, 0x5b, 0x3e,
+	0xa1, 0xe6, 0x1a, 0x8c, 0x87, 0x4f, 0x54, 0x92, 0xf1, 0x7f, 0xbc, 0x27, 0x30, 0x9e, 0xbf, 0x82,
+	0x9e, 0x50, 0x91, 0xd2, 0x39, 0x3d, 0xb4, 0x2e, 0xd2, 0x88, 0x0c, 0x55, 0xd9, 0xad, 0xc6, 0x78,
+	0x1f, 0x6c, 0x52, 0xa5, 0xae, 0xf2, 0x5a, 0x0d, 0xe7, 0x4f, 0xa0, 0x7b, 0xe1, 0xa4, 0x2b, 0x2c,
+	0xca, 0x47, 0x59, 0xec, 0x6b, 0x3b, 0x82, 0x62, 0xac, 0x4b, 0x94, 0xb5, 0x72, 0x53, 0x7b, 0xb4,
+	0x86, 0xf3, 0x1f, 0x01, 0x0c, 0x2e, 0xcb, 0xfa, 0xed, 0xff, 0xa1, 0xeb, 0x4a, 0x6a, 0xba, 0x45,
+	0x23, 0x56, 0xa8, 0xf2, 0xd6, 0xe7, 0x46, 0xc0, 0x7b, 0x8b, 0x30, 0x7b, 0x06, 0x7d, 0x23, 0x13,
+	0xcf, 0x05, 0xb4, 0xc9, 0x7b, 0xde, 0x5c, 0x8d, 0x6c, 0x28, 0x2a, 0xfe, 0x6d, 0xea, 0xcc, 0x5e,
+	0x34, 0xe9, 0xec, 0x01, 0x74, 0x2d, 0x35, 0x4d, 0x86, 0x6d, 0x5c, 0xe9, 0x07, 0x11, 0x15, 0x87,
+	0xcd, 0x1b, 0xe5, 0x0a, 0x53, 0x99, 0x77, 0x20, 0x6a, 0xc8, 0x1e, 0xe2, 0x4d, 0xe8, 0x09, 0xef,
+	0xd7, 0xe1, 0xe9, 0xd8, 0x2b, 0x54, 0x0f, 0x8b, 0x86, 0x66, 0xaf, 0x61, 0x58, 0x9f, 0xf7, 0x9d,
+	0xac, 0x5d, 0x3c, 0xbb, 0xdd, 0xe8, 0x9b, 0xeb, 0x14, 0xdf, 0xeb, 0x61, 0xd1, 0xf4, 0x39, 0x8c,
+	0x6f, 0x4c, 0x82, 0x77, 0xff, 0xa6, 0xf6, 0xd5, 0x95, 0x30, 0x44, 0xab, 0x5e, 0xc9, 0x5d, 0x51,
+	0x6f, 0xc9, 0x83, 0xb3, 0xa3, 0xa7, 0xad, 0xe9, 0x4b, 0x98, 0xdc, 0x56, 0xff, 0x93, 0xfa, 0x55,
+	0x97, 0x7e, 0x4b, 0x8f, 0x7e, 0x06, 0x00, 0x00, 0xff, 0xff, 0x17, 0x64, 0x54, 0xee, 0x2e, 0x05,
+	0x00, 0x00,
 }
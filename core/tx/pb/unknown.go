@@ -0,0 +1,49 @@
+package txpb
+
+import "errors"
+
+// MaxUnknownFieldsSize is the max bytes of unknown fields kept by a decoded tx or action, 0 means no limit.
+var MaxUnknownFieldsSize = 1024
+
+// ErrUnknownFieldsTooLarge is returned when a decoded message carries too many unknown fields.
+var ErrUnknownFieldsTooLarge = errors.New("unknown fields too large")
+
+// UnknownFieldsSize returns the bytes of unknown fields in the action.
+func (m *Action) UnknownFieldsSize() int {
+	return len(m.XXX_unrecognized)
+}
+
+// UnknownFieldsSize returns the bytes of unknown fields in the tx and its nested messages.
+func (m *Tx) UnknownFieldsSize() int {
+	size := len(m.XXX_unrecognized)
+	for _, a := range m.Actions {
+		size += a.UnknownFieldsSize()
+	}
+	for _, s := range m.Signs {
+		size += len(s.XXX_unrecognized)
+	}
+	for _, s := range m.PublishSigns {
+		size += len(s.XXX_unrecognized)
+	}
+	for _, a := range m.AmountLimit {
+		size += len(a.XXX_unrecognized)
+	}
+	return size
+}
+
+// CheckUnknownFields checks the unknown fields of the action against MaxUnknownFieldsSize.
+func (m *Action) CheckUnknownFields() error {
+	return checkUnknownFieldsSize(m.UnknownFieldsSize())
+}
+
+// CheckUnknownFields checks the unknown fields of the tx against MaxUnknownFieldsSize.
+func (m *Tx) CheckUnknownFields() error {
+	return checkUnknownFieldsSize(m.UnknownFieldsSize())
+}
+
+func checkUnknownFieldsSize(size int) error {
+	if MaxUnknownFieldsSize > 0 && size > MaxUnknownFieldsSize {
+		return ErrUnknownFieldsTooLarge
+	}
+	return nil
+}
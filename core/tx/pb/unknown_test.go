@@ -0,0 +1,64 @@
+package txpb
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+)
+
+// unknownField encodes a bytes field with a number not in the proto.
+func unknownField(size int) []byte {
+	b := proto.NewBuffer(nil)
+	b.EncodeVarint(uint64(99<<3 | proto.WireBytes))
+	b.EncodeRawBytes(bytes.Repeat([]byte{'x'}, size))
+	return b.Bytes()
+}
+
+func TestUnknownFields(t *testing.T) {
+	raw, err := proto.Marshal(&Tx{
+		GasLimit: 100000,
+		Actions:  []*Action{{Contract: "token.iost", ActionName: "transfer", Data: "[]"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := &Tx{}
+	if err := proto.Unmarshal(append(raw, unknownField(10)...), m); err != nil {
+		t.Fatal(err)
+	}
+	if m.UnknownFieldsSize() == 0 {
+		t.Fatalf("unknown fields should be kept")
+	}
+	if err := m.CheckUnknownFields(); err != nil {
+		t.Fatalf("small unknown fields got %v", err)
+	}
+
+	m = &Tx{}
+	if err := proto.Unmarshal(append(raw, unknownField(MaxUnknownFieldsSize)...), m); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.CheckUnknownFields(); err != ErrUnknownFieldsTooLarge {
+		t.Fatalf("oversized unknown fields expect %v, got %v", ErrUnknownFieldsTooLarge, err)
+	}
+
+	actRaw, err := proto.Marshal(&Action{Contract: "token.iost", ActionName: "transfer", Data: "[]"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := &Action{}
+	if err := proto.Unmarshal(append(actRaw, unknownField(MaxUnknownFieldsSize)...), a); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.CheckUnknownFields(); err != ErrUnknownFieldsTooLarge {
+		t.Fatalf("oversized unknown fields of action expect %v, got %v", ErrUnknownFieldsTooLarge, err)
+	}
+
+	old := MaxUnknownFieldsSize
+	MaxUnknownFieldsSize = 0
+	defer func() { MaxUnknownFieldsSize = old }()
+	if err := a.CheckUnknownFields(); err != nil {
+		t.Fatalf("no limit got %v", err)
+	}
+}
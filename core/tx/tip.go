@@ -0,0 +1,42 @@
+package tx
+
+import (
+	"fmt"
+
+	"github.com/iost-official/go-iost/common"
+)
+
+// Tip returns the tip of the tx, a priority fee charged on top of GasRatio, 0 if there is none.
+func (t *Tx) Tip() int64 {
+	return t.tip
+}
+
+// SetTip sets the tip of the tx, it should be called before signing.
+func (t *Tx) SetTip(tip int64) {
+	t.tip = tip
+	t.hash = nil
+}
+
+// SignHash returns the hash function of the payloads signed by the tx, sha3 if there is none.
+func (t *Tx) SignHash() common.SignHash {
+	return t.signHash
+}
+
+// SetSignHash sets the hash function of the payloads signed by the tx, it should be called before signing.
+func (t *Tx) SetSignHash(h common.SignHash) {
+	t.signHash = h
+	t.hash = nil
+}
+
+// EffectiveGasRatio returns the gas ratio used to order and charge txs, which is GasRatio plus the tip.
+func (t *Tx) EffectiveGasRatio() int64 {
+	return t.GasRatio + t.Tip()
+}
+
+// NewGasTipReceipt returns the receipt recording the base gas ratio and the tip of a tx.
+func NewGasTipReceipt(gasRatio, tip int64) *Receipt {
+	return &Receipt{
+		FuncName: "gas_tip",
+		Content:  fmt.Sprintf(`{"gas_ratio":%d,"tip":%d}`, gasRatio, tip),
+	}
+}
@@ -4,8 +4,11 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"runtime"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/golang/protobuf/proto"
@@ -28,7 +31,8 @@ const (
 var (
 	MaxExpiration = int64(90 * time.Second)
 	MaxDelay      = int64(720 * time.Hour) // 30 days
-	ChainID       uint32
+	// ChainID is written into the signed bytes of tx, so a tx of another chain fails VerifySelf.
+	ChainID uint32
 )
 
 //go:generate protoc  --go_out=plugins=grpc:. ./core/tx/tx.proto
@@ -60,6 +64,9 @@ type Tx struct {
 	ReferredTx   []byte              `json:"referred_tx"`
 	AmountLimit  []*contract.Amount  `json:"amountLimit"`
 	Reserved     []byte              `json:"reserved"`
+	tip          int64
+	signHash     common.SignHash
+	memo         string
 }
 
 // NewTx return a new Tx
@@ -97,7 +104,7 @@ func (t *Tx) containSigner(id string) bool {
 }
 
 func (t *Tx) baseHash() []byte {
-	return common.Sha3(t.ToBytes(Base))
+	return t.SignHash().Sum(t.ToBytes(Base))
 }
 
 // SignTx sign the whole tx, including signers' signature, only publisher should do this
@@ -113,9 +120,26 @@ func SignTx(tx *Tx, id string, kps []*account.KeyPair, signs ...*crypto.Signatur
 	return tx, nil
 }
 
+// ResignWith clears the signatures of the tx and signs it again as the same publisher with kp,
+// it is used after changing fields such as the gas of a built tx. The signatures of other signers are dropped.
+func (t *Tx) ResignWith(kp *account.KeyPair) error {
+	if len(t.Actions) == 0 {
+		return errors.New("tx has no action")
+	}
+	if t.Publisher == "" {
+		return errors.New("tx has no publisher")
+	}
+	publisher := t.Publisher
+	t.Signs = []*crypto.Signature{}
+	t.Publisher = ""
+	t.hash = nil
+	_, err := SignTx(t, publisher, []*account.KeyPair{kp})
+	return err
+}
+
 // publishHash
 func (t *Tx) publishHash() []byte {
-	return common.Sha3(t.ToBytes(Publish))
+	return t.SignHash().Sum(t.ToBytes(Publish))
 }
 
 // ToPb convert tx to txpb.Tx for transmission.
@@ -130,6 +154,10 @@ func (t *Tx) ToPb() *txpb.Tx {
 		ChainId:     t.ChainID,
 		ReferredTx:  t.ReferredTx,
 		AmountLimit: t.AmountLimit,
+		Reserved:    t.Reserved,
+		Tip:         t.tip,
+		SignHash:    uint32(t.signHash),
+		Memo:        t.memo,
 	}
 	for _, a := range t.Actions {
 		tr.Actions = append(tr.Actions, a.ToPb())
@@ -154,6 +182,11 @@ func (t *Tx) Encode() []byte {
 	return b
 }
 
+// EncodedLen returns the length of the encoded tx.
+func (t *Tx) EncodedLen() int {
+	return t.ToPb().WireSize()
+}
+
 // FromPb convert tx from txpb.Tx.
 func (t *Tx) FromPb(tr *txpb.Tx) *Tx {
 	t.Time = tr.Time
@@ -165,6 +198,10 @@ func (t *Tx) FromPb(tr *txpb.Tx) *Tx {
 	t.ChainID = tr.ChainId
 	t.ReferredTx = tr.ReferredTx
 	t.AmountLimit = tr.AmountLimit
+	t.Reserved = tr.Reserved
+	t.tip = tr.Tip
+	t.signHash = common.SignHash(tr.SignHash)
+	t.memo = tr.Memo
 	for _, a := range tr.Actions {
 		ac := &Action{}
 		t.Actions = append(t.Actions, ac.FromPb(a))
@@ -192,6 +229,10 @@ func (t *Tx) Decode(b []byte) error {
 	if err != nil {
 		return err
 	}
+	err = tr.CheckUnknownFields()
+	if err != nil {
+		return err
+	}
 	t.FromPb(tr)
 	return nil
 }
@@ -250,28 +291,16 @@ func (t *Tx) DeferTx() *Tx {
 		Signers:      t.Signers,
 		ChainID:      t.ChainID,
 		Reserved:     t.Reserved,
+		tip:          t.tip,
+		signHash:     t.signHash,
+		memo:         t.memo,
 	}
 	return deferTx
 }
 
 // VerifySelf verify tx's signature and some base fields.
 func (t *Tx) VerifySelf() error { // nolint
-	if t.ChainID != ChainID {
-		return fmt.Errorf("invalid chain_id, should be %d, yours:%d", ChainID, t.ChainID)
-	}
-	if !(t.Time > 0 && t.Expiration > t.Time) {
-		return errors.New("invalid time and expiration")
-	}
-	if t.Delay < 0 || t.Delay > MaxDelay {
-		return errors.New("invalid delay time")
-	}
-	if t.Delay > 0 && t.IsDefer() {
-		return errors.New("invalid tx. including both delay and referredtx field")
-	}
-	if err := t.CheckSize(); err != nil {
-		return err
-	}
-	if err := t.CheckGas(); err != nil {
+	if err := t.verifyFields(); err != nil {
 		return err
 	}
 
@@ -279,6 +308,13 @@ func (t *Tx) VerifySelf() error { // nolint
 	if t.IsDefer() {
 		return nil
 	}
+	for _, signs := range [][]*crypto.Signature{t.Signs, t.PublishSigns} {
+		for _, sign := range signs {
+			if err := crypto.ValidateSignature(sign); err != nil {
+				return err
+			}
+		}
+	}
 	baseHash := t.baseHash()
 	//signerSet := make(map[string]bool)
 	for _, sign := range t.Signs {
@@ -305,6 +341,76 @@ func (t *Tx) VerifySelf() error { // nolint
 	return nil
 }
 
+func (t *Tx) verifyFields() error {
+	if t.ChainID != ChainID {
+		return fmt.Errorf("invalid chain_id, should be %d, yours:%d", ChainID, t.ChainID)
+	}
+	if !t.SignHash().Valid() {
+		return common.ErrUnknownSignHash
+	}
+	if !(t.Time > 0 && t.Expiration > t.Time) {
+		return errors.New("invalid time and expiration")
+	}
+	if t.Delay < 0 || t.Delay > MaxDelay {
+		return errors.New("invalid delay time")
+	}
+	if t.Delay > 0 && t.IsDefer() {
+		return errors.New("invalid tx. including both delay and referredtx field")
+	}
+	if err := t.CheckSize(); err != nil {
+		return err
+	}
+	if err := t.CheckGas(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// VerifyTxsConcurrently verifies the txs with VerifySelf by a pool of workers, workers <= 0 means
+// the number of cpus. It returns the index and error of the lowest failed tx, or -1 if all pass.
+func VerifyTxsConcurrently(txs []*Tx, workers int) (int, error) {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > len(txs) {
+		workers = len(txs)
+	}
+	errs := make([]error, len(txs))
+	// The txs are taken in order, so the ones after a failed tx can be skipped
+	// without missing a failure of a lower index.
+	next := int64(-1)
+	failed := int64(len(txs))
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				i := atomic.AddInt64(&next, 1)
+				if i >= int64(len(txs)) || i > atomic.LoadInt64(&failed) {
+					return
+				}
+				if errs[i] = txs[i].VerifySelf(); errs[i] != nil {
+					for {
+						f := atomic.LoadInt64(&failed)
+						if i >= f || atomic.CompareAndSwapInt64(&failed, f, i) {
+							break
+						}
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return i, err
+		}
+	}
+	return -1, nil
+}
+
 // VerifySigner verify signer's signature
 func (t *Tx) VerifySigner(sig *crypto.Signature) bool {
 	return sig.Verify(t.baseHash())
@@ -342,6 +448,9 @@ func (t *Tx) CheckGas() error {
 	if t.GasRatio < minGasRatio || t.GasRatio > maxGasRatio {
 		return fmt.Errorf("gas ratio illegal, should in [%v, %v]", minGasRatio/ratio, maxGasRatio/ratio)
 	}
+	if tip := t.Tip(); tip < 0 || tip > maxGasRatio-t.GasRatio {
+		return fmt.Errorf("gas tip illegal, should in [0, %v]", (maxGasRatio-t.GasRatio)/int64(ratio))
+	}
 	if t.GasLimit < minGasLimit || t.GasLimit > maxGasLimit {
 		return fmt.Errorf("gas limit illegal, should in [%v, %v]", minGasLimit/ratio, maxGasLimit/ratio)
 	}
@@ -358,6 +467,12 @@ func (t *Tx) ToBytes(l ToBytesLevel) []byte {
 	se.WriteInt64(t.Delay)
 	se.WriteInt32(int32(t.ChainID))
 	se.WriteBytes(t.Reserved)
+	// The tip, the sign hash and the memo are left out when unset, so the txs without them hash as before.
+	if t.tip != 0 || t.signHash != common.SignHashSha3 || t.memo != "" {
+		se.WriteInt64(t.tip)
+		se.WriteByte(byte(t.signHash))
+		se.WriteString(t.memo)
+	}
 	se.WriteStringSlice(t.Signers)
 
 	actionBytes := make([][]byte, 0, len(t.Actions))
@@ -104,6 +104,8 @@ type TxReceipt struct { //nolint:golint
 	Status   *Status
 	Returns  []string
 	Receipts []*Receipt
+	// ContractGas is the gas of the actions tallied by contract, in the unit of GasUsage.
+	ContractGas map[string]int64
 }
 
 // NewTxReceipt generate tx receipt for a tx hash
@@ -140,6 +142,7 @@ func (r *TxReceipt) ToPb() *txpb.TxReceipt {
 	for _, re := range r.Receipts {
 		tr.Receipts = append(tr.Receipts, re.ToPb())
 	}
+	tr.ContractGas = r.ContractGas
 	return tr
 }
 
@@ -166,6 +169,7 @@ func (r *TxReceipt) FromPb(tr *txpb.TxReceipt) *TxReceipt {
 		rc := &Receipt{}
 		r.Receipts = append(r.Receipts, rc.FromPb(re))
 	}
+	r.ContractGas = tr.ContractGas
 	return r
 }
 
@@ -200,6 +204,12 @@ func (r *TxReceipt) ToBytes() []byte {
 	}
 	se.WriteBytesSlice(receiptBytes)
 
+	// the contract gas is only tallied from the fork.contractgasreceipt height, so
+	// the receipts before it keep their hashes
+	if len(r.ContractGas) > 0 {
+		se.WriteMapStringToI64(r.ContractGas)
+	}
+
 	return se.Bytes()
 }
 
@@ -216,6 +226,14 @@ func (r *TxReceipt) String() string {
 	return tr.String()
 }
 
+// AddContractGas adds the gas spent by an action of the contract.
+func (r *TxReceipt) AddContractGas(contract string, gas int64) {
+	if r.ContractGas == nil {
+		r.ContractGas = make(map[string]int64)
+	}
+	r.ContractGas[contract] += gas
+}
+
 // ParseCancelDelaytx returns the delaytxs' hashes that are canceled.
 func (r *TxReceipt) ParseCancelDelaytx() [][]byte {
 	if r.Status.Code != Success {
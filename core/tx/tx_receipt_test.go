@@ -5,6 +5,7 @@ import (
 
 	"bytes"
 
+	txpb "github.com/iost-official/go-iost/core/tx/pb"
 	. "github.com/smartystreets/goconvey/convey"
 )
 
@@ -66,3 +67,31 @@ func TestTxReceipt(t *testing.T) {
 
 	})
 }
+
+func TestStatusCodeUnknown(t *testing.T) {
+	if StatusCode(txpb.StatusCodeUnknown) != ErrorUnknown {
+		t.Fatalf("txpb.StatusCodeUnknown %v should be ErrorUnknown %v", txpb.StatusCodeUnknown, ErrorUnknown)
+	}
+}
+
+func TestGasByContract(t *testing.T) {
+	Convey("Test of GasByContract", t, func() {
+		r := NewTxReceipt([]byte("hash"))
+		hash := r.Hash()
+		So(r.ToPb().GasByContract(), ShouldBeEmpty)
+
+		r.AddContractGas("token.iost", 100)
+		r.AddContractGas("system.iost", 30)
+		r.AddContractGas("token.iost", 20)
+		So(r.Hash(), ShouldNotResemble, hash)
+
+		var d TxReceipt
+		So(d.Decode(r.Encode()), ShouldBeNil)
+		So(d.Hash(), ShouldResemble, r.Hash())
+		raw := d.ToPb()
+		So(raw.GasByContract(), ShouldResemble, map[string]int64{"token.iost": 120, "system.iost": 30})
+
+		raw.GasByContract()["token.iost"] = 0
+		So(raw.GasByContract()["token.iost"], ShouldEqual, 120)
+	})
+}
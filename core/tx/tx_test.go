@@ -3,6 +3,8 @@ package tx
 import (
 	"bytes"
 	"fmt"
+	"math"
+	"strings"
 	"testing"
 	"time"
 
@@ -97,6 +99,7 @@ func TestTx(t *testing.T) {
 
 			hash = tx.Hash()
 			encode = tx.Encode()
+			So(tx.EncodedLen(), ShouldEqual, len(encode))
 			err = tx1.Decode(encode)
 			So(err, ShouldEqual, nil)
 			hash1 = tx1.Hash()
@@ -131,6 +134,99 @@ func TestTx(t *testing.T) {
 			}
 		})
 
+		Convey("gas tip", func() {
+			tx := NewTx(actions, []string{a1.ReadablePubkey()}, 1000000, 200, 11, 0, 0)
+			So(tx.Tip(), ShouldEqual, 0)
+			So(tx.EffectiveGasRatio(), ShouldEqual, 200)
+			hash := tx.Hash()
+
+			tx.SetTip(50)
+			So(tx.EffectiveGasRatio(), ShouldEqual, 250)
+			So(bytes.Equal(hash, tx.Hash()), ShouldBeFalse)
+			So(tx.CheckGas(), ShouldBeNil)
+
+			tx1 := &Tx{}
+			So(tx1.Decode(tx.Encode()), ShouldBeNil)
+			So(tx1.Tip(), ShouldEqual, 50)
+			So(tx1.ToPb().Tip, ShouldEqual, 50)
+			So(tx1.Reserved, ShouldBeNil)
+
+			tx.SetTip(0)
+			So(bytes.Equal(hash, tx.Hash()), ShouldBeTrue)
+
+			tx.SetTip(-1)
+			So(tx.CheckGas(), ShouldNotBeNil)
+			tx.SetTip(maxGasRatio)
+			So(tx.CheckGas(), ShouldNotBeNil)
+			tx.SetTip(math.MaxInt64)
+			So(tx.CheckGas(), ShouldNotBeNil)
+		})
+
+		Convey("memo", func() {
+			tx := NewTx(actions, []string{a1.ReadablePubkey()}, 100000000, 100, time.Now().Add(time.Minute).UnixNano(), 0, 0)
+			So(tx.Memo(), ShouldEqual, "")
+			So(CheckMemo(tx), ShouldBeNil)
+
+			tx.SetTip(7)
+			tx.SetMemo("deposit 备注 #42")
+			So(tx.Memo(), ShouldEqual, "deposit 备注 #42")
+			So(tx.Tip(), ShouldEqual, 7)
+			So(tx.SignHash(), ShouldEqual, common.SignHashSha3)
+			So(CheckMemo(tx), ShouldBeNil)
+			tx, err := SignTx(tx, a3.ReadablePubkey(), []*account.KeyPair{a3})
+			So(err, ShouldBeNil)
+			So(tx.VerifySelf(), ShouldBeNil)
+
+			tx1 := &Tx{}
+			So(tx1.Decode(tx.Encode()), ShouldBeNil)
+			So(tx1.Memo(), ShouldEqual, "deposit 备注 #42")
+			So(tx1.ToPb().Memo, ShouldEqual, "deposit 备注 #42")
+			So(tx1.VerifySelf(), ShouldBeNil)
+
+			tx1.SetMemo("changed")
+			So(tx1.VerifySelf(), ShouldNotBeNil)
+
+			tx.SetMemo(strings.Repeat("m", MaxMemoLen))
+			So(CheckMemo(tx), ShouldBeNil)
+			tx.SetMemo(strings.Repeat("m", MaxMemoLen+1))
+			So(CheckMemo(tx).Error(), ShouldContainSubstring, "memo too long")
+
+			tx.SetMemo("bad \xff\xfe")
+			So(CheckMemo(tx).Error(), ShouldEqual, "memo is not valid UTF-8")
+		})
+
+		Convey("sign hash", func() {
+			for _, h := range []common.SignHash{common.SignHashSha3, common.SignHashBlake2b} {
+				tx := NewTx(actions, []string{a1.ReadablePubkey()}, 100000000, 100, time.Now().Add(time.Minute).UnixNano(), 0, 0)
+				tx.SetTip(7)
+				tx.SetSignHash(h)
+				So(tx.Tip(), ShouldEqual, 7)
+				So(tx.SignHash(), ShouldEqual, h)
+				So(tx.baseHash(), ShouldResemble, h.Sum(tx.ToBytes(Base)))
+
+				sig, err := SignTxContent(tx, a1.ReadablePubkey(), a1)
+				So(err, ShouldBeNil)
+				tx.Signs = append(tx.Signs, sig)
+				tx, err = SignTx(tx, a3.ReadablePubkey(), []*account.KeyPair{a3})
+				So(err, ShouldBeNil)
+				So(tx.VerifySelf(), ShouldBeNil)
+
+				tx1 := &Tx{}
+				So(tx1.Decode(tx.Encode()), ShouldBeNil)
+				So(tx1.SignHash(), ShouldEqual, h)
+				So(tx1.VerifySelf(), ShouldBeNil)
+			}
+
+			tx := NewTx(actions, nil, 100000000, 100, time.Now().Add(time.Minute).UnixNano(), 0, 0)
+			So(tx.SignHash(), ShouldEqual, common.SignHashSha3)
+			So(tx.baseHash(), ShouldResemble, common.Sha3(tx.ToBytes(Base)))
+			tx, _ = SignTx(tx, a3.ReadablePubkey(), []*account.KeyPair{a3})
+			tx.SetSignHash(common.SignHashBlake2b)
+			So(tx.VerifySelf().Error(), ShouldEqual, "publisher error")
+			tx.SetSignHash(common.SignHash(9))
+			So(tx.VerifySelf(), ShouldEqual, common.ErrUnknownSignHash)
+		})
+
 		Convey("sign and verify", func() {
 			tx := NewTx(actions, []string{a1.ReadablePubkey(), a2.ReadablePubkey()}, 100000000, 100, time.Now().Add(time.Minute).UnixNano(), 0, 0)
 			sig1, err := SignTxContent(tx, a1.ReadablePubkey(), a1)
@@ -155,14 +251,19 @@ func TestTx(t *testing.T) {
 				Pubkey:    []byte("world"),
 			}}
 			err = tx.VerifySelf()
+			So(err.Error(), ShouldContainSubstring, "signature length should be 64")
+
+			tx.PublishSigns[0].Sig = make([]byte, 64)
+			tx.PublishSigns[0].Pubkey = a3.Pubkey
+			err = tx.VerifySelf()
 			So(err.Error(), ShouldEqual, "publisher error")
 
 			fmt.Println(tx.String())
 
 			tx.Signs[0] = &crypto.Signature{
 				Algorithm: crypto.Secp256k1,
-				Sig:       []byte("hello"),
-				Pubkey:    []byte("world"),
+				Sig:       make([]byte, 64),
+				Pubkey:    a1.Pubkey,
 			}
 			err = tx.VerifySelf()
 			So(err.Error(), ShouldEqual, "signer error")
@@ -189,6 +290,69 @@ func TestTx(t *testing.T) {
 	})
 }
 
+func TestTxChainID(t *testing.T) {
+	Convey("Test of Tx chain id replay protection", t, func() {
+		oldChainID := ChainID
+		defer func() { ChainID = oldChainID }()
+
+		a1, _ := account.NewKeyPair(nil, crypto.Secp256k1)
+		actions := []*Action{NewAction("contract1", "actionname1", "[]")}
+		newSignedTx := func(chainID uint32) *Tx {
+			trx := NewTx(actions, []string{a1.ReadablePubkey()}, 1000000, 100, time.Now().UnixNano()+MaxExpiration, 0, chainID)
+			sig, err := SignTxContent(trx, a1.ReadablePubkey(), a1)
+			So(err, ShouldBeNil)
+			trx, err = SignTx(trx, a1.ReadablePubkey(), []*account.KeyPair{a1}, sig)
+			So(err, ShouldBeNil)
+			return trx
+		}
+
+		Convey("same chain", func() {
+			ChainID = 1024
+			trx := newSignedTx(1024)
+			So(trx.VerifySelf(), ShouldBeNil)
+		})
+
+		Convey("replay on another chain", func() {
+			ChainID = 1025
+			trx := newSignedTx(1024)
+			So(trx.VerifySelf(), ShouldNotBeNil)
+
+			trx.ChainID = 1025
+			trx.hash = nil
+			So(trx.VerifySelf(), ShouldNotBeNil)
+		})
+	})
+}
+
+func TestResignWith(t *testing.T) {
+	Convey("Test of ResignWith", t, func() {
+		a1, _ := account.NewKeyPair(nil, crypto.Secp256k1)
+		actions := []*Action{NewAction("contract1", "actionname1", "[]")}
+		trx := NewTx(actions, []string{a1.ReadablePubkey()}, 1000000, 100, time.Now().UnixNano()+MaxExpiration, 0, ChainID)
+		sig, err := SignTxContent(trx, a1.ReadablePubkey(), a1)
+		So(err, ShouldBeNil)
+		trx, err = SignTx(trx, a1.ReadablePubkey(), []*account.KeyPair{a1}, sig)
+		So(err, ShouldBeNil)
+		So(trx.VerifySelf(), ShouldBeNil)
+		hash := trx.Hash()
+
+		trx.GasRatio = 200
+		So(trx.VerifySelf(), ShouldNotBeNil)
+		So(trx.ResignWith(a1), ShouldBeNil)
+		So(trx.VerifySelf(), ShouldBeNil)
+		So(trx.Publisher, ShouldEqual, a1.ReadablePubkey())
+		So(trx.Signs, ShouldBeEmpty)
+		So(trx.Actions, ShouldResemble, actions)
+		So(trx.Hash(), ShouldNotResemble, hash)
+
+		trx.Actions = nil
+		So(trx.ResignWith(a1), ShouldNotBeNil)
+
+		unpublished := NewTx(actions, nil, 1000000, 100, time.Now().UnixNano()+MaxExpiration, 0, ChainID)
+		So(unpublished.ResignWith(a1), ShouldNotBeNil)
+	})
+}
+
 func TestTx_Platform(t *testing.T) {
 	//t.Skip()
 	//var sep = `\` + "`" + "^" + "/" + "<"
@@ -247,6 +411,57 @@ func TestTx_Platform(t *testing.T) {
 	fmt.Println("}")
 }
 
+func signedTxs(n int) []*Tx {
+	actions := []*Action{NewAction("contract1", "actionname1", "[]")}
+	txs := make([]*Tx, 0, n)
+	for i := 0; i < n; i++ {
+		algo := crypto.Ed25519
+		if i%10 == 0 {
+			algo = crypto.Secp256k1
+		}
+		a, _ := account.NewKeyPair(nil, algo)
+		trx := NewTx(actions, []string{a.ReadablePubkey()}, 1000000, 100, time.Now().UnixNano()+MaxExpiration, 0, ChainID)
+		trx, _ = SignTx(trx, a.ReadablePubkey(), []*account.KeyPair{a})
+		txs = append(txs, trx)
+	}
+	return txs
+}
+
+func TestVerifyTxsConcurrently(t *testing.T) {
+	Convey("Test of VerifyTxsConcurrently", t, func() {
+		txs := signedTxs(100)
+		i, err := VerifyTxsConcurrently(txs, 4)
+		So(err, ShouldBeNil)
+		So(i, ShouldEqual, -1)
+
+		txs[70].PublishSigns[0].Sig[0] ^= 0xff
+		i, err = VerifyTxsConcurrently(txs, 4)
+		So(err, ShouldNotBeNil)
+		So(i, ShouldEqual, 70)
+
+		txs[30].PublishSigns[0].Sig[0] ^= 0xff
+		for _, workers := range []int{0, 1, 8, 200} {
+			i, err = VerifyTxsConcurrently(txs, workers)
+			So(err.Error(), ShouldEqual, "publisher error")
+			So(i, ShouldEqual, 30)
+		}
+
+		i, err = VerifyTxsConcurrently(nil, 4)
+		So(err, ShouldBeNil)
+		So(i, ShouldEqual, -1)
+	})
+}
+
+func BenchmarkVerifyTxsConcurrently(b *testing.B) {
+	txs := signedTxs(500)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := VerifyTxsConcurrently(txs, 0); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
 func BenchmarkHash(b *testing.B) {
 	tx := &Tx{
 		Time:       1234567890,
@@ -288,3 +503,22 @@ func BenchmarkHash(b *testing.B) {
 		tx.Hash()
 	}
 }
+
+func TestDecodeOverflowVarint(t *testing.T) {
+	Convey("Test of decoding malicious varints", t, func() {
+		tooLong := append([]byte{0x08}, bytes.Repeat([]byte{0xff}, 10)...)
+		tooLong = append(tooLong, 0x01)
+		highBits := append([]byte{0x08}, bytes.Repeat([]byte{0xff}, 9)...)
+		highBits = append(highBits, 0x02)
+		for _, b := range [][]byte{tooLong, highBits} {
+			var trx Tx
+			So(trx.Decode(b), ShouldNotBeNil)
+		}
+
+		maxVarint := append([]byte{0x08}, bytes.Repeat([]byte{0xff}, 9)...)
+		maxVarint = append(maxVarint, 0x01)
+		var trx Tx
+		So(trx.Decode(maxVarint), ShouldBeNil)
+		So(trx.Time, ShouldEqual, -1)
+	})
+}
@@ -0,0 +1,167 @@
+package tx
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/bitly/go-simplejson"
+	"github.com/iost-official/go-iost/common"
+)
+
+// values
+var (
+	// MaxActionsPerTx is the max number of actions in a tx accepted by ValidateTx.
+	MaxActionsPerTx = 100
+	// MaxTxTimeGap is how far in the future the time of a tx accepted by ValidateTx may be.
+	MaxTxTimeGap = 5 * time.Second.Nanoseconds()
+	// MinExpirationWindow and MaxExpirationWindow bound the expiration of a tx after its time.
+	MinExpirationWindow = time.Second.Nanoseconds()
+	MaxExpirationWindow = time.Hour.Nanoseconds()
+)
+
+// ValidationReason tells which check of ValidateTx fails.
+type ValidationReason int
+
+// ValidationReason list
+const (
+	ReasonExpired ValidationReason = iota + 1
+	ReasonInvalid
+	ReasonBadAction
+)
+
+// ValidationError is the error returned by ValidateTx.
+type ValidationError struct {
+	Reason ValidationReason
+	Err    error
+}
+
+func (e *ValidationError) Error() string {
+	switch e.Reason {
+	case ReasonExpired:
+		return fmt.Sprintf("TimeError %v", e.Err)
+	case ReasonInvalid:
+		return fmt.Sprintf("VerifyError %v", e.Err)
+	default:
+		return e.Err.Error()
+	}
+}
+
+// ValidateTx checks the time, the signatures and the actions of the tx in order,
+// and returns the first failure as *ValidationError.
+func ValidateTx(t *Tx, now int64) error {
+	if !t.IsCreatedBefore(now+MaxTxTimeGap) || t.IsExpired(now) {
+		return &ValidationError{ReasonExpired, fmt.Errorf("tx time %v, expiration %v, now %v", t.Time, t.Expiration, now)}
+	}
+	if err := CheckExpirationWindow(t); err != nil {
+		return &ValidationError{ReasonExpired, err}
+	}
+	if err := t.VerifySelf(); err != nil {
+		return &ValidationError{ReasonInvalid, err}
+	}
+	if err := CheckActions(t); err != nil {
+		return &ValidationError{ReasonBadAction, err}
+	}
+	return nil
+}
+
+// CheckExpirationWindow checks that the expiration of the tx is within
+// [MinExpirationWindow, MaxExpirationWindow] after its time.
+func CheckExpirationWindow(t *Tx) error {
+	window := t.Expiration - t.Time
+	if window < MinExpirationWindow || window > MaxExpirationWindow {
+		return fmt.Errorf("expiration should be %v to %v after tx time, got %v",
+			time.Duration(MinExpirationWindow), time.Duration(MaxExpirationWindow), time.Duration(window))
+	}
+	return nil
+}
+
+// CheckActions checks the number of actions and the arguments of the token transfers in the tx.
+func CheckActions(t *Tx) error {
+	if len(t.Actions) > MaxActionsPerTx {
+		return fmt.Errorf("too many actions: %v, the limit is %v", len(t.Actions), MaxActionsPerTx)
+	}
+	for _, a := range t.Actions {
+		err := checkBadAction(a)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TokenDecimals is the decimal of the tokens known without reading the state.
+var TokenDecimals = map[string]int{
+	"iost": 8,
+}
+
+// ParseTokenAmount returns the number of the smallest unit of the token in amount.
+func ParseTokenAmount(amount, token string) (int64, error) {
+	decimal, ok := TokenDecimals[token]
+	if !ok {
+		return 0, fmt.Errorf("unknown decimal of token %v", token)
+	}
+	v, err := common.ParseAmount(amount, decimal)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount: %v, %v", err, amount)
+	}
+	return v, nil
+}
+
+// CheckAmount checks that amount is a valid amount of the token in a transfer.
+func CheckAmount(amount string, token string) error {
+	matched, err := regexp.MatchString("^([0-9]+[.])?[0-9]+$", amount)
+	if err != nil || !matched {
+		return fmt.Errorf("invalid amount: %v", amount)
+	}
+	f1, err := common.NewFixed(amount, -1)
+	if err != nil {
+		return fmt.Errorf("invalid amount: %v, %v", err, amount)
+	}
+	f2, err := strconv.ParseFloat(amount, 64)
+	if err != nil {
+		return fmt.Errorf("invalid amount: %v, %v", err, amount)
+	}
+	if math.Abs(f1.ToFloat()-f2) > 1e-7 {
+		return fmt.Errorf("invalid amount: %v, %v", err, amount)
+	}
+	if _, ok := TokenDecimals[token]; ok {
+		if _, err := ParseTokenAmount(amount, token); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkBadAction(action *Action) error {
+	if action.Contract == "token.iost" && action.ActionName == "transfer" {
+		data := action.Data
+		js, err := simplejson.NewJson([]byte(data))
+		if err != nil {
+			return fmt.Errorf("invalid json array: %v, %v", err, data)
+		}
+		arr, err := js.Array()
+		if err != nil {
+			return fmt.Errorf("invalid json array: %v, %v", err, data)
+		}
+		if len(arr) != 5 {
+			return fmt.Errorf("wrong args num: %v", data)
+		}
+		token, err := js.GetIndex(0).String()
+		if err != nil {
+			return fmt.Errorf("invalid token: %v, %v", err, data)
+		}
+		amount, err := js.GetIndex(3).String()
+		if err != nil {
+			return fmt.Errorf("invalid amount: %v, %v", err, data)
+		}
+		err = CheckAmount(amount, token)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+	return nil
+}
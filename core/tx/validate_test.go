@@ -0,0 +1,98 @@
+package tx
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iost-official/go-iost/account"
+	"github.com/iost-official/go-iost/crypto"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestValidateTx(t *testing.T) {
+	Convey("Test of ValidateTx", t, func() {
+		a1, _ := account.NewKeyPair(nil, crypto.Ed25519)
+		now := time.Now().UnixNano()
+		newSignedTx := func(actions []*Action, ctime int64) *Tx {
+			trx := NewTx(actions, []string{a1.ReadablePubkey()}, 1000000, 100, ctime+MaxExpiration, 0, ChainID)
+			trx.Time = ctime
+			trx, err := SignTx(trx, a1.ReadablePubkey(), []*account.KeyPair{a1})
+			So(err, ShouldBeNil)
+			return trx
+		}
+		reasonOf := func(err error) ValidationReason {
+			verr, ok := err.(*ValidationError)
+			So(ok, ShouldBeTrue)
+			return verr.Reason
+		}
+		actions := []*Action{NewAction("token.iost", "transfer", `["iost", "a", "b", "1.5", ""]`)}
+
+		Convey("valid", func() {
+			So(ValidateTx(newSignedTx(actions, now), now), ShouldBeNil)
+		})
+
+		Convey("expired", func() {
+			trx := newSignedTx(actions, now-2*MaxExpiration)
+			So(reasonOf(ValidateTx(trx, now)), ShouldEqual, ReasonExpired)
+			trx = newSignedTx(actions, now+2*MaxTxTimeGap)
+			So(reasonOf(ValidateTx(trx, now)), ShouldEqual, ReasonExpired)
+		})
+
+		Convey("expiration window", func() {
+			for _, c := range []struct {
+				window int64
+				ok     bool
+			}{
+				{MinExpirationWindow - 1, false},
+				{MinExpirationWindow, true},
+				{MaxExpiration, true},
+				{MaxExpirationWindow, true},
+				{MaxExpirationWindow + 1, false},
+				{-MaxExpiration, false},
+			} {
+				trx := &Tx{Time: now, Expiration: now + c.window}
+				So(CheckExpirationWindow(trx) == nil, ShouldEqual, c.ok)
+			}
+			trx := newSignedTx(actions, now)
+			trx.Expiration = now + MaxExpirationWindow + 1
+			So(reasonOf(ValidateTx(trx, now)), ShouldEqual, ReasonExpired)
+		})
+
+		Convey("invalid signature", func() {
+			trx := newSignedTx(actions, now)
+			trx.PublishSigns[0].Sig[0] ^= 0xff
+			So(reasonOf(ValidateTx(trx, now)), ShouldEqual, ReasonInvalid)
+		})
+
+		Convey("bad action", func() {
+			bad := []*Action{NewAction("token.iost", "transfer", `["iost", "a", "b", "1.123456789", ""]`)}
+			So(reasonOf(ValidateTx(newSignedTx(bad, now), now)), ShouldEqual, ReasonBadAction)
+		})
+
+		Convey("token amount", func() {
+			v, err := ParseTokenAmount("1.23456789", "iost")
+			So(err, ShouldBeNil)
+			So(v, ShouldEqual, 123456789)
+			_, err = ParseTokenAmount("1.234567891", "iost")
+			So(err, ShouldNotBeNil)
+			_, err = ParseTokenAmount("1", "unknown")
+			So(err, ShouldNotBeNil)
+
+			So(CheckAmount("1.23456789", "iost"), ShouldBeNil)
+			So(CheckAmount("1.234567891", "iost"), ShouldNotBeNil)
+			So(CheckAmount("1.234567891", "unknown"), ShouldBeNil)
+		})
+
+		Convey("max actions", func() {
+			genActions := func(n int) []*Action {
+				actions := make([]*Action, n)
+				for i := range actions {
+					actions[i] = NewAction("contract1", "actionname1", "[]")
+				}
+				return actions
+			}
+			So(CheckActions(&Tx{Actions: genActions(MaxActionsPerTx)}), ShouldBeNil)
+			So(CheckActions(&Tx{Actions: genActions(MaxActionsPerTx + 1)}), ShouldNotBeNil)
+		})
+	})
+}
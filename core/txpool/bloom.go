@@ -0,0 +1,54 @@
+package txpool
+
+import (
+	"math"
+	"sync"
+
+	"github.com/willf/bloom"
+)
+
+var (
+	txBloomItemCount uint = 1000000
+	txBloomErrRate        = 0.001
+)
+
+// txBloom is a bloom filter of the hashes of the recent txs.
+// It is rebuilt from the live blocks when the old blocks are cleared,
+// so the hashes of the expired txs roll out of it.
+type txBloom struct {
+	mu     sync.RWMutex
+	filter *bloom.BloomFilter
+	n      int
+}
+
+func newTxBloom() *txBloom {
+	return &txBloom{
+		filter: bloom.NewWithEstimates(txBloomItemCount, txBloomErrRate),
+	}
+}
+
+// add adds a tx hash. The caller must hold mu.
+func (b *txBloom) add(hash []byte) {
+	b.filter.Add(hash)
+	b.n++
+}
+
+// reset empties the filter. The caller must hold mu.
+func (b *txBloom) reset() {
+	b.filter = bloom.NewWithEstimates(txBloomItemCount, txBloomErrRate)
+	b.n = 0
+}
+
+// mayContain returns false if the hash is surely not added.
+func (b *txBloom) mayContain(hash []byte) bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.filter.Test(hash)
+}
+
+func (b *txBloom) stats() (int, float64) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	m, k := float64(b.filter.Cap()), float64(b.filter.K())
+	return b.n, math.Pow(1-math.Exp(-k*float64(b.n)/m), k)
+}
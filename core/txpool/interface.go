@@ -4,6 +4,7 @@ import (
 	"github.com/iost-official/go-iost/core/block"
 	"github.com/iost-official/go-iost/core/blockcache"
 	"github.com/iost-official/go-iost/core/tx"
+	"github.com/iost-official/go-iost/crypto"
 )
 
 //go:generate mockgen -destination mock/mock_txpool.go -package txpool_mock github.com/iost-official/go-iost/core/txpool TxPool
@@ -16,10 +17,13 @@ type TxPool interface {
 	AddTx(tx *tx.Tx) error
 	DelTx(hash []byte) error
 	DelTxList(delList []*tx.Tx)
+	CancelTx(hash []byte, proof *crypto.Signature) error
 	ExistTxs(hash []byte, chainBlock *block.Block) FRet
+	TxStatus(hash []byte) (FRet, error)
 	GetFromPending(hash []byte) (*tx.Tx, error)
 	GetFromChain(hash []byte) (*tx.Tx, *tx.TxReceipt, error)
 	Lock()
 	Release()
 	PendingTx() (*SortedTxMap, *blockcache.BlockCacheNode)
+	SetMinGasPrice(p int64)
 }
@@ -10,6 +10,7 @@ import (
 	blockcache "github.com/iost-official/go-iost/core/blockcache"
 	tx "github.com/iost-official/go-iost/core/tx"
 	txpool "github.com/iost-official/go-iost/core/txpool"
+	crypto "github.com/iost-official/go-iost/crypto"
 	reflect "reflect"
 )
 
@@ -60,6 +61,18 @@ func (mr *MockTxPoolMockRecorder) AddTx(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTx", reflect.TypeOf((*MockTxPool)(nil).AddTx), arg0)
 }
 
+// CancelTx mocks base method
+func (m *MockTxPool) CancelTx(arg0 []byte, arg1 *crypto.Signature) error {
+	ret := m.ctrl.Call(m, "CancelTx", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelTx indicates an expected call of CancelTx
+func (mr *MockTxPoolMockRecorder) CancelTx(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelTx", reflect.TypeOf((*MockTxPool)(nil).CancelTx), arg0, arg1)
+}
+
 // DelTx mocks base method
 func (m *MockTxPool) DelTx(arg0 []byte) error {
 	ret := m.ctrl.Call(m, "DelTx", arg0)
@@ -154,6 +167,16 @@ func (mr *MockTxPoolMockRecorder) Release() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockTxPool)(nil).Release))
 }
 
+// SetMinGasPrice mocks base method
+func (m *MockTxPool) SetMinGasPrice(arg0 int64) {
+	m.ctrl.Call(m, "SetMinGasPrice", arg0)
+}
+
+// SetMinGasPrice indicates an expected call of SetMinGasPrice
+func (mr *MockTxPoolMockRecorder) SetMinGasPrice(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMinGasPrice", reflect.TypeOf((*MockTxPool)(nil).SetMinGasPrice), arg0)
+}
+
 // Start mocks base method
 func (m *MockTxPool) Start() error {
 	ret := m.ctrl.Call(m, "Start")
@@ -175,3 +198,16 @@ func (m *MockTxPool) Stop() {
 func (mr *MockTxPoolMockRecorder) Stop() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockTxPool)(nil).Stop))
 }
+
+// TxStatus mocks base method
+func (m *MockTxPool) TxStatus(arg0 []byte) (txpool.FRet, error) {
+	ret := m.ctrl.Call(m, "TxStatus", arg0)
+	ret0, _ := ret[0].(txpool.FRet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TxStatus indicates an expected call of TxStatus
+func (mr *MockTxPoolMockRecorder) TxStatus(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TxStatus", reflect.TypeOf((*MockTxPool)(nil).TxStatus), arg0)
+}
@@ -0,0 +1,92 @@
+package txpool
+
+import (
+	"errors"
+
+	"github.com/emirpasic/gods/utils"
+	"github.com/iost-official/go-iost/core/tx"
+)
+
+// OrderingPolicy decides the order in which the pending txs are packed into blocks.
+type OrderingPolicy int
+
+// OrderingPolicy list
+const (
+	// OrderByGasPrice packs the txs of higher effective gas ratio first, then the earlier created ones.
+	OrderByGasPrice OrderingPolicy = iota
+	// OrderFIFO packs the txs in the order they arrive in the pool.
+	OrderFIFO
+	// OrderByGasPriceThenFIFO packs the txs of higher effective gas ratio first, then the earlier arrived ones.
+	OrderByGasPriceThenFIFO
+)
+
+// ErrUnknownOrderingPolicy is returned when the ordering policy isn't supported.
+var ErrUnknownOrderingPolicy = errors.New("unknown tx ordering policy")
+
+// ParseOrderingPolicy returns the ordering policy of the name, "" means gasprice.
+func ParseOrderingPolicy(name string) (OrderingPolicy, error) {
+	switch name {
+	case "", "gasprice":
+		return OrderByGasPrice, nil
+	case "fifo":
+		return OrderFIFO, nil
+	case "gasprice_fifo":
+		return OrderByGasPriceThenFIFO, nil
+	default:
+		return OrderByGasPrice, ErrUnknownOrderingPolicy
+	}
+}
+
+// String returns the name of the ordering policy.
+func (p OrderingPolicy) String() string {
+	switch p {
+	case OrderByGasPrice:
+		return "gasprice"
+	case OrderFIFO:
+		return "fifo"
+	case OrderByGasPriceThenFIFO:
+		return "gasprice_fifo"
+	default:
+		return "unknown"
+	}
+}
+
+// sortedTx is a pending tx with its arrival sequence in the pool.
+type sortedTx struct {
+	tx  *tx.Tx
+	seq uint64
+}
+
+// comparator returns the comparator of the sorted txs, the tx packed first is the largest.
+func (p OrderingPolicy) comparator() utils.Comparator {
+	switch p {
+	case OrderFIFO:
+		return func(a, b interface{}) int {
+			return compareSeq(a.(*sortedTx), b.(*sortedTx))
+		}
+	case OrderByGasPriceThenFIFO:
+		return func(a, b interface{}) int {
+			sa, sb := a.(*sortedTx), b.(*sortedTx)
+			ra, rb := sa.tx.EffectiveGasRatio(), sb.tx.EffectiveGasRatio()
+			if ra != rb {
+				return int(ra - rb)
+			}
+			return compareSeq(sa, sb)
+		}
+	default:
+		return func(a, b interface{}) int {
+			return compareTx(a.(*sortedTx).tx, b.(*sortedTx).tx)
+		}
+	}
+}
+
+func compareSeq(a, b *sortedTx) int {
+	switch {
+	case a.seq < b.seq:
+		return 1
+	case a.seq > b.seq:
+		return -1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,89 @@
+package txpool
+
+import (
+	"github.com/iost-official/go-iost/common"
+	"github.com/iost-official/go-iost/core/blockcache"
+	"github.com/iost-official/go-iost/core/tx"
+	"github.com/iost-official/go-iost/ilog"
+)
+
+// reorgChSize is the size of a reorg subscriber's read channel.
+var reorgChSize = 16
+
+// ReorgEvent describes a switch of the head to another fork.
+type ReorgEvent struct {
+	OldHead     []byte
+	NewHead     []byte
+	ForkHash    []byte
+	RevertedTxs []*tx.Tx
+	AppliedTxs  []*tx.Tx
+}
+
+// SubscribeReorg returns a channel of the reorgs resolved by the pool.
+// The channel is closed if the subscriber falls reorgChSize events behind, so no event is missed silently.
+func (pool *TxPImpl) SubscribeReorg() <-chan ReorgEvent {
+	c := make(chan ReorgEvent, reorgChSize)
+	pool.reorgMu.Lock()
+	pool.reorgSubs = append(pool.reorgSubs, c)
+	pool.reorgMu.Unlock()
+	return c
+}
+
+// UnsubscribeReorg removes and closes a channel returned by SubscribeReorg.
+func (pool *TxPImpl) UnsubscribeReorg(c <-chan ReorgEvent) {
+	pool.reorgMu.Lock()
+	defer pool.reorgMu.Unlock()
+	for i, sub := range pool.reorgSubs {
+		if sub == c {
+			close(sub)
+			pool.reorgSubs = append(pool.reorgSubs[:i], pool.reorgSubs[i+1:]...)
+			return
+		}
+	}
+}
+
+// publishReorg sends the fork chain to the subscribers, if the old head was left for another fork.
+// A subscriber which doesn't keep up is closed instead of blocking the pool.
+func (pool *TxPImpl) publishReorg() {
+	pool.reorgMu.Lock()
+	defer pool.reorgMu.Unlock()
+	if len(pool.reorgSubs) == 0 {
+		return
+	}
+	newHead := pool.forkChain.GetNewHead()
+	oldHead := pool.forkChain.GetOldHead()
+	forkBCN := pool.forkChain.GetForkBCN()
+	if oldHead == nil || oldHead == forkBCN {
+		return
+	}
+	ev := ReorgEvent{
+		OldHead:     bcnHash(oldHead),
+		NewHead:     bcnHash(newHead),
+		ForkHash:    bcnHash(forkBCN),
+		RevertedTxs: branchTxs(oldHead, forkBCN),
+		AppliedTxs:  branchTxs(newHead, forkBCN),
+	}
+	subs := pool.reorgSubs[:0]
+	for _, c := range pool.reorgSubs {
+		select {
+		case c <- ev:
+			subs = append(subs, c)
+		default:
+			ilog.Warnf("reorg subscriber is lagging, closing it. new head=%v", common.Base58Encode(ev.NewHead))
+			close(c)
+		}
+	}
+	pool.reorgSubs = subs
+}
+
+// branchTxs returns the txs of the blocks from head down to fork, excluding fork.
+func branchTxs(head, fork *blockcache.BlockCacheNode) []*tx.Tx {
+	txs := make([]*tx.Tx, 0)
+	for head != nil && head != fork {
+		if head.Block != nil {
+			txs = append(txs, head.Block.Txs...)
+		}
+		head = head.GetParent()
+	}
+	return txs
+}
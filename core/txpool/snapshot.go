@@ -0,0 +1,56 @@
+package txpool
+
+import (
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/iost-official/go-iost/common"
+	"github.com/iost-official/go-iost/core/tx"
+	"github.com/iost-official/go-iost/core/tx/pb"
+	"github.com/iost-official/go-iost/ilog"
+)
+
+// pendingSnapshot is the protobuf message of the exported pending txs.
+type pendingSnapshot struct {
+	Txs []*txpb.Tx `protobuf:"bytes,1,rep,name=txs,proto3" json:"txs,omitempty"`
+}
+
+func (m *pendingSnapshot) Reset()         { *m = pendingSnapshot{} }
+func (m *pendingSnapshot) String() string { return proto.CompactTextString(m) }
+func (*pendingSnapshot) ProtoMessage()    {}
+
+// ExportPending serializes all pending txs.
+func (pool *TxPImpl) ExportPending() ([]byte, error) {
+	snapshot := &pendingSnapshot{}
+	pool.IteratePending(func(t *tx.Tx) bool {
+		snapshot.Txs = append(snapshot.Txs, t.ToPb())
+		return true
+	})
+	return proto.Marshal(snapshot)
+}
+
+// ImportPending adds the txs exported by ExportPending to pending through the same checks as AddTx,
+// so txs which are expired, invalid, already in pending or chain, or cheaper than a full pool are skipped.
+// The imported txs are not broadcast. It returns the number of imported txs.
+func (pool *TxPImpl) ImportPending(data []byte) (int, error) {
+	snapshot := &pendingSnapshot{}
+	if err := proto.Unmarshal(data, snapshot); err != nil {
+		return 0, err
+	}
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	count := 0
+	for _, tpb := range snapshot.Txs {
+		t := (&tx.Tx{}).FromPb(tpb)
+		err := tx.ValidateTx(t, time.Now().UnixNano())
+		if err == nil {
+			err = pool.admitTx(t)
+		}
+		if err != nil {
+			ilog.Debugf("Skip importing tx %v: %v", common.Base58Encode(t.Hash()), err)
+			continue
+		}
+		count++
+	}
+	return count, nil
+}
@@ -1,10 +1,14 @@
 package txpool
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"math/rand"
 	"runtime"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/iost-official/go-iost/common"
@@ -12,6 +16,7 @@ import (
 	"github.com/iost-official/go-iost/core/blockcache"
 	"github.com/iost-official/go-iost/core/global"
 	"github.com/iost-official/go-iost/core/tx"
+	"github.com/iost-official/go-iost/crypto"
 	"github.com/iost-official/go-iost/ilog"
 	"github.com/iost-official/go-iost/p2p"
 )
@@ -28,9 +33,26 @@ type TxPImpl struct {
 	pendingTx        *SortedTxMap
 	mu               sync.RWMutex
 	chP2PTx          chan p2p.IncomingMessage
+	txIntake         chan struct{} // a slot per AddTx in progress, bounded by intakeSize
+	nodeIntake       chan struct{} // a slot per AddLinkedNode in progress, bounded by intakeSize
 	deferServer      *DeferServer
 	quitGenerateMode chan struct{}
 	quitCh           chan struct{}
+	maxReorgDepth    int64
+	maxBlockListSize int
+	maxPendingTxs    int
+	minConfirmations int64
+	minGasPrice      int64
+	clearInterval    time.Duration
+	contractFilter   atomic.Value // contractFilter
+	reorgMu          sync.RWMutex
+	reorgSubs        []chan ReorgEvent
+	txBloom          *txBloom
+}
+
+// contractFilter wraps the filter func so that a nil filter can be stored in atomic.Value.
+type contractFilter struct {
+	fn func(action *tx.Action) bool
 }
 
 // NewTxPoolImpl returns a default TxPImpl instance.
@@ -42,10 +64,42 @@ func NewTxPoolImpl(global global.BaseVariable, blockCache blockcache.BlockCache,
 		forkChain:        new(forkChain),
 		blockList:        new(sync.Map),
 		pendingTx:        NewSortedTxMap(),
-		chP2PTx:          p2pService.Register("txpool message", p2p.PublishTx),
+		txIntake:         make(chan struct{}, intakeSize),
+		nodeIntake:       make(chan struct{}, intakeSize),
 		quitGenerateMode: make(chan struct{}),
 		quitCh:           make(chan struct{}),
+		maxReorgDepth:    defaultMaxReorgDepth,
+		maxBlockListSize: defaultMaxBlockListSize,
+		maxPendingTxs:    maxCacheTxs,
+		clearInterval:    clearInterval,
+		txBloom:          newTxBloom(),
+	}
+	if conf := global.Config(); conf != nil && conf.TxPool != nil {
+		if conf.TxPool.MaxReorgDepth > 0 {
+			p.maxReorgDepth = conf.TxPool.MaxReorgDepth
+		}
+		p.minGasPrice = conf.TxPool.MinGasPrice
+		p.minConfirmations = conf.TxPool.MinConfirmations
+		if conf.TxPool.ClearInterval > 0 {
+			p.clearInterval = conf.TxPool.ClearInterval
+		}
+		if conf.TxPool.MaxBlockListSize > 0 {
+			p.maxBlockListSize = conf.TxPool.MaxBlockListSize
+		}
+		if conf.TxPool.MaxPendingTxs > 0 {
+			p.maxPendingTxs = conf.TxPool.MaxPendingTxs
+		}
+		policy, err := ParseOrderingPolicy(conf.TxPool.OrderingPolicy)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %v", err, conf.TxPool.OrderingPolicy)
+		}
+		p.pendingTx = NewSortedTxMapWithPolicy(policy)
 	}
+	chP2PTx, err := p2p.Subscribe(p2pService, "txpool message", p2p.PublishTx)
+	if err != nil {
+		return nil, err
+	}
+	p.chP2PTx = chP2PTx
 	p.forkChain.SetNewHead(blockCache.Head())
 	deferServer, err := NewDeferServer(p)
 	if err != nil {
@@ -71,7 +125,7 @@ func (pool *TxPImpl) Stop() {
 
 // AddDefertx adds defer transaction.
 func (pool *TxPImpl) AddDefertx(txHash []byte) error {
-	if pool.pendingTx.Size() > maxCacheTxs {
+	if pool.pendingTx.Size() >= pool.maxPendingTxs {
 		return ErrCacheFull
 	}
 	referredTx, err := pool.global.BlockChain().GetTx(txHash)
@@ -109,7 +163,7 @@ func (pool *TxPImpl) loop() {
 	for i := 0; i < workerCnt; i++ {
 		go pool.verifyWorkers()
 	}
-	clearTx := time.NewTicker(clearInterval)
+	clearTx := time.NewTimer(jitterInterval(pool.clearInterval))
 	defer clearTx.Stop()
 	for {
 		select {
@@ -119,12 +173,43 @@ func (pool *TxPImpl) loop() {
 			pool.clearTimeoutTx()
 			pool.mu.Unlock()
 			metricsTxPoolSize.Set(float64(pool.pendingTx.Size()), nil)
+			clearTx.Reset(jitterInterval(pool.clearInterval))
 		case <-pool.quitCh:
 			return
 		}
 	}
 }
 
+// SetMinGasPrice sets the min gas ratio of the txs received from p2p.
+func (pool *TxPImpl) SetMinGasPrice(p int64) {
+	atomic.StoreInt64(&pool.minGasPrice, p)
+}
+
+// SetContractFilter sets the filter which decides whether an action is accepted into the pool.
+// A tx is rejected if any of its actions is not accepted. A nil filter accepts all txs.
+func (pool *TxPImpl) SetContractFilter(fn func(action *tx.Action) bool) {
+	pool.contractFilter.Store(contractFilter{fn: fn})
+}
+
+func (pool *TxPImpl) verifyContractFilter(t *tx.Tx) error {
+	f, ok := pool.contractFilter.Load().(contractFilter)
+	if !ok || f.fn == nil {
+		return nil
+	}
+	for _, a := range t.Actions {
+		if !f.fn(a) {
+			metricsRejectedFilterCount.Add(1, nil)
+			return ErrTxFiltered
+		}
+	}
+	return nil
+}
+
+// jitterInterval randomizes d by clearJitter so that the nodes do not clean up in lockstep.
+func jitterInterval(d time.Duration) time.Duration {
+	return d + time.Duration(float64(d)*clearJitter*(2*rand.Float64()-1))
+}
+
 // Lock lock the txpool
 func (pool *TxPImpl) Lock() {
 	pool.mu.Lock()
@@ -136,6 +221,16 @@ func (pool *TxPImpl) PendingTx() (*SortedTxMap, *blockcache.BlockCacheNode) {
 	return pool.pendingTx, pool.forkChain.NewHead
 }
 
+// IteratePending calls fn for each pending tx in priority order until fn returns false.
+func (pool *TxPImpl) IteratePending(fn func(*tx.Tx) bool) {
+	iter := pool.pendingTx.Iter()
+	for t, ok := iter.Next(); ok; t, ok = iter.Next() {
+		if !fn(t) {
+			return
+		}
+	}
+}
+
 // Release release the txpool
 func (pool *TxPImpl) Release() {
 	close(pool.quitGenerateMode)
@@ -153,6 +248,10 @@ func (pool *TxPImpl) verifyWorkers() {
 			ilog.Errorf("decode tx error. err=%v", err)
 			continue
 		}
+		if t.GasRatio < atomic.LoadInt64(&pool.minGasPrice) {
+			metricsRejectedLowGasCount.Add(1, nil)
+			continue
+		}
 		pool.mu.Lock()
 		ret := pool.verifyDuplicate(&t)
 		if ret != nil {
@@ -160,6 +259,9 @@ func (pool *TxPImpl) verifyWorkers() {
 			continue
 		}
 		ret = pool.verifyTx(&t)
+		if ret == nil {
+			ret = pool.evictFor(&t)
+		}
 		if ret != nil {
 			pool.mu.Unlock()
 			continue
@@ -188,6 +290,24 @@ func (pool *TxPImpl) processDelaytx(blk *block.Block) {
 
 // AddLinkedNode add the findBlock
 func (pool *TxPImpl) AddLinkedNode(linkedNode *blockcache.BlockCacheNode) error {
+	pool.nodeIntake <- struct{}{}
+	defer func() { <-pool.nodeIntake }()
+	return pool.addLinkedNode(linkedNode)
+}
+
+// TryAddLinkedNode is like AddLinkedNode, but returns ErrPoolBusy instead of waiting
+// when intakeSize blocks are already being added.
+func (pool *TxPImpl) TryAddLinkedNode(linkedNode *blockcache.BlockCacheNode) error {
+	select {
+	case pool.nodeIntake <- struct{}{}:
+	default:
+		return ErrPoolBusy
+	}
+	defer func() { <-pool.nodeIntake }()
+	return pool.addLinkedNode(linkedNode)
+}
+
+func (pool *TxPImpl) addLinkedNode(linkedNode *blockcache.BlockCacheNode) error {
 	pool.mu.Lock()
 	defer pool.mu.Unlock()
 	pool.processDelaytx(linkedNode.Block)
@@ -206,34 +326,77 @@ func (pool *TxPImpl) AddLinkedNode(linkedNode *blockcache.BlockCacheNode) error
 	switch typeOfFork {
 	case forkBCN:
 		pool.doChainChangeByForkBCN()
+		pool.publishReorg()
 	case noForkBCN:
 		pool.doChainChangeByTimeout()
 	case sameHead:
+	case forkError:
+		return ErrForkError
 	default:
 		return errors.New("failed to tFort")
 	}
 	return nil
 }
 
-// AddTx add the transaction
+// AddTx add the transaction, waiting while intakeSize txs are already being added.
 func (pool *TxPImpl) AddTx(t *tx.Tx) error {
+	pool.txIntake <- struct{}{}
+	defer func() { <-pool.txIntake }()
+	return pool.addTx(t)
+}
+
+// TryAddTx is like AddTx, but returns ErrPoolBusy instead of waiting
+// when intakeSize txs are already being added.
+func (pool *TxPImpl) TryAddTx(t *tx.Tx) error {
+	select {
+	case pool.txIntake <- struct{}{}:
+	default:
+		return ErrPoolBusy
+	}
+	defer func() { <-pool.txIntake }()
+	return pool.addTx(t)
+}
+
+// addTx checks the signatures of t before taking mu, and broadcasts it after releasing mu.
+func (pool *TxPImpl) addTx(t *tx.Tx) error {
+	if err := tx.ValidateTx(t, time.Now().UnixNano()); err != nil {
+		return err
+	}
+	pool.mu.Lock()
+	err := pool.admitTx(t)
+	pool.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	pool.p2pService.Broadcast(t.Encode(), p2p.PublishTx, p2p.NormalMessage)
+	metricsReceivedTxCount.Add(1, map[string]string{"from": "rpc"})
+	return nil
+}
+
+// admitTx checks t against the pool and adds it to pendingTx, evicting cheaper txs if the pool is full.
+// The caller must hold mu and have validated t by tx.ValidateTx.
+func (pool *TxPImpl) admitTx(t *tx.Tx) error {
 	err := pool.verifyDuplicate(t)
 	if err != nil {
 		return err
 	}
-	err = pool.verifyTx(t)
+	err = pool.verifyAdmission(t)
+	if err != nil {
+		return err
+	}
+	err = pool.evictFor(t)
 	if err != nil {
 		return err
 	}
 	pool.pendingTx.Add(t)
+	pool.txBloom.mu.Lock()
+	pool.txBloom.add(t.Hash())
+	pool.txBloom.mu.Unlock()
 	ilog.Debugf(
 		"Added %v to pendingTx, now size is %v.",
 		common.Base58Encode(t.Hash()),
 		pool.pendingTx.Size(),
 	)
-
-	pool.p2pService.Broadcast(t.Encode(), p2p.PublishTx, p2p.NormalMessage)
-	metricsReceivedTxCount.Add(1, map[string]string{"from": "rpc"})
 	return nil
 }
 
@@ -250,7 +413,26 @@ func (pool *TxPImpl) DelTxList(delList []*tx.Tx) {
 	}
 }
 
-// ExistTxs determine if the transaction exists
+// CancelTx removes the pending tx if proof is a signature over its hash by a key which signed it as the publisher.
+func (pool *TxPImpl) CancelTx(hash []byte, proof *crypto.Signature) error {
+	t := pool.pendingTx.Get(hash)
+	if t == nil {
+		return ErrTxNotFound
+	}
+	if proof == nil || !proof.Verify(hash) {
+		return ErrCancelProof
+	}
+	for _, sign := range t.PublishSigns {
+		if sign != nil && bytes.Equal(sign.Pubkey, proof.Pubkey) {
+			pool.delPendingTx(hash, "cancelled")
+			return nil
+		}
+	}
+	return ErrCancelProof
+}
+
+// ExistTxs determine if the transaction exists.
+// A tx in the chain with fewer confirmations than minConfirmations is FoundUnconfirmed, the block including it counts as one.
 func (pool *TxPImpl) ExistTxs(hash []byte, chainBlock *block.Block) FRet {
 	var r FRet
 	switch {
@@ -258,12 +440,26 @@ func (pool *TxPImpl) ExistTxs(hash []byte, chainBlock *block.Block) FRet {
 		r = FoundPending
 	case pool.existTxInChain(hash, chainBlock):
 		r = FoundChain
+		if pool.minConfirmations > 0 {
+			if _, _, depth := pool.findTxInChain(hash, chainBlock); depth+1 < pool.minConfirmations {
+				r = FoundUnconfirmed
+			}
+		}
 	default:
 		r = NotFound
 	}
 	return r
 }
 
+// TxStatus returns whether the transaction is pending, in the chain of the current fork head or not found.
+func (pool *TxPImpl) TxStatus(hash []byte) (FRet, error) {
+	head := pool.forkChain.GetNewHead()
+	if head == nil {
+		return NotFound, ErrNoForkHead
+	}
+	return pool.ExistTxs(hash, head.Block), nil
+}
+
 func (pool *TxPImpl) initBlockTx() {
 	filterLimit := time.Now().UnixNano() - filterTime
 	for i := pool.global.BlockChain().Length() - 1; i > 0; i-- {
@@ -279,20 +475,36 @@ func (pool *TxPImpl) initBlockTx() {
 }
 
 func (pool *TxPImpl) verifyTx(t *tx.Tx) error {
-	if pool.pendingTx.Size() > maxCacheTxs {
-		return ErrCacheFull
+	if err := pool.verifyAdmission(t); err != nil {
+		return err
+	}
+	return tx.ValidateTx(t, time.Now().UnixNano())
+}
+
+// verifyAdmission checks t against the state of the pool: its size and the contract filter.
+func (pool *TxPImpl) verifyAdmission(t *tx.Tx) error {
+	if pool.pendingTx.Size() >= pool.maxPendingTxs {
+		if c := pool.pendingTx.Cheapest(); c == nil || t.EffectiveGasRatio() <= c.EffectiveGasRatio() {
+			return ErrCacheFull
+		}
 	}
 	if t.IsDefer() {
 		return errors.New("reject defertx")
 	}
-	// Add one second delay for tx created time check
-	if !t.IsCreatedBefore(time.Now().UnixNano()+maxTxTimeGap) || t.IsExpired(time.Now().UnixNano()) {
-		return fmt.Errorf("TimeError")
-	}
-	if err := t.VerifySelf(); err != nil {
-		return fmt.Errorf("VerifyError %v", err)
-	}
+	return pool.verifyContractFilter(t)
+}
 
+// evictFor makes room for t in the full pendingTx by evicting the txs of the lowest effective gas ratio.
+// It returns ErrCacheFull if t doesn't pay more than all of them.
+func (pool *TxPImpl) evictFor(t *tx.Tx) error {
+	for pool.pendingTx.Size() >= pool.maxPendingTxs {
+		c := pool.pendingTx.Cheapest()
+		if c == nil || t.EffectiveGasRatio() <= c.EffectiveGasRatio() {
+			return ErrCacheFull
+		}
+		pool.delPendingTx(c.Hash(), "evicted")
+		metricsEvictedCount.Add(1, nil)
+	}
 	return nil
 }
 
@@ -300,7 +512,13 @@ func (pool *TxPImpl) addBlock(blk *block.Block) error {
 	if blk == nil {
 		return errors.New("failed to linkedBlock")
 	}
-	pool.blockList.LoadOrStore(string(blk.HeadHash()), newBlockTx(blk))
+	pool.txBloom.mu.Lock()
+	defer pool.txBloom.mu.Unlock()
+	if _, loaded := pool.blockList.LoadOrStore(string(blk.HeadHash()), newBlockTx(blk)); !loaded {
+		for _, t := range blk.Txs {
+			pool.txBloom.add(t.Hash())
+		}
+	}
 	return nil
 }
 
@@ -320,30 +538,54 @@ func (pool *TxPImpl) findBlock(hash []byte) (*blockTx, bool) {
 }
 
 func (pool *TxPImpl) getTxAndReceiptInChain(txHash []byte, block *block.Block) (*tx.Tx, *tx.TxReceipt) {
+	t, tr, _ := pool.findTxInChain(txHash, block)
+	return t, tr
+}
+
+// findTxInChain returns the tx and its receipt in the chain ending at block, and the number of
+// blocks after the one including the tx, which is 0 if block includes it.
+func (pool *TxPImpl) findTxInChain(txHash []byte, block *block.Block) (*tx.Tx, *tx.TxReceipt, int64) {
 	if block == nil {
-		return nil, nil
+		return nil, nil, 0
 	}
 	blkHash := block.HeadHash()
 	filterLimit := block.Head.Time - filterTime
 	var ok bool
-	for {
+	for depth := int64(0); ; depth++ {
 		t, tr := pool.getTxAndReceiptInBlock(txHash, blkHash)
 		if t != nil {
-			return t, tr
+			return t, tr, depth
 		}
 		blkHash, ok = pool.parentHash(blkHash)
 		if !ok {
-			return nil, nil
+			return nil, nil, 0
 		}
 		if b, ok := pool.findBlock(blkHash); ok {
 			if b.time < filterLimit {
-				return nil, nil
+				return nil, nil, 0
 			}
 		}
 	}
 }
 
+// ConfirmedReceipt returns the receipt of the tx in the blocks at or below the linked root,
+// so that the receipt will not be reverted by a reorg.
+// The txs older than the block list are looked up in the block chain, which only holds irreversible blocks.
+func (pool *TxPImpl) ConfirmedReceipt(hash []byte) (*tx.TxReceipt, bool) {
+	if _, tr := pool.getTxAndReceiptInChain(hash, pool.blockCache.LinkedRoot().Block); tr != nil {
+		return tr, true
+	}
+	tr, err := pool.global.BlockChain().GetReceiptByTxHash(hash)
+	if err != nil {
+		return nil, false
+	}
+	return tr, true
+}
+
 func (pool *TxPImpl) existTxInChain(txHash []byte, block *block.Block) bool {
+	if block == nil || !pool.txBloom.mayContain(txHash) {
+		return false
+	}
 	t, _ := pool.getTxAndReceiptInChain(txHash, block)
 	return t != nil
 }
@@ -358,12 +600,44 @@ func (pool *TxPImpl) getTxAndReceiptInBlock(txHash []byte, blockHash []byte) (*t
 
 func (pool *TxPImpl) clearBlock() {
 	filterLimit := pool.blockCache.LinkedRoot().Block.Head.Time - filterTime
+	pool.txBloom.mu.Lock()
+	defer pool.txBloom.mu.Unlock()
+	remains := make([]interface{}, 0)
 	pool.blockList.Range(func(key, value interface{}) bool {
 		if value.(*blockTx).time < filterLimit {
 			pool.blockList.Delete(key)
+		} else {
+			remains = append(remains, key)
 		}
 		return true
 	})
+	// A burst of blocks within filterTime is capped by count, the oldest ones are evicted first.
+	if len(remains) > pool.maxBlockListSize {
+		timeOf := func(key interface{}) int64 {
+			v, _ := pool.blockList.Load(key)
+			return v.(*blockTx).time
+		}
+		sort.Slice(remains, func(i, j int) bool {
+			return timeOf(remains[i]) < timeOf(remains[j])
+		})
+		for _, key := range remains[:len(remains)-pool.maxBlockListSize] {
+			pool.blockList.Delete(key)
+		}
+	}
+	pool.txBloom.reset()
+	pool.blockList.Range(func(key, value interface{}) bool {
+		value.(*blockTx).txMap.Range(func(hash, _ interface{}) bool {
+			pool.txBloom.add([]byte(hash.(string)))
+			return true
+		})
+		return true
+	})
+}
+
+// BloomStats returns the number of tx hashes in the bloom filter of the recent txs
+// and its estimated false positive rate.
+func (pool *TxPImpl) BloomStats() (n int, fpRate float64) {
+	return pool.txBloom.stats()
 }
 
 func (pool *TxPImpl) verifyDuplicate(t *tx.Tx) error {
@@ -385,19 +659,37 @@ func (pool *TxPImpl) clearTimeoutTx() {
 	t, ok := iter.Next()
 	for ok {
 		if t.IsExpired(time.Now().UnixNano()) && !t.IsDefer() {
-			pool.pendingTx.Del(t.Hash())
+			pool.delPendingTx(t.Hash(), "expired")
 		}
 		t, ok = iter.Next()
 	}
 }
 
+// delPendingTx deletes the tx from pending and records how long it was pending.
+func (pool *TxPImpl) delPendingTx(hash []byte, result string) {
+	if addedAt, ok := pool.pendingTx.AddedAt(hash); ok {
+		metricsTxPendingTime.Observe(time.Since(addedAt).Seconds(), map[string]string{"result": result})
+	}
+	pool.pendingTx.Del(hash)
+}
+
 func (pool *TxPImpl) updateForkChain(newHead *blockcache.BlockCacheNode) tFork {
-	if pool.forkChain.GetNewHead() == newHead {
+	oldHead := pool.forkChain.GetNewHead()
+	if oldHead == newHead {
 		return sameHead
 	}
-	pool.forkChain.SetOldHead(pool.forkChain.GetNewHead())
+	bcn, ok := pool.findForkBCN(newHead, oldHead)
+	if ok {
+		depth := oldHead.Head.Number - bcn.Head.Number
+		metricsForkDepth.Set(float64(depth), nil)
+		if depth > pool.maxReorgDepth {
+			ilog.Errorf("reorg depth %v exceeds the limit %v, old head: %v, new head: %v",
+				depth, pool.maxReorgDepth, common.Base58Encode(oldHead.HeadHash()), common.Base58Encode(newHead.HeadHash()))
+			return forkError
+		}
+	}
+	pool.forkChain.SetOldHead(oldHead)
 	pool.forkChain.SetNewHead(newHead)
-	bcn, ok := pool.findForkBCN(pool.forkChain.GetNewHead(), pool.forkChain.GetOldHead())
 	if ok {
 		pool.forkChain.SetForkBCN(bcn)
 		return forkBCN
@@ -406,6 +698,18 @@ func (pool *TxPImpl) updateForkChain(newHead *blockcache.BlockCacheNode) tFork {
 	return noForkBCN
 }
 
+// ForkInfo returns copies of the head hashes of the fork chain, nil when unset.
+func (pool *TxPImpl) ForkInfo() (newHead, oldHead, forkHash []byte) {
+	return bcnHash(pool.forkChain.GetNewHead()), bcnHash(pool.forkChain.GetOldHead()), bcnHash(pool.forkChain.GetForkBCN())
+}
+
+func bcnHash(bcn *blockcache.BlockCacheNode) []byte {
+	if bcn == nil || bcn.Block == nil {
+		return nil
+	}
+	return append([]byte(nil), bcn.HeadHash()...)
+}
+
 func (pool *TxPImpl) findForkBCN(newHead *blockcache.BlockCacheNode, oldHead *blockcache.BlockCacheNode) (*blockcache.BlockCacheNode, bool) {
 	for {
 		for oldHead != nil && oldHead.Head.Number > newHead.Head.Number {
@@ -446,7 +750,7 @@ func (pool *TxPImpl) doChainChangeByForkBCN() {
 			break
 		}
 		for _, t := range newHead.Block.Txs {
-			pool.DelTx(t.Hash())
+			pool.delPendingTx(t.Hash(), "included")
 		}
 		newHead = newHead.GetParent()
 	}
@@ -479,7 +783,7 @@ func (pool *TxPImpl) doChainChangeByTimeout() {
 				break
 			}
 			nb.txMap.Range(func(k, v interface{}) bool {
-				pool.DelTx(v.(*tx.Tx).Hash())
+				pool.delPendingTx(v.(*tx.Tx).Hash(), "included")
 				return true
 			})
 			nb, ok = pool.findBlock(nb.ParentHash)
@@ -2,6 +2,7 @@ package txpool
 
 import (
 	"encoding/json"
+	"errors"
 	"testing"
 	"time"
 
@@ -171,6 +172,8 @@ func TestNewTxPImpl(t *testing.T) {
 		})
 		Convey("delTimeOutTx", func() {
 
+			defer func(w int64) { tx.MinExpirationWindow = w }(tx.MinExpirationWindow)
+			tx.MinExpirationWindow = 0
 			t := genTx(accountList[0], int64(30*time.Millisecond))
 			So(txPool.testPendingTxsNum(), ShouldEqual, 0)
 
@@ -181,6 +184,100 @@ func TestNewTxPImpl(t *testing.T) {
 			txPool.clearTimeoutTx()
 			So(txPool.testPendingTxsNum(), ShouldEqual, 0)
 		})
+		Convey("ExportPending and ImportPending", func() {
+
+			defer func(w int64) { tx.MinExpirationWindow = w }(tx.MinExpirationWindow)
+			tx.MinExpirationWindow = 0
+			t1 := genTx(accountList[0], tx.MaxExpiration)
+			t2 := genTx(accountList[1], int64(30*time.Millisecond))
+			So(txPool.AddTx(t1), ShouldBeNil)
+			So(txPool.AddTx(t2), ShouldBeNil)
+
+			data, err := txPool.ExportPending()
+			So(err, ShouldBeNil)
+			txPool.DelTxList([]*tx.Tx{t1, t2})
+			So(txPool.testPendingTxsNum(), ShouldEqual, 0)
+
+			time.Sleep(50 * time.Millisecond)
+			n, err := txPool.ImportPending(data)
+			So(err, ShouldBeNil)
+			So(n, ShouldEqual, 1)
+			So(txPool.existTxInPending(t1.Hash()), ShouldBeTrue)
+			So(txPool.existTxInPending(t2.Hash()), ShouldBeFalse)
+
+			n, err = txPool.ImportPending(data)
+			So(err, ShouldBeNil)
+			So(n, ShouldEqual, 0)
+
+			txPool.DelTxList([]*tx.Tx{t1})
+			txPool.maxPendingTxs = 1
+			So(txPool.AddTx(genTxWithGasRatio(accountList[2], tx.MaxExpiration, 200)), ShouldBeNil)
+			n, err = txPool.ImportPending(data)
+			So(err, ShouldBeNil)
+			So(n, ShouldEqual, 0)
+			So(txPool.testPendingTxsNum(), ShouldEqual, 1)
+		})
+		Convey("min gas price", func() {
+
+			txPool.SetMinGasPrice(101)
+			t1 := genTxMsg(accountList[0], tx.MaxExpiration)
+			p2pCh <- *t1
+			time.Sleep(100 * time.Millisecond)
+			So(txPool.testPendingTxsNum(), ShouldEqual, 0)
+
+			txPool.SetMinGasPrice(100)
+			t2 := genTxMsg(accountList[1], tx.MaxExpiration)
+			p2pCh <- *t2
+			time.Sleep(100 * time.Millisecond)
+			So(txPool.testPendingTxsNum(), ShouldEqual, 1)
+		})
+		Convey("TryAddTx when busy", func() {
+
+			t1 := genTx(accountList[0], tx.MaxExpiration)
+			for i := 0; i < cap(txPool.txIntake); i++ {
+				txPool.txIntake <- struct{}{}
+				txPool.nodeIntake <- struct{}{}
+			}
+			So(txPool.TryAddTx(t1), ShouldEqual, ErrPoolBusy)
+			So(txPool.TryAddLinkedNode(BlockCache.Head()), ShouldEqual, ErrPoolBusy)
+			So(txPool.testPendingTxsNum(), ShouldEqual, 0)
+
+			done := make(chan error)
+			go func() { done <- txPool.AddTx(t1) }()
+			time.Sleep(50 * time.Millisecond)
+			So(txPool.testPendingTxsNum(), ShouldEqual, 0)
+			for i := 0; i < cap(txPool.txIntake); i++ {
+				<-txPool.txIntake
+				<-txPool.nodeIntake
+			}
+			So(<-done, ShouldBeNil)
+			So(txPool.testPendingTxsNum(), ShouldEqual, 1)
+
+			t2 := genTx(accountList[1], tx.MaxExpiration)
+			So(txPool.TryAddTx(t2), ShouldBeNil)
+			So(txPool.testPendingTxsNum(), ShouldEqual, 2)
+		})
+		Convey("contract filter", func() {
+
+			txPool.SetContractFilter(func(a *tx.Action) bool {
+				return a.Contract != "contract2"
+			})
+			t1 := genTx(accountList[0], tx.MaxExpiration)
+			So(txPool.AddTx(t1), ShouldEqual, ErrTxFiltered)
+			p2pCh <- *genTxMsg(accountList[1], tx.MaxExpiration)
+			time.Sleep(100 * time.Millisecond)
+			So(txPool.testPendingTxsNum(), ShouldEqual, 0)
+
+			txPool.SetContractFilter(func(a *tx.Action) bool {
+				return a.Contract != "contract3"
+			})
+			So(txPool.AddTx(t1), ShouldBeNil)
+			So(txPool.testPendingTxsNum(), ShouldEqual, 1)
+
+			txPool.SetContractFilter(nil)
+			So(txPool.AddTx(genTx(accountList[1], tx.MaxExpiration)), ShouldBeNil)
+			So(txPool.testPendingTxsNum(), ShouldEqual, 2)
+		})
 		Convey("ExistTxs FoundPending", func() {
 
 			t := genTx(accountList[0], tx.MaxExpiration)
@@ -222,6 +319,131 @@ func TestNewTxPImpl(t *testing.T) {
 			r1 := txPool.ExistTxs(t.Hash(), bcn.Block)
 			So(r1, ShouldEqual, NotFound)
 		})
+		Convey("max pending txs", func() {
+
+			txPool.maxPendingTxs = 2
+			t1 := genTxWithGasRatio(accountList[0], tx.MaxExpiration, 200)
+			t2 := genTxWithGasRatio(accountList[1], tx.MaxExpiration, 300)
+			So(txPool.AddTx(t1), ShouldBeNil)
+			So(txPool.AddTx(t2), ShouldBeNil)
+
+			So(txPool.AddTx(genTxWithGasRatio(accountList[2], tx.MaxExpiration, 200)), ShouldEqual, ErrCacheFull)
+			So(txPool.testPendingTxsNum(), ShouldEqual, 2)
+
+			t3 := genTxWithGasRatio(accountList[2], tx.MaxExpiration, 400)
+			So(txPool.AddTx(t3), ShouldBeNil)
+			So(txPool.testPendingTxsNum(), ShouldEqual, 2)
+			So(txPool.existTxInPending(t1.Hash()), ShouldBeFalse)
+			So(txPool.existTxInPending(t2.Hash()), ShouldBeTrue)
+			So(txPool.existTxInPending(t3.Hash()), ShouldBeTrue)
+		})
+		Convey("CancelTx", func() {
+
+			t := genTx(accountList[0], tx.MaxExpiration)
+			So(txPool.AddTx(t), ShouldBeNil)
+			So(txPool.testPendingTxsNum(), ShouldEqual, 1)
+
+			So(txPool.CancelTx(t.Hash(), accountList[1].Sign(t.Hash())), ShouldEqual, ErrCancelProof)
+			forged := accountList[0].Sign(t.Hash())
+			forged.Sig[0] ^= 0xff
+			So(txPool.CancelTx(t.Hash(), forged), ShouldEqual, ErrCancelProof)
+			So(txPool.CancelTx(t.Hash(), nil), ShouldEqual, ErrCancelProof)
+			So(txPool.testPendingTxsNum(), ShouldEqual, 1)
+
+			So(txPool.CancelTx(t.Hash(), accountList[0].Sign(t.Hash())), ShouldBeNil)
+			So(txPool.testPendingTxsNum(), ShouldEqual, 0)
+			So(txPool.CancelTx(t.Hash(), accountList[0].Sign(t.Hash())), ShouldEqual, ErrTxNotFound)
+		})
+		Convey("TxStatus", func() {
+
+			t := genTx(accountList[0], tx.MaxExpiration)
+			r, err := txPool.TxStatus(t.Hash())
+			So(err, ShouldBeNil)
+			So(r, ShouldEqual, NotFound)
+
+			So(txPool.AddTx(t), ShouldBeNil)
+			r, err = txPool.TxStatus(t.Hash())
+			So(err, ShouldBeNil)
+			So(r, ShouldEqual, FoundPending)
+
+			b := genBlocks(accountList, witnessList, 1, 10, true)
+			bcn := blockcache.NewBCN(nil, b[0])
+			txPool.blockCache.Head().Head.Number = 0
+			So(txPool.AddLinkedNode(bcn), ShouldBeNil)
+			for i := 0; i < 20; i++ {
+				time.Sleep(20 * time.Millisecond)
+				if txPool.testBlockListNum() == 1 {
+					break
+				}
+			}
+			r, err = txPool.TxStatus(b[0].Txs[0].Hash())
+			So(err, ShouldBeNil)
+			So(r, ShouldEqual, FoundChain)
+		})
+		Convey("ExistTxs with min confirmations", func() {
+
+			b := genBlocks(accountList, witnessList, 2, 2, true)
+			for _, blk := range b {
+				So(txPool.addBlock(blk), ShouldBeNil)
+			}
+			So(txPool.ExistTxs(b[1].Txs[0].Hash(), b[1]), ShouldEqual, FoundChain)
+
+			txPool.minConfirmations = 2
+			defer func() { txPool.minConfirmations = 0 }()
+			So(txPool.ExistTxs(b[1].Txs[0].Hash(), b[1]), ShouldEqual, FoundUnconfirmed)
+			So(txPool.ExistTxs(b[0].Txs[0].Hash(), b[1]), ShouldEqual, FoundChain)
+			So(txPool.ExistTxs(b[0].Txs[0].Hash(), b[0]), ShouldEqual, FoundUnconfirmed)
+			So(txPool.ExistTxs(genTx(accountList[0], tx.MaxExpiration).Hash(), b[1]), ShouldEqual, NotFound)
+		})
+		Convey("clearBlock caps the block list by count", func() {
+
+			blockList := genBlocks(accountList, witnessList, 5, 2, true)
+			for _, blk := range blockList {
+				So(txPool.addBlock(blk), ShouldBeNil)
+			}
+			txPool.maxBlockListSize = 3
+			txPool.clearBlock()
+			So(txPool.testBlockListNum(), ShouldEqual, 3)
+			for i, blk := range blockList {
+				_, ok := txPool.findBlock(blk.HeadHash())
+				So(ok, ShouldEqual, i >= 2)
+			}
+			So(txPool.existTxInChain(blockList[4].Txs[0].Hash(), blockList[4]), ShouldBeTrue)
+			So(txPool.existTxInChain(blockList[1].Txs[0].Hash(), blockList[1]), ShouldBeFalse)
+		})
+		Convey("tx bloom has no false negatives", func() {
+
+			blockList := genBlocks(accountList, witnessList, 5, 20, true)
+			for _, blk := range blockList {
+				So(txPool.addBlock(blk), ShouldBeNil)
+			}
+			n, fpRate := txPool.BloomStats()
+			So(n, ShouldEqual, 100)
+			So(fpRate, ShouldBeLessThan, txBloomErrRate)
+
+			head := blockList[len(blockList)-1]
+			for _, blk := range blockList {
+				for _, t := range blk.Txs {
+					So(txPool.txBloom.mayContain(t.Hash()), ShouldBeTrue)
+					So(txPool.existTxInChain(t.Hash(), head), ShouldBeTrue)
+				}
+			}
+
+			txPool.clearBlock()
+			live := 0
+			for _, blk := range blockList {
+				if _, ok := txPool.findBlock(blk.HeadHash()); !ok {
+					continue
+				}
+				live++
+				for _, t := range blk.Txs {
+					So(txPool.existTxInChain(t.Hash(), head), ShouldBeTrue)
+				}
+			}
+			n, _ = txPool.BloomStats()
+			So(n, ShouldEqual, live*20)
+			So(txPool.existTxInChain(genTx(accountList[0], tx.MaxExpiration).Hash(), head), ShouldBeFalse)
+		})
 		stopTest(gbl)
 	})
 
@@ -338,6 +560,30 @@ func TestNewTxPImplB(t *testing.T) {
 
 		})
 
+		Convey("pending time of included tx", func() {
+			recorder := &histogramRecorder{values: make(map[string][]float64)}
+			origin := metricsTxPendingTime
+			metricsTxPendingTime = recorder
+			defer func() { metricsTxPendingTime = origin }()
+
+			blockList := genBlocks(accountList, witnessList, 1, 3, true)
+			txPool.blockCache.Head().Head.Number = 0
+			for _, t := range blockList[0].Txs {
+				So(txPool.AddTx(t), ShouldBeNil)
+			}
+			So(txPool.testPendingTxsNum(), ShouldEqual, 3)
+			time.Sleep(10 * time.Millisecond)
+
+			bcn := BlockCache.Add(blockList[0])
+			So(bcn, ShouldNotBeNil)
+			So(txPool.AddLinkedNode(bcn), ShouldBeNil)
+			So(txPool.testPendingTxsNum(), ShouldEqual, 0)
+			So(len(recorder.values["included"]), ShouldEqual, 3)
+			for _, v := range recorder.values["included"] {
+				So(v, ShouldBeGreaterThan, 0)
+			}
+		})
+
 		Convey("doChainChange", func() {
 
 			txCnt := 10
@@ -366,6 +612,11 @@ func TestNewTxPImplB(t *testing.T) {
 			// fork chain
 			err = txPool.AddLinkedNode(bcn)
 			So(err, ShouldBeNil)
+			newHead, oldHead, forkHash := txPool.ForkInfo()
+			So(newHead, ShouldResemble, forkBlock.HeadHash())
+			So(oldHead, ShouldResemble, blockList[2].HeadHash())
+			// genSingleBlock numbers the fork block 1, so the fork point is found at the first block.
+			So(forkHash, ShouldResemble, blockList[0].HeadHash())
 			// need delay
 			for i := 0; i < 20; i++ {
 				time.Sleep(20 * time.Millisecond)
@@ -377,6 +628,123 @@ func TestNewTxPImplB(t *testing.T) {
 			So(txPool.testPendingTxsNum(), ShouldEqual, 10)
 		})
 
+		Convey("SubscribeReorg", func() {
+			blockList := genBlocks(accountList, witnessList, 3, 10, true)
+			txPool.blockCache.Head().Head.Number = 0
+			for _, blk := range blockList {
+				bcn := BlockCache.Add(blk)
+				So(bcn, ShouldNotBeNil)
+				So(txPool.AddLinkedNode(bcn), ShouldBeNil)
+			}
+			reorgCh := txPool.SubscribeReorg()
+			So(len(reorgCh), ShouldEqual, 0)
+
+			forkBlock := genSingleBlock(accountList, witnessList, blockList[0].HeadHash(), 6)
+			forkBlock.Head.Number = 2
+			forkBlock.CalculateHeadHash()
+			bcn := BlockCache.Add(forkBlock)
+			So(bcn, ShouldNotBeNil)
+			So(txPool.AddLinkedNode(bcn), ShouldBeNil)
+
+			So(len(reorgCh), ShouldEqual, 1)
+			ev := <-reorgCh
+			So(ev.OldHead, ShouldResemble, blockList[2].HeadHash())
+			So(ev.NewHead, ShouldResemble, forkBlock.HeadHash())
+			So(ev.ForkHash, ShouldResemble, blockList[0].HeadHash())
+			So(ev.RevertedTxs, ShouldResemble, append(append([]*tx.Tx{}, blockList[2].Txs...), blockList[1].Txs...))
+			So(ev.AppliedTxs, ShouldResemble, forkBlock.Txs)
+
+			otherCh := txPool.SubscribeReorg()
+			txPool.UnsubscribeReorg(otherCh)
+			_, ok := <-otherCh
+			So(ok, ShouldBeFalse)
+
+			for i := 0; i <= reorgChSize; i++ {
+				txPool.publishReorg()
+			}
+			So(len(txPool.reorgSubs), ShouldEqual, 0)
+			for i := 0; i < reorgChSize; i++ {
+				<-reorgCh
+			}
+			_, ok = <-reorgCh
+			So(ok, ShouldBeFalse)
+		})
+
+		Convey("ConfirmedReceipt", func() {
+
+			blockList := genBlocks(accountList, witnessList, 3, 2, true)
+			forkBlock := genSingleBlock(accountList, witnessList, blockList[0].HeadHash(), 2)
+			for _, blk := range append(blockList, forkBlock) {
+				for i, t := range blk.Txs {
+					blk.Receipts[i].TxHash = t.Hash()
+				}
+			}
+			chainReceipts := make(map[string]*tx.TxReceipt)
+			base.EXPECT().GetReceiptByTxHash(Any()).AnyTimes().DoAndReturn(func(hash []byte) (*tx.TxReceipt, error) {
+				if tr, ok := chainReceipts[string(hash)]; ok {
+					return tr, nil
+				}
+				return nil, errors.New("not found")
+			})
+			txPool.blockCache.Head().Head.Number = 0
+			bcns := make([]*blockcache.BlockCacheNode, 0)
+			for _, blk := range blockList {
+				bcn := BlockCache.Add(blk)
+				So(bcn, ShouldNotBeNil)
+				So(txPool.AddLinkedNode(bcn), ShouldBeNil)
+				bcns = append(bcns, bcn)
+			}
+			bcn := BlockCache.Add(forkBlock)
+			So(bcn, ShouldNotBeNil)
+			So(txPool.AddLinkedNode(bcn), ShouldBeNil)
+
+			_, ok := txPool.ConfirmedReceipt(blockList[1].Txs[0].Hash())
+			So(ok, ShouldBeFalse)
+
+			BlockCache.SetLinkedRoot(bcns[1])
+			tr, ok := txPool.ConfirmedReceipt(blockList[1].Txs[0].Hash())
+			So(ok, ShouldBeTrue)
+			So(tr, ShouldEqual, blockList[1].Receipts[0])
+			_, ok = txPool.ConfirmedReceipt(blockList[0].Txs[1].Hash())
+			So(ok, ShouldBeTrue)
+			_, ok = txPool.ConfirmedReceipt(blockList[2].Txs[0].Hash())
+			So(ok, ShouldBeFalse)
+			_, ok = txPool.ConfirmedReceipt(forkBlock.Txs[0].Hash())
+			So(ok, ShouldBeFalse)
+
+			old := genBlocks(accountList, witnessList, 1, 1, true)[0]
+			chainReceipts[string(old.Txs[0].Hash())] = old.Receipts[0]
+			tr, ok = txPool.ConfirmedReceipt(old.Txs[0].Hash())
+			So(ok, ShouldBeTrue)
+			So(tr, ShouldEqual, old.Receipts[0])
+		})
+
+		Convey("reorg deeper than maxReorgDepth", func() {
+
+			txCnt := 2
+			blockCnt := 3
+			blockList := genBlocks(accountList, witnessList, blockCnt, txCnt, true)
+			txPool.blockCache.Head().Head.Number = 0
+			for i := 0; i < blockCnt; i++ {
+				bcn := BlockCache.Add(blockList[i])
+				So(bcn, ShouldNotBeNil)
+
+				err = txPool.AddLinkedNode(bcn)
+				So(err, ShouldBeNil)
+			}
+			oldHead := txPool.forkChain.GetNewHead()
+
+			txPool.maxReorgDepth = 1
+			forkBlock := genSingleBlock(accountList, witnessList, blockList[0].HeadHash(), 2)
+			bcn := BlockCache.Add(forkBlock)
+			So(bcn, ShouldNotBeNil)
+
+			err = txPool.AddLinkedNode(bcn)
+			So(err, ShouldEqual, ErrForkError)
+			So(txPool.forkChain.GetNewHead(), ShouldEqual, oldHead)
+			So(txPool.testPendingTxsNum(), ShouldEqual, 0)
+		})
+
 		Convey("rbtree", func() {
 			t1 := genTx(newAccount, tx.MaxExpiration)
 			t2 := genTx(newAccount, tx.MaxExpiration)
@@ -442,6 +810,85 @@ func TestNewTxPImplB(t *testing.T) {
 			}
 			So(ok, ShouldBeFalse)
 
+			var visited []*tx.Tx
+			txPool.IteratePending(func(t *tx.Tx) bool {
+				visited = append(visited, t)
+				return len(visited) < 2
+			})
+			So(len(visited), ShouldEqual, 2)
+			So(common.Base58Encode(visited[0].Hash()), ShouldEqual, common.Base58Encode(t5.Hash()))
+			So(common.Base58Encode(visited[1].Hash()), ShouldEqual, common.Base58Encode(t4.Hash()))
+
+		})
+
+		Convey("rbtree with tip", func() {
+			low := genTx(newAccount, tx.MaxExpiration)
+			high := genTx(newAccount, tx.MaxExpiration)
+			top := genTx(newAccount, tx.MaxExpiration)
+			low.GasRatio, high.GasRatio, top.GasRatio = 200, 200, 300
+			high.Time = low.Time
+			high.SetTip(50)
+
+			st := NewSortedTxMap()
+			st.Add(low)
+			st.Add(top)
+			st.Add(high)
+			iter := st.Iter()
+			for _, expectTx := range []*tx.Tx{top, high, low} {
+				trx, ok := iter.Next()
+				So(ok, ShouldBeTrue)
+				So(common.Base58Encode(trx.Hash()), ShouldEqual, common.Base58Encode(expectTx.Hash()))
+			}
+			_, ok := iter.Next()
+			So(ok, ShouldBeFalse)
+		})
+
+		Convey("ordering policy", func() {
+			first := genTx(newAccount, tx.MaxExpiration)
+			second := genTx(newAccount, tx.MaxExpiration)
+			third := genTx(newAccount, tx.MaxExpiration)
+			first.GasRatio, second.GasRatio, third.GasRatio = 100, 300, 300
+			// third is created before second but arrives after it.
+			first.Time, second.Time, third.Time = 3, 2, 1
+
+			expected := map[OrderingPolicy][]*tx.Tx{
+				OrderByGasPrice:         {third, second, first},
+				OrderFIFO:               {first, second, third},
+				OrderByGasPriceThenFIFO: {second, third, first},
+			}
+			for policy, order := range expected {
+				st := NewSortedTxMapWithPolicy(policy)
+				st.Add(first)
+				st.Add(second)
+				st.Add(third)
+				st.Add(first)
+				So(st.Size(), ShouldEqual, 3)
+				So(st.Cheapest(), ShouldEqual, first)
+				iter := st.Iter()
+				for _, expectTx := range order {
+					trx, ok := iter.Next()
+					So(ok, ShouldBeTrue)
+					So(common.Base58Encode(trx.Hash()), ShouldEqual, common.Base58Encode(expectTx.Hash()))
+				}
+				_, ok := iter.Next()
+				So(ok, ShouldBeFalse)
+
+				st.Del(second.Hash())
+				So(st.Get(second.Hash()), ShouldBeNil)
+				So(st.Get(first.Hash()), ShouldEqual, first)
+				st.Del(first.Hash())
+				So(st.Cheapest(), ShouldEqual, third)
+				st.Del(third.Hash())
+				So(st.Cheapest(), ShouldBeNil)
+			}
+
+			for _, policy := range []OrderingPolicy{OrderByGasPrice, OrderFIFO, OrderByGasPriceThenFIFO} {
+				parsed, err := ParseOrderingPolicy(policy.String())
+				So(err, ShouldBeNil)
+				So(parsed, ShouldEqual, policy)
+			}
+			_, err := ParseOrderingPolicy("lifo")
+			So(err, ShouldEqual, ErrUnknownOrderingPolicy)
 		})
 
 		stopTest(gbl)
@@ -652,6 +1099,29 @@ func stopTest(gl global.BaseVariable) {
 	os.RemoveAll(walPath)
 }
 
+func TestJitterInterval(t *testing.T) {
+	Convey("test jitterInterval", t, func() {
+		lower := time.Duration(float64(clearInterval) * (1 - clearJitter))
+		upper := time.Duration(float64(clearInterval) * (1 + clearJitter))
+		seen := make(map[time.Duration]bool)
+		for i := 0; i < 100; i++ {
+			d := jitterInterval(clearInterval)
+			So(d, ShouldBeBetweenOrEqual, lower, upper)
+			seen[d] = true
+		}
+		So(len(seen), ShouldBeGreaterThan, 1)
+	})
+}
+
+type histogramRecorder struct {
+	values map[string][]float64
+}
+
+func (h *histogramRecorder) Observe(v float64, tagkv map[string]string) error {
+	h.values[tagkv["result"]] = append(h.values[tagkv["result"]], v)
+	return nil
+}
+
 func genTxReceipt() *tx.TxReceipt {
 	return &tx.TxReceipt{
 		Status: &tx.Status{},
@@ -659,6 +1129,10 @@ func genTxReceipt() *tx.TxReceipt {
 }
 
 func genTx(a *account.KeyPair, expirationIter int64) *tx.Tx {
+	return genTxWithGasRatio(a, expirationIter, 100)
+}
+
+func genTxWithGasRatio(a *account.KeyPair, expirationIter int64, gasRatio int64) *tx.Tx {
 	actions := make([]*tx.Action, 0)
 	actions = append(actions, &tx.Action{
 		Contract:   "contract1",
@@ -673,7 +1147,7 @@ func genTx(a *account.KeyPair, expirationIter int64) *tx.Tx {
 
 	ex := time.Now().UnixNano() + expirationIter
 
-	t := tx.NewTx(actions, []string{a.ReadablePubkey()}, 1000000, 100, ex, 0, 0)
+	t := tx.NewTx(actions, []string{a.ReadablePubkey()}, 1000000, gasRatio, ex, 0, 0)
 
 	sig1, err := tx.SignTxContent(t, a.ReadablePubkey(), a)
 	if err != nil {
@@ -16,17 +16,33 @@ import (
 // Values.
 var (
 	clearInterval = 10 * time.Second
+	clearJitter   = 0.1
 	filterTime    = int64(90 * time.Second)
 	maxCacheTxs   = 10000
-	maxTxTimeGap  = 5 * time.Second.Nanoseconds()
+	intakeSize    = 1024
+
+	defaultMaxReorgDepth    = int64(1000)
+	defaultMaxBlockListSize = 10000
 
 	metricsReceivedTxCount = metrics.NewCounter("iost_tx_received_count", []string{"from"})
 	metricsTxPoolSize      = metrics.NewGauge("iost_txpool_size", nil)
+	metricsForkDepth       = metrics.NewGauge("iost_txpool_fork_depth", nil)
+
+	metricsRejectedLowGasCount = metrics.NewCounter("iost_txpool_rejected_low_gas", nil)
+	metricsRejectedFilterCount = metrics.NewCounter("iost_txpool_rejected_filter", nil)
+	metricsEvictedCount        = metrics.NewCounter("iost_txpool_evicted_total", nil)
+	pendingTimeBuckets         = []float64{0.5, 1, 2, 5, 10, 20, 30, 60, 90}
+	metricsTxPendingTime       = metrics.NewHistogram("iost_txpool_pending_seconds", []string{"result"}, pendingTimeBuckets)
 
 	ErrDupPendingTx = errors.New("tx exists in pending")
 	ErrDupChainTx   = errors.New("tx exists in chain")
 	ErrCacheFull    = errors.New("txpool is full")
 	ErrTxNotFound   = errors.New("tx not found")
+	ErrForkError    = errors.New("fork is deeper than max reorg depth")
+	ErrTxFiltered   = errors.New("tx rejected by contract filter")
+	ErrPoolBusy     = errors.New("txpool is busy")
+	ErrNoForkHead   = errors.New("txpool has no fork head")
+	ErrCancelProof  = errors.New("cancel proof is not signed by the tx publisher")
 )
 
 // FRet find the return value of the tx
@@ -39,6 +55,8 @@ const (
 	FoundPending
 	// FoundChain ...
 	FoundChain
+	// FoundUnconfirmed is a tx in the chain with fewer confirmations than required.
+	FoundUnconfirmed
 )
 
 // tFork ...
@@ -48,6 +66,7 @@ const (
 	sameHead tFork = iota
 	forkBCN
 	noForkBCN
+	forkError
 )
 
 type forkChain struct {
@@ -138,58 +157,116 @@ func (b *blockTx) getTxAndReceipt(hash []byte) (*tx.Tx, *tx.TxReceipt) {
 
 // SortedTxMap is a red black tree of tx.
 type SortedTxMap struct {
-	tree  *redblacktree.Tree
-	txMap map[string]*tx.Tx
-	rw    *sync.RWMutex
+	tree *redblacktree.Tree
+	// priceTree orders the txs by effective gas ratio for eviction when tree doesn't, nil otherwise.
+	priceTree *redblacktree.Tree
+	txMap     map[string]*sortedTx
+	addedAt   map[string]time.Time
+	seq       uint64
+	policy    OrderingPolicy
+	rw        *sync.RWMutex
 }
 
 func compareTx(a, b interface{}) int {
 	txa := a.(*tx.Tx)
 	txb := b.(*tx.Tx)
-	if txa.GasRatio == txb.GasRatio && txb.Time == txa.Time {
+	ra, rb := txa.EffectiveGasRatio(), txb.EffectiveGasRatio()
+	if ra == rb && txb.Time == txa.Time {
 		return bytes.Compare(txa.Hash(), txb.Hash())
 	}
-	if txa.GasRatio == txb.GasRatio {
+	if ra == rb {
 		return int(txb.Time - txa.Time)
 	}
-	return int(txa.GasRatio - txb.GasRatio)
+	return int(ra - rb)
 }
 
-// NewSortedTxMap returns a new SortedTxMap instance.
+// NewSortedTxMap returns a new SortedTxMap instance ordered by gas price.
 func NewSortedTxMap() *SortedTxMap {
-	return &SortedTxMap{
-		tree:  redblacktree.NewWith(compareTx),
-		txMap: make(map[string]*tx.Tx),
-		rw:    new(sync.RWMutex),
+	return NewSortedTxMapWithPolicy(OrderByGasPrice)
+}
+
+// NewSortedTxMapWithPolicy returns a new SortedTxMap instance ordered by the policy.
+func NewSortedTxMapWithPolicy(policy OrderingPolicy) *SortedTxMap {
+	st := &SortedTxMap{
+		tree:    redblacktree.NewWith(policy.comparator()),
+		txMap:   make(map[string]*sortedTx),
+		addedAt: make(map[string]time.Time),
+		policy:  policy,
+		rw:      new(sync.RWMutex),
+	}
+	if policy == OrderFIFO {
+		st.priceTree = redblacktree.NewWith(OrderByGasPriceThenFIFO.comparator())
 	}
+	return st
 }
 
 // Get returns a tx of hash.
 func (st *SortedTxMap) Get(hash []byte) *tx.Tx {
 	st.rw.RLock()
 	defer st.rw.RUnlock()
-	return st.txMap[string(hash)]
+	if s, ok := st.txMap[string(hash)]; ok {
+		return s.tx
+	}
+	return nil
 }
 
 // Add adds a tx in SortedTxMap.
 func (st *SortedTxMap) Add(tx *tx.Tx) {
 	st.rw.Lock()
-	st.tree.Put(tx, true)
-	st.txMap[string(tx.Hash())] = tx
+	if _, ok := st.txMap[string(tx.Hash())]; !ok {
+		st.seq++
+		s := &sortedTx{tx: tx, seq: st.seq}
+		st.tree.Put(s, true)
+		if st.priceTree != nil {
+			st.priceTree.Put(s, true)
+		}
+		st.txMap[string(tx.Hash())] = s
+	}
+	if _, ok := st.addedAt[string(tx.Hash())]; !ok {
+		st.addedAt[string(tx.Hash())] = time.Now()
+	}
 	st.rw.Unlock()
 }
 
+// AddedAt returns the time the tx of hash was added in SortedTxMap.
+func (st *SortedTxMap) AddedAt(hash []byte) (time.Time, bool) {
+	st.rw.RLock()
+	defer st.rw.RUnlock()
+	t, ok := st.addedAt[string(hash)]
+	return t, ok
+}
+
 // Del deletes a tx in SortedTxMap.
 func (st *SortedTxMap) Del(hash []byte) {
 	st.rw.Lock()
 	defer st.rw.Unlock()
 
-	tx := st.txMap[string(hash)]
-	if tx == nil {
+	s := st.txMap[string(hash)]
+	if s == nil {
 		return
 	}
-	st.tree.Remove(tx)
+	st.tree.Remove(s)
+	if st.priceTree != nil {
+		st.priceTree.Remove(s)
+	}
 	delete(st.txMap, string(hash))
+	delete(st.addedAt, string(hash))
+}
+
+// Cheapest returns the tx of the lowest effective gas ratio, the newest one among equals.
+// It returns nil if SortedTxMap is empty.
+func (st *SortedTxMap) Cheapest() *tx.Tx {
+	st.rw.RLock()
+	defer st.rw.RUnlock()
+
+	tree := st.tree
+	if st.priceTree != nil {
+		tree = st.priceTree
+	}
+	if node := tree.Left(); node != nil {
+		return node.Key.(*sortedTx).tx
+	}
+	return nil
 }
 
 // Size returns the size of SortedTxMap.
@@ -234,7 +311,7 @@ func (iter *Iterator) getNext() {
 		iter.res <- &iterRes{nil, false}
 		return
 	}
-	iter.res <- &iterRes{iter.iter.Key().(*tx.Tx), true}
+	iter.res <- &iterRes{iter.iter.Key().(*sortedTx).tx, true}
 }
 
 // Next next the tx
@@ -1,6 +1,8 @@
 package crypto
 
 import (
+	"fmt"
+
 	"github.com/iost-official/go-iost/crypto/backend"
 	"github.com/iost-official/go-iost/ilog"
 )
@@ -24,6 +26,49 @@ const (
 	Ed25519
 )
 
+var expectedSeckeyLen = map[Algorithm]int{
+	Secp256k1: 32,
+	Ed25519:   64,
+}
+
+// SeckeyLen returns the expected secret key length of the algorithm, and false if the algorithm is not registered
+func (a Algorithm) SeckeyLen() (int, bool) {
+	l, ok := expectedSeckeyLen[a]
+	return l, ok
+}
+
+var expectedSigLen = map[Algorithm]int{
+	Secp256k1: 64,
+	Ed25519:   64,
+}
+
+var expectedPubkeyLen = map[Algorithm]int{
+	Secp256k1: 33,
+	Ed25519:   32,
+}
+
+// SigLen returns the expected signature length of the algorithm, and false if the algorithm is not registered
+func (a Algorithm) SigLen() (int, bool) {
+	l, ok := expectedSigLen[a]
+	return l, ok
+}
+
+// PubkeyLen returns the expected public key length of the algorithm, and false if the algorithm is not registered
+func (a Algorithm) PubkeyLen() (int, bool) {
+	l, ok := expectedPubkeyLen[a]
+	return l, ok
+}
+
+// AlgorithmOfPubkey returns the algorithm of a public key by its length, and false if no algorithm matches
+func AlgorithmOfPubkey(pubkey []byte) (Algorithm, bool) {
+	for a, l := range expectedPubkeyLen {
+		if len(pubkey) == l {
+			return a, true
+		}
+	}
+	return 0, false
+}
+
 func (a Algorithm) getBackend() AlgorithmBackend {
 	switch a {
 	case Secp256k1:
@@ -86,7 +131,14 @@ func (a Algorithm) GenSeckey() []byte {
 	return a.getBackend().GenSeckey()
 }
 
-// CheckSeckey ...
+// CheckSeckey checks the secret key length against the algorithm
 func (a Algorithm) CheckSeckey(seckey []byte) error {
+	l, ok := a.SeckeyLen()
+	if !ok {
+		return fmt.Errorf("unsupported algorithm %d", uint8(a))
+	}
+	if len(seckey) != l {
+		return fmt.Errorf("invalid %v seckey length, expected %v, got %v", a, l, len(seckey))
+	}
 	return a.getBackend().CheckSeckey(seckey)
 }
@@ -21,7 +21,8 @@ func (b *Secp256k1) Sign(message []byte, seckey []byte) []byte {
 	return sig[:64]
 }
 
-// Verify will verify the message with pubkey and sig by secp256k1
+// Verify will verify the message with pubkey and sig by secp256k1.
+// Signatures with a high s are malleable, libsecp256k1 only accepts the low-s form.
 func (b *Secp256k1) Verify(message []byte, pubkey []byte, sig []byte) bool {
 	return secp256k1.VerifySignature(pubkey, message, sig)
 }
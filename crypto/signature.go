@@ -3,6 +3,7 @@ package crypto
 import (
 	"bytes"
 	"errors"
+	"fmt"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/iost-official/go-iost/common"
@@ -27,6 +28,25 @@ func NewSignature(algo Algorithm, info []byte, privkey []byte) *Signature {
 	return s
 }
 
+// ValidateSignature checks the lengths of the signature and the public key against its algorithm.
+func ValidateSignature(s *Signature) error {
+	if s == nil {
+		return errors.New("signature is nil")
+	}
+	sigLen, ok := s.Algorithm.SigLen()
+	if !ok {
+		return fmt.Errorf("unknown signature algorithm %v", uint8(s.Algorithm))
+	}
+	if len(s.Sig) != sigLen {
+		return fmt.Errorf("%v signature length should be %v, got %v", s.Algorithm, sigLen, len(s.Sig))
+	}
+	pubkeyLen, _ := s.Algorithm.PubkeyLen()
+	if len(s.Pubkey) != pubkeyLen {
+		return fmt.Errorf("%v pubkey length should be %v, got %v", s.Algorithm, pubkeyLen, len(s.Pubkey))
+	}
+	return nil
+}
+
 // Verify will verify the info
 func (s *Signature) Verify(info []byte) bool {
 	return s.Algorithm.Verify(info, s.Pubkey, s.Sig)
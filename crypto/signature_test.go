@@ -6,6 +6,9 @@ import (
 	"bytes"
 	"encoding/base64"
 	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/crypto/secp256k1"
 
 	"github.com/iost-official/go-iost/common"
 	. "github.com/smartystreets/goconvey/convey"
@@ -35,6 +38,31 @@ func TestSignature(t *testing.T) {
 			So(sig.Algorithm, ShouldEqual, sig2.Algorithm)
 		})
 
+		Convey("Validate", func() {
+			info := common.Sha3([]byte("hello"))
+			for _, algo := range []Algorithm{Secp256k1, Ed25519} {
+				sig := NewSignature(algo, info, algo.GenSeckey())
+				So(ValidateSignature(sig), ShouldBeNil)
+
+				truncated := &Signature{Algorithm: algo, Sig: sig.Sig[:len(sig.Sig)-1], Pubkey: sig.Pubkey}
+				So(ValidateSignature(truncated), ShouldNotBeNil)
+				truncated = &Signature{Algorithm: algo, Sig: sig.Sig, Pubkey: sig.Pubkey[:len(sig.Pubkey)-1]}
+				So(ValidateSignature(truncated), ShouldNotBeNil)
+			}
+			So(ValidateSignature(&Signature{Algorithm: Algorithm(0)}), ShouldNotBeNil)
+			So(ValidateSignature(nil), ShouldNotBeNil)
+		})
+
+		Convey("Algorithm of pubkey", func() {
+			for _, algo := range []Algorithm{Secp256k1, Ed25519} {
+				a, ok := AlgorithmOfPubkey(algo.GetPubkey(algo.GenSeckey()))
+				So(ok, ShouldBeTrue)
+				So(a, ShouldEqual, algo)
+			}
+			_, ok := AlgorithmOfPubkey([]byte("short"))
+			So(ok, ShouldBeFalse)
+		})
+
 	})
 }
 
@@ -94,3 +122,23 @@ func TestSignature_Platform(t *testing.T) {
 	t.Log("sig in base64 >", base64.StdEncoding.EncodeToString(sig2))
 
 }
+
+func TestSecp256k1HighS(t *testing.T) {
+	Convey("Test of secp256k1 signature malleability", t, func() {
+		info := common.Sha3([]byte("hello"))
+		seckey := common.Sha3([]byte("seckey"))
+		sig := NewSignature(Secp256k1, info, seckey)
+		So(sig.Verify(info), ShouldBeTrue)
+
+		n := secp256k1.S256().Params().N
+		s := new(big.Int).SetBytes(sig.Sig[32:])
+		So(s.Cmp(new(big.Int).Rsh(n, 1)), ShouldBeLessThanOrEqualTo, 0)
+
+		highS := make([]byte, 64)
+		copy(highS, sig.Sig[:32])
+		b := new(big.Int).Sub(n, s).Bytes()
+		copy(highS[64-len(b):], b)
+		malleated := &Signature{Algorithm: Secp256k1, Sig: highS, Pubkey: sig.Pubkey}
+		So(malleated.Verify(info), ShouldBeFalse)
+	})
+}
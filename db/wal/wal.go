@@ -56,14 +56,24 @@ type WAL struct {
 	lastEntryIndex uint64   // index of the last entry saved to the wal
 	encoder        *encoder // encoder to encode records
 
-	files []*os.File // the locked files the WAL holds (the name is increasing)
-	st    *StreamFile
+	files       []*os.File // the locked files the WAL holds (the name is increasing)
+	st          *StreamFile
+	segmentSize int64 // the size a segment is cut at
 }
 
 // Create creates a WAL ready for appending records. The given metadata is
 // recorded at the head of each WAL file, and can be retrieved with ReadAll.
 // If there already are some wal files, it will try to recover from them.
 func Create(dirpath string, metadata []byte) (*WAL, error) {
+	return CreateWithSegmentSize(dirpath, metadata, SegmentSizeBytes)
+}
+
+// CreateWithSegmentSize creates a WAL like Create, whose segments are cut at segmentSize bytes.
+// A non-positive segmentSize means SegmentSizeBytes.
+func CreateWithSegmentSize(dirpath string, metadata []byte, segmentSize int64) (*WAL, error) {
+	if segmentSize <= 0 {
+		segmentSize = SegmentSizeBytes
+	}
 	b, err := exists(dirpath)
 	if err != nil {
 		return nil, err
@@ -78,10 +88,10 @@ func Create(dirpath string, metadata []byte) (*WAL, error) {
 
 	if Exist(dirpath) {
 		// Recover
-		return recoverFromDir(dirpath, metadata)
+		return recoverFromDir(dirpath, metadata, segmentSize)
 	}
 
-	streamFile := newStreamFile(dirpath, SegmentSizeBytes)
+	streamFile := newStreamFile(dirpath, segmentSize)
 	f, err := streamFile.GetNewFile()
 	if err != nil {
 		ilog.Warn("failed to generate a new WAL temp file！", err)
@@ -89,9 +99,10 @@ func Create(dirpath string, metadata []byte) (*WAL, error) {
 	}
 
 	w := &WAL{
-		dir:      dirpath,
-		metadata: metadata,
-		st:       streamFile,
+		dir:         dirpath,
+		metadata:    metadata,
+		st:          streamFile,
+		segmentSize: segmentSize,
 	}
 
 	if w.dirFile, err = OpenDir(w.dir); err != nil {
@@ -229,7 +240,7 @@ func (w *WAL) SaveSingle(ent Entry) (uint64, error) {
 	if err != nil {
 		return 0, err
 	}
-	if curOff < SegmentSizeBytes {
+	if curOff < w.segmentSize {
 		return w.lastEntryIndex, nil
 	}
 
@@ -277,7 +288,7 @@ func (w *WAL) CleanDir() error {
 
 // Size return WAL used data size include current tmp file.
 func (w *WAL) Size() uint64 {
-	size := uint64(len(w.files)) * uint64(SegmentSizeBytes)
+	size := uint64(len(w.files)) * uint64(w.segmentSize)
 	return size
 }
 
@@ -291,12 +302,15 @@ func (w *WAL) HasDecoder() bool {
 
 }
 
-func recoverFromDir(dirpath string, metadata []byte) (*WAL, error) {
+func recoverFromDir(dirpath string, metadata []byte, segmentSize int64) (*WAL, error) {
 	ilog.Info("RecoverFromDir")
-	w, err := Open(dirpath)
+	w, err := openAtIndex(dirpath, segmentSize)
 	if err != nil {
 		return nil, err
 	}
+	if w.dirFile, err = OpenDir(w.dir); err != nil {
+		return nil, err
+	}
 
 	return w, err
 
@@ -309,7 +323,7 @@ func recoverFromDir(dirpath string, metadata []byte) (*WAL, error) {
 // the given snap. The WAL cannot be appended to before reading out all of its
 // previous records.
 func Open(dirpath string) (*WAL, error) {
-	w, err := openAtIndex(dirpath)
+	w, err := openAtIndex(dirpath, SegmentSizeBytes)
 	if err != nil {
 		return nil, err
 	}
@@ -322,10 +336,10 @@ func Open(dirpath string) (*WAL, error) {
 // OpenForRead only opens the wal files for read.
 // Write on a read only wal panics.
 func OpenForRead(dirpath string) (*WAL, error) {
-	return openAtIndex(dirpath)
+	return openAtIndex(dirpath, SegmentSizeBytes)
 }
 
-func openAtIndex(dirpath string) (*WAL, error) {
+func openAtIndex(dirpath string, segmentSize int64) (*WAL, error) {
 	names, err := readWALNames(dirpath)
 	if err != nil {
 		return nil, err
@@ -351,14 +365,15 @@ func openAtIndex(dirpath string) (*WAL, error) {
 
 	closer := func() error { return closeAll(rcs...) }
 
-	streamFile := newStreamFile(dirpath, SegmentSizeBytes)
+	streamFile := newStreamFile(dirpath, segmentSize)
 	// create a WAL ready for reading
 	w := &WAL{
-		dir:       dirpath,
-		decoder:   newDecoder(rs...),
-		readClose: closer,
-		files:     ls,
-		st:        streamFile,
+		dir:         dirpath,
+		decoder:     newDecoder(rs...),
+		readClose:   closer,
+		files:       ls,
+		st:          streamFile,
+		segmentSize: segmentSize,
 	}
 
 	return w, nil
@@ -587,7 +602,7 @@ func (w *WAL) Save(ents []Entry) (uint64, error) {
 	if err != nil {
 		return 0, err
 	}
-	if curOff < SegmentSizeBytes {
+	if curOff < w.segmentSize {
 		return w.lastEntryIndex, nil
 	}
 
@@ -348,4 +348,58 @@ func TestRemoveFiles(t *testing.T) {
 	if entries[1].Index != 3 {
 		t.Fatal("Entry Index miss match, should be 3, got: ", entries[1].Index)
 	}
-}
\ No newline at end of file
+}
+func TestSegmentRotation(t *testing.T) {
+	p, err := ioutil.TempDir(os.TempDir(), "waltest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(p)
+
+	w, err := CreateWithSegmentSize(p, []byte("somedata"), 256)
+	if err != nil {
+		t.Fatalf("err = %v, want nil", err)
+	}
+	defer w.Close()
+
+	var lib uint64
+	for i := 0; i < 40; i++ {
+		index, err := w.SaveSingle(Entry{Data: bytes.Repeat([]byte{byte(i)}, 32)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i == 25 {
+			lib = index
+		}
+	}
+	names, err := readWALNames(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) < 4 {
+		t.Fatalf("wal should be cut into segments, got %d files", len(names))
+	}
+
+	if err := w.RemoveFilesBefore(lib); err != nil {
+		t.Fatal(err)
+	}
+	pruned, err := readWALNames(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pruned) >= len(names) {
+		t.Fatalf("segments below lib should be removed, got %d files, had %d", len(pruned), len(names))
+	}
+	for _, name := range pruned {
+		if strings.HasSuffix(name, ".wal.tmp") {
+			continue
+		}
+		_, lastIndex, err := parseWALName(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if lastIndex < lib {
+			t.Errorf("segment %s is below lib %d", name, lib)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package ilog
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// formatFields appends the key/value pairs to msg in the form of `msg key1=value1 key2=value2`.
+// Values containing spaces, quotes or '=' are quoted.
+func formatFields(msg string, keysAndValues []interface{}) string {
+	var buf bytes.Buffer
+	buf.WriteString(msg)
+	for i := 0; i < len(keysAndValues); i += 2 {
+		buf.WriteString(" ")
+		buf.WriteString(fmt.Sprint(keysAndValues[i]))
+		buf.WriteString("=")
+		if i+1 >= len(keysAndValues) {
+			buf.WriteString("MISSING")
+			break
+		}
+		value := fmt.Sprint(keysAndValues[i+1])
+		if value == "" || strings.ContainsAny(value, " =\"") {
+			value = strconv.Quote(value)
+		}
+		buf.WriteString(value)
+	}
+	return buf.String()
+}
+
+// Debugw generates a debug-level log with key/value pairs.
+func (logger *Logger) Debugw(msg string, keysAndValues ...interface{}) {
+	if LevelDebug < logger.lowestLevel {
+		return
+	}
+	logger.genMsg(LevelDebug, formatFields(msg, keysAndValues))
+}
+
+// Infow generates a info-level log with key/value pairs.
+func (logger *Logger) Infow(msg string, keysAndValues ...interface{}) {
+	if LevelInfo < logger.lowestLevel {
+		return
+	}
+	logger.genMsg(LevelInfo, formatFields(msg, keysAndValues))
+}
+
+// Warnw generates a warn-level log with key/value pairs.
+func (logger *Logger) Warnw(msg string, keysAndValues ...interface{}) {
+	if LevelWarn < logger.lowestLevel {
+		return
+	}
+	logger.genMsg(LevelWarn, formatFields(msg, keysAndValues))
+}
+
+// Errorw generates a error-level log with key/value pairs.
+func (logger *Logger) Errorw(msg string, keysAndValues ...interface{}) {
+	if LevelError < logger.lowestLevel {
+		return
+	}
+	logger.genMsg(LevelError, formatFields(msg, keysAndValues))
+}
+
+// Debugw generates a debug-level log with key/value pairs.
+func Debugw(msg string, keysAndValues ...interface{}) {
+	defaultLogger.Debugw(msg, keysAndValues...)
+}
+
+// Infow generates a info-level log with key/value pairs.
+func Infow(msg string, keysAndValues ...interface{}) {
+	defaultLogger.Infow(msg, keysAndValues...)
+}
+
+// Warnw generates a warn-level log with key/value pairs.
+func Warnw(msg string, keysAndValues ...interface{}) {
+	defaultLogger.Warnw(msg, keysAndValues...)
+}
+
+// Errorw generates a error-level log with key/value pairs.
+func Errorw(msg string, keysAndValues ...interface{}) {
+	defaultLogger.Errorw(msg, keysAndValues...)
+}
@@ -0,0 +1,49 @@
+package ilog
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type memWriter struct {
+	mu   sync.Mutex
+	msgs []string
+}
+
+func (mw *memWriter) Init() error      { return nil }
+func (mw *memWriter) SetLevel(l Level) {}
+func (mw *memWriter) GetLevel() Level  { return LevelDebug }
+func (mw *memWriter) Flush() error     { return nil }
+func (mw *memWriter) Close() error     { return nil }
+func (mw *memWriter) Write(msg string, level Level) error {
+	mw.mu.Lock()
+	mw.msgs = append(mw.msgs, msg)
+	mw.mu.Unlock()
+	return nil
+}
+
+func TestFormatFields(t *testing.T) {
+	assert.Equal(t, "Rec block num=10 witness=abc", formatFields("Rec block", []interface{}{"num", 10, "witness", "abc"}))
+	assert.Equal(t, `msg a="x y" b=""`, formatFields("msg", []interface{}{"a", "x y", "b", ""}))
+	assert.Equal(t, "msg a=MISSING", formatFields("msg", []interface{}{"a"}))
+}
+
+func TestInfow(t *testing.T) {
+	logger := New()
+	mw := &memWriter{}
+	err := logger.AddWriter(mw)
+	assert.Nil(t, err)
+	logger.Start()
+	defer logger.Stop()
+
+	logger.Infow("Gen block", "num", 3, "txs", 5)
+	logger.Flush()
+
+	mw.mu.Lock()
+	defer mw.mu.Unlock()
+	assert.Equal(t, 1, len(mw.msgs))
+	assert.True(t, strings.HasSuffix(mw.msgs[0], "Gen block num=3 txs=5\n"))
+}
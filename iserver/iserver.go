@@ -10,6 +10,8 @@ import (
 	"github.com/iost-official/go-iost/ilog"
 	"github.com/iost-official/go-iost/p2p"
 	"github.com/iost-official/go-iost/rpc"
+	"github.com/iost-official/go-iost/vm"
+	"github.com/iost-official/go-iost/vm/host"
 )
 
 // Service defines APIs of resident goroutines.
@@ -31,6 +33,24 @@ type IServer struct {
 // New returns a iserver application
 func New(conf *common.Config) *IServer {
 	tx.ChainID = conf.P2P.ChainID
+	if conf.ACC != nil {
+		signHash, err := common.ParseSignHash(conf.ACC.SignHash)
+		if err != nil {
+			ilog.Fatalf("invalid sign hash %q, err=%v", conf.ACC.SignHash, err)
+		}
+		common.DefaultSignHash = signHash
+	}
+	if conf.Debug != nil {
+		vm.CallTraceEnabled = conf.Debug.CallTrace
+	}
+	if conf.Fork != nil {
+		common.Forks = *conf.Fork
+	}
+	if conf.VM != nil && conf.VM.CostModel != nil {
+		if err := host.LoadCostModel(host.NewCostModel(conf.VM.CostModel)); err != nil {
+			ilog.Fatalf("load cost model failed. err=%v", err)
+		}
+	}
 
 	bv, err := global.New(conf)
 	if err != nil {
@@ -46,3 +46,8 @@ func NewGauge(name string, labels []string) Gauge {
 func NewSummary(name string, labels []string) Summary {
 	return defaultClient.NewSummary(name, labels)
 }
+
+// NewHistogram returns a histogram-type metrics with the given buckets.
+func NewHistogram(name string, labels []string, buckets []float64) Histogram {
+	return defaultClient.NewHistogram(name, labels, buckets)
+}
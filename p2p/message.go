@@ -101,6 +101,15 @@ const (
 	reservedCompressionFlag = 1
 )
 
+// codecs of the block messages
+const (
+	NoCompression     = ""
+	SnappyCompression = "snappy"
+)
+
+// ErrUnknownCompression is returned when the block compression codec isn't supported.
+var ErrUnknownCompression = errors.New("unknown block compression")
+
 var (
 	errMessageTooShort   = errors.New("message too short")
 	errUnmatchDataLength = errors.New("unmatch data length")
@@ -152,6 +161,22 @@ func (m *p2pMessage) data() ([]byte, error) {
 	return data, err
 }
 
+// compressionFlag returns the reserved flag of a message sent with the block compression codec.
+// Only block messages are compressed, and the receiver decompresses by the flag.
+func compressionFlag(codec string, typ MessageType) (uint32, error) {
+	switch codec {
+	case NoCompression:
+		return defaultReservedFlag, nil
+	case SnappyCompression:
+		if typ == NewBlock || typ == SyncBlockResponse {
+			return reservedCompressionFlag, nil
+		}
+		return defaultReservedFlag, nil
+	default:
+		return defaultReservedFlag, ErrUnknownCompression
+	}
+}
+
 func (m *p2pMessage) needDedup() bool {
 	return m.messageType() == PublishTx || m.messageType() == NewBlockHash
 }
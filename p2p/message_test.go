@@ -5,6 +5,10 @@ import (
 	"hash/crc32"
 	"testing"
 
+	"github.com/iost-official/go-iost/account"
+	"github.com/iost-official/go-iost/core/block"
+	"github.com/iost-official/go-iost/core/tx"
+	"github.com/iost-official/go-iost/crypto"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -96,3 +100,57 @@ func TestP2PMessageParse(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, m, newM)
 }
+
+func TestCompressionFlag(t *testing.T) {
+	flag, err := compressionFlag(NoCompression, NewBlock)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(defaultReservedFlag), flag)
+
+	flag, err = compressionFlag(SnappyCompression, NewBlock)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(reservedCompressionFlag), flag)
+
+	flag, err = compressionFlag(SnappyCompression, SyncBlockResponse)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(reservedCompressionFlag), flag)
+
+	flag, err = compressionFlag(SnappyCompression, PublishTx)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(defaultReservedFlag), flag)
+
+	_, err = compressionFlag("gzip", NewBlock)
+	assert.Equal(t, ErrUnknownCompression, err)
+}
+
+func TestCompressedBlockMessage(t *testing.T) {
+	blk := &block.Block{
+		Head: &block.BlockHead{Number: 10, Witness: "witness", Info: []byte("{}")},
+	}
+	for i := 0; i < 20; i++ {
+		blk.Txs = append(blk.Txs, tx.NewTx([]*tx.Action{{Contract: "token.iost", ActionName: "transfer", Data: `["iost","a","b","1",""]`}}, nil, 100000, 100, int64(i), 0, 0))
+		blk.Receipts = append(blk.Receipts, tx.NewTxReceipt(blk.Txs[i].Hash()))
+	}
+	blk.Head.TxMerkleHash = blk.CalculateTxMerkleHash()
+	blk.CalculateHeadHash()
+	acc, err := account.NewKeyPair(nil, crypto.Ed25519)
+	assert.Nil(t, err)
+	blk.Sign = acc.Sign(blk.HeadHash())
+	raw, err := blk.Encode()
+	assert.Nil(t, err)
+
+	flag, err := compressionFlag(SnappyCompression, NewBlock)
+	assert.Nil(t, err)
+	m := newP2PMessage(testChainID, NewBlock, testVersion, flag, raw)
+	assert.True(t, m.isCompressed())
+	assert.True(t, len(m.rawData()) < len(raw))
+
+	newM, err := parseP2PMessage(m.content())
+	assert.Nil(t, err)
+	data, err := newM.data()
+	assert.Nil(t, err)
+
+	newBlk := &block.Block{}
+	assert.Nil(t, newBlk.Decode(data))
+	assert.Equal(t, blk.HeadHash(), newBlk.HeadHash())
+	assert.Equal(t, len(blk.Txs), len(newBlk.Txs))
+}
@@ -9,6 +9,7 @@ import (
 	p2p "github.com/iost-official/go-iost/p2p"
 	go_libp2p_peer "github.com/libp2p/go-libp2p-peer"
 	reflect "reflect"
+	time "time"
 )
 
 // MockService is a mock of Service interface
@@ -93,6 +94,16 @@ func (mr *MockServiceMockRecorder) ID() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ID", reflect.TypeOf((*MockService)(nil).ID))
 }
 
+// BanPeer mocks base method
+func (m *MockService) BanPeer(arg0 string, arg1 time.Duration) {
+	m.ctrl.Call(m, "BanPeer", arg0, arg1)
+}
+
+// BanPeer indicates an expected call of BanPeer
+func (mr *MockServiceMockRecorder) BanPeer(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BanPeer", reflect.TypeOf((*MockService)(nil).BanPeer), arg0, arg1)
+}
+
 // PutPeerToBlack mocks base method
 func (m *MockService) PutPeerToBlack(arg0 string) {
 	m.ctrl.Call(m, "PutPeerToBlack", arg0)
@@ -7,6 +7,7 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/iost-official/go-iost/common"
 	"github.com/iost-official/go-iost/ilog"
@@ -33,6 +34,7 @@ const (
 // errors
 var (
 	ErrPortUnavailable = errors.New("port is unavailable")
+	ErrNoMessageType   = errors.New("no message type to subscribe")
 )
 
 // Service defines all the API of p2p package.
@@ -43,6 +45,7 @@ type Service interface {
 	ID() string
 	ConnectBPs([]string)
 	PutPeerToBlack(string)
+	BanPeer(string, time.Duration)
 
 	Broadcast([]byte, MessageType, MessagePriority)
 	SendToPeer(PeerID, []byte, MessageType, MessagePriority)
@@ -52,6 +55,19 @@ type Service interface {
 	GetAllNeighbors() []*Peer
 }
 
+// Subscribe registers a message channel of the given types on the service.
+// Unlike Register, it returns an error instead of a nil channel.
+func Subscribe(s Service, id string, mTyps ...MessageType) (chan IncomingMessage, error) {
+	if len(mTyps) == 0 {
+		return nil, ErrNoMessageType
+	}
+	c := s.Register(id, mTyps...)
+	if c == nil {
+		return nil, fmt.Errorf("failed to register %v for message types %v", id, mTyps)
+	}
+	return c, nil
+}
+
 // NetService is the implementation of Service interface.
 type NetService struct {
 	*PeerManager
@@ -69,6 +85,11 @@ func NewNetService(config *common.P2PConfig) (*NetService, error) {
 		config: config,
 	}
 
+	if _, err := compressionFlag(config.BlockCompression, NewBlock); err != nil {
+		ilog.Errorf("invalid block compression %q, err=%v", config.BlockCompression, err)
+		return nil, err
+	}
+
 	if err := os.MkdirAll(config.DataPath, 0755); config.DataPath != "" && err != nil {
 		ilog.Errorf("failed to create p2p datapath, err=%v, path=%v", err, config.DataPath)
 		return nil, err
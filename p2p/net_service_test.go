@@ -0,0 +1,37 @@
+package p2p
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubService struct {
+	Service
+	registered map[MessageType]string
+	ret        chan IncomingMessage
+}
+
+func (s *stubService) Register(id string, mTyps ...MessageType) chan IncomingMessage {
+	for _, typ := range mTyps {
+		s.registered[typ] = id
+	}
+	return s.ret
+}
+
+func TestSubscribe(t *testing.T) {
+	s := &stubService{registered: make(map[MessageType]string), ret: make(chan IncomingMessage)}
+
+	c, err := Subscribe(s, "test")
+	assert.Equal(t, ErrNoMessageType, err)
+	assert.Nil(t, c)
+
+	c, err = Subscribe(s, "test", PublishTx, NewBlock)
+	assert.Nil(t, err)
+	assert.Equal(t, s.ret, c)
+	assert.Equal(t, map[MessageType]string{PublishTx: "test", NewBlock: "test"}, s.registered)
+
+	s.ret = nil
+	_, err = Subscribe(s, "test", SyncHeight)
+	assert.NotNil(t, err)
+}
@@ -86,6 +86,7 @@ type PeerManager struct {
 
 	blackPIDs  map[string]bool
 	blackIPs   map[string]bool
+	bannedPIDs map[string]time.Time
 	blackMutex sync.RWMutex
 
 	retryTimes map[string]int
@@ -108,6 +109,7 @@ func NewPeerManager(host host.Host, config *common.P2PConfig) *PeerManager {
 		wg:            new(sync.WaitGroup),
 		blackPIDs:     make(map[string]bool),
 		blackIPs:      make(map[string]bool),
+		bannedPIDs:    make(map[string]time.Time),
 		retryTimes:    make(map[string]int),
 	}
 	if config.InboundConn <= 0 {
@@ -566,7 +568,8 @@ func (pm *PeerManager) dnsResolve(peerID peer.ID, addr multiaddr.Multiaddr) erro
 
 // Broadcast sends message to all the neighbors.
 func (pm *PeerManager) Broadcast(data []byte, typ MessageType, mp MessagePriority) {
-	msg := newP2PMessage(pm.config.ChainID, typ, pm.config.Version, defaultReservedFlag, data)
+	flag, _ := compressionFlag(pm.config.BlockCompression, typ)
+	msg := newP2PMessage(pm.config.ChainID, typ, pm.config.Version, flag, data)
 
 	wg := new(sync.WaitGroup)
 	for _, p := range pm.GetAllNeighbors() {
@@ -581,7 +584,8 @@ func (pm *PeerManager) Broadcast(data []byte, typ MessageType, mp MessagePriorit
 
 // SendToPeer sends message to the specified peer.
 func (pm *PeerManager) SendToPeer(peerID peer.ID, data []byte, typ MessageType, mp MessagePriority) {
-	msg := newP2PMessage(pm.config.ChainID, typ, pm.config.Version, defaultReservedFlag, data)
+	flag, _ := compressionFlag(pm.config.BlockCompression, typ)
+	msg := newP2PMessage(pm.config.ChainID, typ, pm.config.Version, flag, data)
 
 	peer := pm.GetNeighbor(peerID)
 	if peer != nil {
@@ -857,6 +861,34 @@ func (pm *PeerManager) PutPIDToBlack(pid peer.ID) {
 	}
 }
 
+// BanPeer disconnects the peer and refuses its PID until the ban expires after d.
+// Unlike PutPeerToBlack, it doesn't touch the peer's IP.
+func (pm *PeerManager) BanPeer(id string, d time.Duration) {
+	pid, err := peer.IDB58Decode(id)
+	if err != nil {
+		ilog.Warnf("decode peerID failed. err=%v, id=%v", err, id)
+		return
+	}
+	pm.blackMutex.Lock()
+	pm.bannedPIDs[pid.Pretty()] = time.Now().Add(d)
+	pm.blackMutex.Unlock()
+	pm.RemoveNeighbor(pid)
+}
+
+// isBanned returns whether the pid is banned and deletes the ban once expired,
+// it should be called with blackMutex locked.
+func (pm *PeerManager) isBanned(pid string) bool {
+	expire, ok := pm.bannedPIDs[pid]
+	if !ok {
+		return false
+	}
+	if !time.Now().Before(expire) {
+		delete(pm.bannedPIDs, pid)
+		return false
+	}
+	return true
+}
+
 // PutIPToBlack puts the ip to black list.
 func (pm *PeerManager) PutIPToBlack(ip string) {
 	pm.blackMutex.Lock()
@@ -866,10 +898,10 @@ func (pm *PeerManager) PutIPToBlack(ip string) {
 
 func (pm *PeerManager) isStreamBlack(s libnet.Stream) bool {
 	pid := s.Conn().RemotePeer()
-	pm.blackMutex.RLock()
-	defer pm.blackMutex.RUnlock()
+	pm.blackMutex.Lock()
+	defer pm.blackMutex.Unlock()
 
-	if pm.blackPIDs[pid.Pretty()] {
+	if pm.blackPIDs[pid.Pretty()] || pm.isBanned(pid.Pretty()) {
 		return true
 	}
 	ma := s.Conn().RemoteMultiaddr().String()
@@ -878,9 +910,9 @@ func (pm *PeerManager) isStreamBlack(s libnet.Stream) bool {
 }
 
 func (pm *PeerManager) isPIDBlack(pid peer.ID) bool {
-	pm.blackMutex.RLock()
-	defer pm.blackMutex.RUnlock()
-	return pm.blackPIDs[pid.Pretty()]
+	pm.blackMutex.Lock()
+	defer pm.blackMutex.Unlock()
+	return pm.blackPIDs[pid.Pretty()] || pm.isBanned(pid.Pretty())
 }
 
 func (pm *PeerManager) recordDialFail(pid peer.ID) {
@@ -0,0 +1,32 @@
+package p2p
+
+import (
+	"testing"
+	"time"
+
+	peer "github.com/libp2p/go-libp2p-peer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBanPeer(t *testing.T) {
+	pm := &PeerManager{
+		neighbors:     make(map[peer.ID]*Peer),
+		neighborCount: make(map[connDirection]int),
+		blackPIDs:     make(map[string]bool),
+		blackIPs:      make(map[string]bool),
+		bannedPIDs:    make(map[string]time.Time),
+	}
+	id := "QmYyQSo1c1Ym7orWxLYvCrM2EmxFTANf8wXmmE7DWjhx5N"
+	pid, err := peer.IDB58Decode(id)
+	assert.Nil(t, err)
+
+	pm.BanPeer(id, time.Hour)
+	assert.True(t, pm.isPIDBlack(pid))
+
+	pm.BanPeer(id, -time.Second)
+	assert.False(t, pm.isPIDBlack(pid))
+	assert.Empty(t, pm.bannedPIDs)
+
+	pm.BanPeer("invalid id", time.Hour)
+	assert.Empty(t, pm.bannedPIDs)
+}
@@ -465,10 +465,49 @@ func (as *APIService) tryTransaction(t *tx.Tx) (*tx.TxReceipt, error) {
 	return v.Try(blkHead, stateDB, t, cverifier.TxExecTimeLimit)
 }
 
+// checkBadTx rejects the tx which is invalid by itself, has a bad memo or whose publisher can't afford its gas limit.
+func (as *APIService) checkBadTx(t *tx.Tx) error {
+	err := tx.ValidateTx(t, time.Now().UnixNano())
+	if err != nil {
+		return err
+	}
+	if err := tx.CheckMemo(t); err != nil {
+		return err
+	}
+	if limit := as.MaxTxGasLimit(); limit > 0 && t.GasLimit > limit {
+		return fmt.Errorf("gas limit %v exceeds the max gas limit per tx %v", float64(t.GasLimit)/100, float64(limit)/100)
+	}
+	headBlock := as.bc.Head()
+	dbVisitor, err := as.getStateDBVisitorByHash(headBlock.HeadHash())
+	if err != nil {
+		ilog.Errorf("[internal error] checkBadTx error: %v", err)
+		return err
+	}
+	currentGas := dbVisitor.TotalGasAtTime(t.Publisher, headBlock.Head.Time)
+	return vm.CheckTxGasLimitValid(t, currentGas, dbVisitor)
+}
+
+// MaxTxGasLimit returns the max gas limit of the txs accepted by this node, 0 if there is no cap besides the protocol's.
+func (as *APIService) MaxTxGasLimit() int64 {
+	if conf := as.bv.Config(); conf != nil && conf.TxPool != nil {
+		return conf.TxPool.MaxTxGasLimit
+	}
+	return 0
+}
+
+// SimulateTx runs the tx on a fork of the head state and returns its receipt, including the receipts of the actions.
+// The state changes are discarded and the tx is neither added to the tx pool nor broadcast.
+func (as *APIService) SimulateTx(t *tx.Tx) (*tx.TxReceipt, error) {
+	if err := as.checkBadTx(t); err != nil {
+		return nil, err
+	}
+	return as.tryTransaction(t)
+}
+
 // SendTransaction sends a transaction to iserver.
 func (as *APIService) SendTransaction(ctx context.Context, req *rpcpb.TransactionRequest) (*rpcpb.SendTransactionResponse, error) {
 	t := toCoreTx(req)
-	err := checkBadTx(t)
+	err := as.checkBadTx(t)
 	if err != nil {
 		return nil, err
 	}
@@ -482,17 +521,6 @@ func (as *APIService) SendTransaction(ctx context.Context, req *rpcpb.Transactio
 		}
 		ret.PreTxReceipt = toPbTxReceipt(tr)
 	}
-	headBlock := as.bc.Head()
-	dbVisitor, err := as.getStateDBVisitorByHash(headBlock.HeadHash())
-	if err != nil {
-		ilog.Errorf("[internal error] SendTransaction error: %v", err)
-		return nil, err
-	}
-	currentGas := dbVisitor.TotalGasAtTime(t.Publisher, headBlock.Head.Time)
-	err = vm.CheckTxGasLimitValid(t, currentGas, dbVisitor)
-	if err != nil {
-		return nil, err
-	}
 	err = as.txpool.AddTx(t)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,116 @@
+package rpc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/iost-official/go-iost/account"
+	"github.com/iost-official/go-iost/common"
+	"github.com/iost-official/go-iost/core/block"
+	"github.com/iost-official/go-iost/core/blockcache"
+	"github.com/iost-official/go-iost/core/mocks"
+	"github.com/iost-official/go-iost/core/tx"
+	"github.com/iost-official/go-iost/crypto"
+	"github.com/iost-official/go-iost/verifier"
+	"github.com/iost-official/go-iost/vm/database"
+	"github.com/iost-official/go-iost/vm/native"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// headCache is a BlockCache which only knows its head.
+type headCache struct {
+	blockcache.BlockCache
+	head *blockcache.BlockCacheNode
+}
+
+func (c *headCache) Head() *blockcache.BlockCacheNode {
+	return c.head
+}
+
+func TestSimulateTx(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	Convey("Test of SimulateTx", t, func() {
+		s := verifier.NewSimulator()
+		defer s.Clear()
+
+		kp, err := account.NewKeyPair(nil, crypto.Ed25519)
+		So(err, ShouldBeNil)
+		for _, id := range []string{"user0", "user1"} {
+			s.SetAccount(account.NewInitAccount(id, kp.ReadablePubkey(), kp.ReadablePubkey()))
+			s.SetGas(id, 100000000)
+			s.SetRAM(id, 10000)
+		}
+		s.SetContract(native.TokenABI())
+		r, err := s.Call("token.iost", "create", `["iost", "user0", 1000000, {}]`, "user0", kp)
+		So(err, ShouldBeNil)
+		So(r.Status.Code, ShouldEqual, tx.Success)
+		r, err = s.Call("token.iost", "issue", `["iost", "user0", "1000"]`, "user0", kp)
+		So(err, ShouldBeNil)
+		So(r.Status.Code, ShouldEqual, tx.Success)
+		s.Visitor.Commit()
+
+		head := blockcache.NewBCN(nil, &block.Block{Head: &block.BlockHead{Number: 1, Time: s.Head.Time}})
+		s.Mvcc.Commit(string(head.HeadHash()))
+
+		bv := core_mock.NewMockBaseVariable(mockController)
+		bv.EXPECT().StateDB().Return(s.Mvcc).AnyTimes()
+		conf := &common.Config{RPC: &common.RPCConfig{}, TxPool: &common.TxPoolConfig{}}
+		bv.EXPECT().Config().Return(conf).AnyTimes()
+		as := &APIService{bc: &headCache{head: head}, bv: bv}
+
+		Convey("max gas limit per tx", func() {
+			trx, err := BuildTransferTx("user0", "user1", "iost", "10", kp, 1000000, 100)
+			So(err, ShouldBeNil)
+			So(as.MaxTxGasLimit(), ShouldEqual, 0)
+			So(as.checkBadTx(trx), ShouldBeNil)
+
+			conf.TxPool.MaxTxGasLimit = 1000000
+			So(as.MaxTxGasLimit(), ShouldEqual, 1000000)
+			So(as.checkBadTx(trx), ShouldBeNil)
+
+			conf.TxPool.MaxTxGasLimit = 999999
+			err = as.checkBadTx(trx)
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "exceeds the max gas limit per tx")
+		})
+
+		Convey("memo", func() {
+			trx, err := BuildTransferTx("user0", "user1", "iost", "10", kp, 1000000, 100)
+			So(err, ShouldBeNil)
+			trx.SetMemo("order 42")
+			So(trx.ResignWith(kp), ShouldBeNil)
+			So(as.checkBadTx(trx), ShouldBeNil)
+
+			trx.SetMemo(strings.Repeat("m", tx.MaxMemoLen+1))
+			So(trx.ResignWith(kp), ShouldBeNil)
+			So(as.checkBadTx(trx).Error(), ShouldContainSubstring, "memo too long")
+		})
+
+		Convey("simulate", func() {
+			trx, err := BuildTransferTx("user0", "user1", "iost", "10", kp, 1000000, 100)
+			So(err, ShouldBeNil)
+			tr, err := as.SimulateTx(trx)
+			So(err, ShouldBeNil)
+			So(tr.Status.Code, ShouldEqual, tx.Success)
+			So(tr.TxHash, ShouldResemble, trx.Hash())
+			found := false
+			for _, receipt := range tr.Receipts {
+				if receipt.FuncName == "token.iost/transfer" {
+					So(receipt.Content, ShouldEqual, `["iost","user0","user1","10",""]`)
+					found = true
+				}
+			}
+			So(found, ShouldBeTrue)
+
+			vi := database.NewVisitor(0, s.Mvcc)
+			So(vi.TokenBalance("iost", "user1"), ShouldEqual, 0)
+
+			trx.Expiration = trx.Time
+			_, err = as.SimulateTx(trx)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
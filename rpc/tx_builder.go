@@ -0,0 +1,40 @@
+package rpc
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/iost-official/go-iost/account"
+	"github.com/iost-official/go-iost/core/contract"
+	"github.com/iost-official/go-iost/core/tx"
+)
+
+// TransferTxExpiration is the expiration of the txs built by BuildTransferTx.
+var TransferTxExpiration = 90 * time.Second
+
+// BuildTransferTx builds a token.iost transfer tx published and signed by kp.
+func BuildTransferTx(from, to, token, amount string, kp *account.KeyPair, gasLimit, gasPrice int64) (*tx.Tx, error) {
+	if kp == nil {
+		return nil, errors.New("keypair is nil")
+	}
+	if err := tx.CheckAmount(amount, token); err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal([]string{token, from, to, amount, ""})
+	if err != nil {
+		return nil, err
+	}
+	actions := []*tx.Action{{
+		Contract:   "token.iost",
+		ActionName: "transfer",
+		Data:       string(data),
+	}}
+	t := tx.NewTx(actions, nil, gasLimit, gasPrice, 0, 0, tx.ChainID)
+	t.Expiration = t.Time + int64(TransferTxExpiration)
+	if err := tx.CheckExpirationWindow(t); err != nil {
+		return nil, err
+	}
+	t.AmountLimit = []*contract.Amount{{Token: token, Val: amount}}
+	return tx.SignTx(t, from, []*account.KeyPair{kp})
+}
@@ -0,0 +1,28 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/iost-official/go-iost/account"
+	"github.com/iost-official/go-iost/crypto"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestBuildTransferTx(t *testing.T) {
+	Convey("Test of BuildTransferTx", t, func() {
+		kp, err := account.NewKeyPair(nil, crypto.Ed25519)
+		So(err, ShouldBeNil)
+
+		trx, err := BuildTransferTx("alice", "bob", "iost", "12.5", kp, 1000000, 100)
+		So(err, ShouldBeNil)
+		So(trx.VerifySelf(), ShouldBeNil)
+		So(trx.Publisher, ShouldEqual, "alice")
+		So(trx.Actions[0].Data, ShouldEqual, `["iost","alice","bob","12.5",""]`)
+		So(trx.AmountLimit[0].Val, ShouldEqual, "12.5")
+
+		for _, amount := range []string{"", "-1", "1.2.3", "abc", "1.123456789"} {
+			_, err = BuildTransferTx("alice", "bob", "iost", amount, kp, 1000000, 100)
+			So(err, ShouldNotBeNil)
+		}
+	})
+}
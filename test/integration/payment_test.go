@@ -0,0 +1,135 @@
+package integration
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/iost-official/go-iost/common"
+	"github.com/iost-official/go-iost/core/contract"
+	"github.com/iost-official/go-iost/core/tx"
+	txpb "github.com/iost-official/go-iost/core/tx/pb"
+	. "github.com/iost-official/go-iost/verifier"
+	"github.com/iost-official/go-iost/vm/native"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func paymentSetup(t *testing.T, payment contract.PaymentCode) *Simulator {
+	s := NewSimulator()
+	for _, acc := range []*TestAccount{acc0, acc1} {
+		s.SetAccount(acc.ToAccount())
+		s.SetGas(acc.ID, 1e8)
+		s.SetRAM(acc.ID, 1000)
+	}
+	c := native.TokenABI()
+	c.ABI("transfer").Payment = int32(payment)
+	s.SetContract(c)
+	if err := createToken(t, s, acc0); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func transferArgs(amount string) string {
+	return fmt.Sprintf(`["iost", "%v", "%v", "%v", ""]`, acc0.ID, acc1.ID, amount)
+}
+
+func TestPayment(t *testing.T) {
+	Convey("caller pays", t, func() {
+		s := paymentSetup(t, contract.SelfPay)
+		defer s.Clear()
+		s.SetGas("token.iost", 1e8)
+
+		r, err := s.Call("token.iost", "transfer", transferArgs("10"), acc0.ID, acc0.KeyPair)
+		So(err, ShouldBeNil)
+		So(r.Status.Code, ShouldEqual, tx.Success)
+		So(s.Visitor.TokenBalance("iost", acc1.ID), ShouldEqual, 10*1e8)
+		So(s.GetGas("token.iost"), ShouldEqual, 1e8)
+		So(s.GetGas(acc0.ID), ShouldBeLessThan, 1e8)
+	})
+
+	Convey("contract pays", t, func() {
+		s := paymentSetup(t, contract.ContractPay)
+		defer s.Clear()
+		s.SetGas("token.iost", 1e8)
+		gas := s.GetGas(acc0.ID)
+
+		r, err := s.Call("token.iost", "transfer", transferArgs("10"), acc0.ID, acc0.KeyPair)
+		So(err, ShouldBeNil)
+		So(r.Status.Code, ShouldEqual, tx.Success)
+		So(s.Visitor.TokenBalance("iost", acc1.ID), ShouldEqual, 10*1e8)
+		So(s.GetGas("token.iost"), ShouldBeLessThan, 1e8)
+
+		r, err = s.Call("token.iost", "issue", `["iost", "user_0", "10"]`, acc0.ID, acc0.KeyPair)
+		So(err, ShouldBeNil)
+		So(r.Status.Code, ShouldEqual, tx.Success)
+		So(s.GetGas(acc0.ID), ShouldBeLessThan, gas)
+
+		Convey("contract gas not enough", func() {
+			s.SetGas("token.iost", 0)
+			r, err := s.Call("token.iost", "transfer", transferArgs("10"), acc0.ID, acc0.KeyPair)
+			So(err, ShouldBeNil)
+			So(r.Status.Code, ShouldEqual, tx.ErrorBalanceNotEnough)
+			So(r.Status.Message, ShouldEqual, "contract gas not enough")
+			So(s.Visitor.TokenBalance("iost", acc1.ID), ShouldEqual, 10*1e8)
+		})
+	})
+}
+
+func TestGasByContract(t *testing.T) {
+	Convey("gas by contract", t, func() {
+		s := paymentSetup(t, contract.SelfPay)
+		defer s.Clear()
+		defer func(f common.ForkConfig) { common.Forks = f }(common.Forks)
+		height := int64(0)
+		common.Forks.ContractGasReceipt = &height
+
+		trx := tx.NewTx([]*tx.Action{
+			{Contract: "token.iost", ActionName: "transfer", Data: transferArgs("10")},
+			{Contract: "system.iost", ActionName: "receipt", Data: `["gas by contract"]`},
+		}, nil, s.GasLimit, 100, s.Head.Time+10000000, 0, 0)
+		trx.Time = s.Head.Time
+		trx.AmountLimit = append(trx.AmountLimit, &contract.Amount{Token: "*", Val: "unlimited"})
+
+		r, err := s.CallTx(trx, acc0.ID, acc0.KeyPair)
+		So(err, ShouldBeNil)
+		So(r.Status.Code, ShouldEqual, tx.Success)
+		raw := &txpb.TxReceipt{}
+		So(proto.Unmarshal(r.Encode(), raw), ShouldBeNil)
+		gas := raw.GasByContract()
+		So(len(gas), ShouldEqual, 2)
+		So(gas["token.iost"], ShouldBeGreaterThan, 0)
+		So(gas["system.iost"], ShouldBeGreaterThan, 0)
+		So(gas["token.iost"]+gas["system.iost"], ShouldBeLessThanOrEqualTo, r.GasUsage)
+	})
+}
+
+func TestGasTip(t *testing.T) {
+	Convey("the tip is charged along with the gas ratio", t, func() {
+		s := paymentSetup(t, contract.SelfPay)
+		defer s.Clear()
+
+		newTransfer := func(tip int64) *tx.Tx {
+			trx := tx.NewTx([]*tx.Action{{
+				Contract:   "token.iost",
+				ActionName: "transfer",
+				Data:       transferArgs("10"),
+			}}, nil, s.GasLimit, 100, s.Head.Time+10000000, 0, 0)
+			trx.Time = s.Head.Time
+			trx.AmountLimit = append(trx.AmountLimit, &contract.Amount{Token: "*", Val: "unlimited"})
+			trx.SetTip(tip)
+			return trx
+		}
+
+		r, err := s.CallTx(newTransfer(0), acc0.ID, acc0.KeyPair)
+		So(err, ShouldBeNil)
+		So(r.Status.Code, ShouldEqual, tx.Success)
+		paid := r.GasUsage
+
+		r, err = s.CallTx(newTransfer(100), acc0.ID, acc0.KeyPair)
+		So(err, ShouldBeNil)
+		So(r.Status.Code, ShouldEqual, tx.Success)
+		So(r.GasUsage, ShouldBeGreaterThanOrEqualTo, 2*paid)
+		So(r.Receipts[len(r.Receipts)-1].FuncName, ShouldEqual, "gas_tip")
+	})
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/iost-official/go-iost/core/tx"
 	"github.com/iost-official/go-iost/ilog"
 	. "github.com/iost-official/go-iost/verifier"
+	"github.com/iost-official/go-iost/vm/host"
 )
 
 func Test_callWithAuth(t *testing.T) {
@@ -288,10 +289,13 @@ func Test_StackHeight(t *testing.T) {
 		So(r.Status.Code, ShouldEqual, tx.Success)
 
 		Convey("test of out of stack height", func() {
+			defer func(f common.ForkConfig) { common.Forks = f }(common.Forks)
+			height := int64(0)
+			common.Forks.CallDepthLimit = &height
 			r, err := s.Call(cname0, "sh0", fmt.Sprintf(`["%v"]`, cname1), acc0.ID, acc0.KeyPair)
 			s.Visitor.Commit()
 			So(err, ShouldBeNil)
-			So(r.Status.Message, ShouldContainSubstring, "stack height exceed.")
+			So(r.Status.Message, ShouldContainSubstring, host.ErrCallDepthExceeded.Error())
 		})
 	})
 }
@@ -1,12 +1,17 @@
 package native
 
 import (
+	"fmt"
 	"io/ioutil"
+	"sort"
+	"strings"
 	"testing"
 
 	"time"
 
+	"github.com/iost-official/go-iost/common"
 	"github.com/iost-official/go-iost/core/contract"
+	"github.com/iost-official/go-iost/core/tx"
 	"github.com/iost-official/go-iost/vm"
 	"github.com/iost-official/go-iost/vm/database"
 	"github.com/iost-official/go-iost/vm/host"
@@ -104,3 +109,320 @@ func TestEngine_SetCode(t *testing.T) {
 		t.Fatalf("LoadAndCall except 0 rtn"+", got %d\n", len(rs))
 	}
 }
+
+// nolint
+func TestEngine_UpdateCodeCompatibility(t *testing.T) {
+
+	e, host, code := InitVMWithMonitor(t, "setcode", int64(400000000))
+	host.Context().Set("tx_hash", "iamhash")
+	host.Context().Set("contract_name", "system.iost")
+	host.Context().Set("auth_contract_list", make(map[string]int))
+	host.Context().Set("number", int64(10))
+	host.SetDeadline(time.Now().Add(10 * time.Second))
+	defer func(f common.ForkConfig) { common.Forks = f }(common.Forks)
+
+	rawCode, err := ioutil.ReadFile(testDataPath + "test.js")
+	if err != nil {
+		t.Fatalf("read file error: %v\n", err)
+	}
+	rawAbi, err := ioutil.ReadFile(testDataPath + "test.js.abi")
+	if err != nil {
+		t.Fatalf("read file error: %v\n", err)
+	}
+
+	compiler := &contract.Compiler{}
+	con, err := compiler.Parse("", string(rawCode), string(rawAbi))
+	if err != nil {
+		t.Fatalf("compiler parse error: %v\n", err)
+	}
+
+	_, _, err = e.LoadAndCall(host, code, "setCode", con.B64Encode())
+	if err != nil {
+		t.Fatalf("LoadAndCall setcode error: %v\n", err)
+	}
+
+	rawAbi, err = ioutil.ReadFile(testDataPath + "test_new.js.abi")
+	if err != nil {
+		t.Fatalf("read file error: %v\n", err)
+	}
+	con, err = compiler.Parse("Contractiamhash", string(rawCode), string(rawAbi))
+	if err != nil {
+		t.Fatalf("compiler parse error: %v\n", err)
+	}
+	_, _, err = e.LoadAndCall(host, code, "updateCode", con.B64Encode(), "")
+	if err != nil {
+		t.Fatalf("LoadAndCall compatible update error: %v\n", err)
+	}
+
+	rawAbi, err = ioutil.ReadFile(testDataPath + "test_removed.js.abi")
+	if err != nil {
+		t.Fatalf("read file error: %v\n", err)
+	}
+	con, err = compiler.Parse("Contractiamhash", string(rawCode), string(rawAbi))
+	if err != nil {
+		t.Fatalf("compiler parse error: %v\n", err)
+	}
+	_, _, err = e.LoadAndCall(host, code, "forceUpdateCode", con.B64Encode(), "")
+	if err == nil {
+		t.Fatalf("LoadAndCall force update should fail before the fork\n")
+	}
+
+	height := int64(10)
+	common.Forks.UpdateCodeCompatibility = &height
+	_, _, err = e.LoadAndCall(host, code, "updateCode", con.B64Encode(), "")
+	if err == nil {
+		t.Fatalf("LoadAndCall update should fail when abi number is removed\n")
+	}
+
+	_, _, err = e.LoadAndCall(host, code, "forceUpdateCode", con.B64Encode(), "")
+	if err != nil {
+		t.Fatalf("LoadAndCall force update error: %v\n", err)
+	}
+}
+
+func TestEngine_Receipt(t *testing.T) {
+	e, h, code := InitVMWithMonitor(t, "setcode")
+	h.Context().Set("contract_name", "system.iost")
+	h.Context().Set("abi_name", "receipt")
+	h.Context().Set("number", int64(10))
+	h.Context().GSet("receipts", []*tx.Receipt{})
+
+	content := strings.Repeat("a", native.MaxReceiptContentLen)
+	_, _, err := e.LoadAndCall(h, code, "receipt", content+"a")
+	if err != nil {
+		t.Fatalf("receipt over limit before the fork error: %v", err)
+	}
+	h.Context().GSet("receipts", []*tx.Receipt{})
+
+	defer func(f common.ForkConfig) { common.Forks = f }(common.Forks)
+	height := int64(10)
+	common.Forks.ReceiptContentLimit = &height
+	_, cost, err := e.LoadAndCall(h, code, "receipt", content)
+	if err != nil {
+		t.Fatalf("receipt at limit error: %v", err)
+	}
+	if cost.Net < int64(len(content)) {
+		t.Fatalf("receipt cost should grow with content size, got %v", cost)
+	}
+	if rs := h.Context().GValue("receipts").([]*tx.Receipt); len(rs) != 1 || rs[0].Content != content {
+		t.Fatalf("receipt not recorded, got %v", rs)
+	}
+
+	_, cost, err = e.LoadAndCall(h, code, "receipt", content+"a")
+	if err != host.ErrReceiptTooLarge {
+		t.Fatalf("receipt over limit expect %v, got %v", host.ErrReceiptTooLarge, err)
+	}
+	if cost.ToGas() == 0 {
+		t.Fatalf("receipt over limit should still charge cost")
+	}
+	if rs := h.Context().GValue("receipts").([]*tx.Receipt); len(rs) != 1 {
+		t.Fatalf("over limit receipt should not be recorded, got %d", len(rs))
+	}
+}
+
+func TestEngine_Random(t *testing.T) {
+	random := func(nonce string) string {
+		e, h, code := InitVMWithMonitor(t, "setcode")
+		h.Context().Set("parent_hash", "parenthash")
+		h.Context().Set("number", int64(10))
+		rs, _, err := e.LoadAndCall(h, code, "random", nonce)
+		if err != nil {
+			t.Fatalf("LoadAndCall random error: %v", err)
+		}
+		return rs[0].(string)
+	}
+
+	if random("nonce0") != random("nonce0") {
+		t.Fatalf("random should be the same on nodes with the same block")
+	}
+	if random("nonce0") == random("nonce1") {
+		t.Fatalf("random should differ with different nonces")
+	}
+}
+
+func TestEngine_PauseContract(t *testing.T) {
+	e, h, code := InitVMWithMonitor(t, "setcode", int64(400000000))
+	h.Context().Set("tx_hash", "iamhash")
+	h.Context().Set("contract_name", "system.iost")
+	h.Context().Set("auth_contract_list", make(map[string]int))
+	h.Context().Set("number", int64(0))
+	h.SetDeadline(time.Now().Add(10 * time.Second))
+
+	rawCode, err := ioutil.ReadFile(testDataPath + "test.js")
+	if err != nil {
+		t.Fatalf("read file error: %v\n", err)
+	}
+	rawAbi, err := ioutil.ReadFile(testDataPath + "test.js.abi")
+	if err != nil {
+		t.Fatalf("read file error: %v\n", err)
+	}
+	compiler := &contract.Compiler{}
+	con, err := compiler.Parse("", string(rawCode), string(rawAbi))
+	if err != nil {
+		t.Fatalf("compiler parse error: %v\n", err)
+	}
+	rs, _, err := e.LoadAndCall(h, code, "setCode", con.B64Encode())
+	if err != nil {
+		t.Fatalf("LoadAndCall setcode error: %v\n", err)
+	}
+	id := rs[0].(string)
+
+	_, _, err = e.LoadAndCall(h, code, "pauseContract", "Contractnotexists")
+	if err != host.ErrContractNotFound {
+		t.Fatalf("pause unknown contract expect %v, got %v", host.ErrContractNotFound, err)
+	}
+
+	_, _, err = e.LoadAndCall(h, code, "pauseContract", id)
+	if err != nil {
+		t.Fatalf("LoadAndCall pauseContract error: %v\n", err)
+	}
+	if paused, _ := h.ContractPaused(id); !paused {
+		t.Fatalf("contract should be paused")
+	}
+	_, _, err = h.Call(id, "number", "[]")
+	if err != host.ErrContractPaused {
+		t.Fatalf("call paused contract expect %v, got %v", host.ErrContractPaused, err)
+	}
+
+	_, _, err = e.LoadAndCall(h, code, "unpauseContract", id)
+	if err != nil {
+		t.Fatalf("LoadAndCall unpauseContract error: %v\n", err)
+	}
+	if paused, _ := h.ContractPaused(id); paused {
+		t.Fatalf("contract should be unpaused")
+	}
+}
+
+func TestEngine_SetCodeRateLimit(t *testing.T) {
+	e, h, code := InitVMWithMonitor(t, "setcode", int64(400000000))
+	h.Context().Set("contract_name", "system.iost")
+	h.Context().Set("auth_contract_list", make(map[string]int))
+	h.Context().Set("time", int64(0))
+	h.SetDeadline(time.Now().Add(10 * time.Second))
+
+	oldMax := native.MaxDeploysPerWindow
+	native.MaxDeploysPerWindow = 2
+	defer func() { native.MaxDeploysPerWindow = oldMax }()
+
+	rawCode, err := ioutil.ReadFile(testDataPath + "test.js")
+	if err != nil {
+		t.Fatalf("read file error: %v\n", err)
+	}
+	rawAbi, err := ioutil.ReadFile(testDataPath + "test.js.abi")
+	if err != nil {
+		t.Fatalf("read file error: %v\n", err)
+	}
+	compiler := &contract.Compiler{}
+	con, err := compiler.Parse("", string(rawCode), string(rawAbi))
+	if err != nil {
+		t.Fatalf("compiler parse error: %v\n", err)
+	}
+
+	deploy := func(hash string) error {
+		h.Context().Set("tx_hash", hash)
+		_, _, err := e.LoadAndCall(h, code, "setCode", con.B64Encode())
+		return err
+	}
+	defer func(f common.ForkConfig) { common.Forks = f }(common.Forks)
+	height := int64(11)
+	common.Forks.DeployRateLimit = &height
+	h.Context().Set("number", int64(10))
+	for i := 0; i < 3; i++ {
+		if err := deploy(fmt.Sprintf("early%v", i)); err != nil {
+			t.Fatalf("deploy %v before the fork error: %v\n", i, err)
+		}
+	}
+	if keys, _ := h.MapKeys("deploy_rate"); len(keys) != 0 {
+		t.Fatalf("deploy rate should not be counted before the fork, got %v", keys)
+	}
+
+	h.Context().Set("number", int64(11))
+	for i := 0; i < 2; i++ {
+		if err := deploy(fmt.Sprintf("hash%v", i)); err != nil {
+			t.Fatalf("deploy %v error: %v\n", i, err)
+		}
+	}
+	if err := deploy("hash2"); err != host.ErrDeployRateExceeded {
+		t.Fatalf("deploy past limit expect %v, got %v", host.ErrDeployRateExceeded, err)
+	}
+
+	h.Context().Set("time", int64(native.DeployRateWindow))
+	if err := deploy("hash3"); err != nil {
+		t.Fatalf("deploy in next window error: %v\n", err)
+	}
+	if keys, _ := h.MapKeys("deploy_rate"); len(keys) != 1 {
+		t.Fatalf("deploy rate should keep one key per publisher, got %v", keys)
+	}
+	rate, _ := h.MapGet("deploy_rate", "pub")
+	if j, ok := rate.(database.SerializedJSON); !ok || string(j) != `{"window":1,"count":1}` {
+		t.Fatalf("deploy rate of next window expect {window:1, count:1}, got %v", rate)
+	}
+}
+
+func TestEngine_StorageKeys(t *testing.T) {
+	e, h, code := InitVMWithMonitor(t, "setcode")
+	h.Context().Set("contract_name", "Contractabc")
+	for i := 0; i < 5; i++ {
+		h.DB().MPut("Contractabc-m", fmt.Sprintf("k%v", i), database.MustMarshal(int64(i)))
+	}
+	h.DB().MPut("Contractother-m", "other", database.MustMarshal(int64(0)))
+
+	pages := []struct {
+		cursor int64
+		keys   string
+		next   int64
+	}{
+		{0, `["k0","k1"]`, 2},
+		{2, `["k2","k3"]`, 4},
+		{4, `["k4"]`, 0},
+		{5, `[]`, 0},
+	}
+	var pageCost int64
+	for _, p := range pages {
+		rs, cost, err := e.LoadAndCall(h, code, "storageKeys", "m", p.cursor, int64(2))
+		if err != nil {
+			t.Fatalf("LoadAndCall storageKeys error: %v\n", err)
+		}
+		if rs[0].(string) != p.keys || rs[1].(int64) != p.next {
+			t.Fatalf("storageKeys from %v expect %v %v, got %v %v", p.cursor, p.keys, p.next, rs[0], rs[1])
+		}
+		if pageCost != 0 && cost.ToGas() != pageCost {
+			t.Fatalf("storageKeys should charge for all the keys read, got %v and %v", pageCost, cost.ToGas())
+		}
+		pageCost = cost.ToGas()
+	}
+
+	rs, _, err := e.LoadAndCall(h, code, "storageKeys", "empty", int64(0), int64(10))
+	if err != nil {
+		t.Fatalf("LoadAndCall storageKeys error: %v\n", err)
+	}
+	if rs[0].(string) != "[]" || rs[1].(int64) != 0 {
+		t.Fatalf("storageKeys of empty prefix got %v %v", rs[0], rs[1])
+	}
+
+	_, _, err = e.LoadAndCall(h, code, "storageKeys", "m", int64(0), native.MaxStorageKeysLimit+1)
+	if err == nil {
+		t.Fatalf("storageKeys over max limit should fail")
+	}
+}
+
+func TestSystemABINames(t *testing.T) {
+	names := native.SystemABINames()
+	if !sort.StringsAreSorted(names) {
+		t.Fatalf("abi names are not sorted: %v", names)
+	}
+	for i := 0; i < 10; i++ {
+		if got := native.SystemABINames(); strings.Join(got, ",") != strings.Join(names, ",") {
+			t.Fatalf("abi names changed: %v, expected %v", got, names)
+		}
+	}
+	c := native.SystemABI()
+	if len(c.Info.Abi) != len(names) {
+		t.Fatalf("abi count mismatch: %v, expected %v", len(c.Info.Abi), len(names))
+	}
+	for i, a := range c.Info.Abi {
+		if a.Name != names[i] {
+			t.Fatalf("abi %v is %v, expected %v", i, a.Name, names[i])
+		}
+	}
+}
@@ -247,6 +247,44 @@ func TestToken_Issue(t *testing.T) {
 	})
 }
 
+func TestToken_TokenInfo(t *testing.T) {
+	issuer0 := "issuer0"
+	e, host, code := InitVM(t, "token")
+	code.ID = "token.iost"
+	host.Context().Set("contract_name", "token.iost")
+	host.SetDeadline(time.Now().Add(10 * time.Second))
+	authList := host.Context().Value("auth_list").(map[string]int)
+
+	Convey("Test of Token tokenInfo", t, func() {
+		Convey("unknown token", func() {
+			_, _, err := e.LoadAndCall(host, code, "tokenInfo", "unknown")
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "token not exists")
+		})
+
+		Convey("issued token", func() {
+			authList[issuer0] = 1
+			host.Context().Set("auth_list", authList)
+			_, _, err := e.LoadAndCall(host, code, "create", "mytoken", "issuer0", int64(100), []byte(`{"decimal": 4}`))
+			So(err, ShouldBeNil)
+			_, _, err = e.LoadAndCall(host, code, "issue", "mytoken", "user0", "1.1")
+			So(err, ShouldBeNil)
+
+			rs, cost, err := e.LoadAndCall(host, code, "tokenInfo", "mytoken")
+			So(err, ShouldBeNil)
+			So(cost.ToGas(), ShouldBeGreaterThan, 0)
+			So(rs[0], ShouldEqual, `{"symbol":"mytoken","decimals":4,"totalSupply":"100"}`)
+		})
+
+		Convey("invalid decimals", func() {
+			authList[issuer0] = 1
+			host.Context().Set("auth_list", authList)
+			_, _, err := e.LoadAndCall(host, code, "create", "badtoken", "issuer0", int64(1), []byte(`{"decimal": 19}`))
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
 func TestToken_Transfer(t *testing.T) {
 	issuer0 := "issuer0"
 	e, host, code := InitVM(t, "token")
@@ -488,6 +526,186 @@ func TestToken_Destroy(t *testing.T) {
 	})
 }
 
+func TestToken_Freeze(t *testing.T) {
+	issuer0 := "issuer0"
+	e, host, code := InitVM(t, "token")
+	code.ID = "token.iost"
+	host.Context().Set("contract_name", "token.iost")
+	host.SetDeadline(time.Now().Add(10 * time.Second))
+	authList := host.Context().Value("auth_list").(map[string]int)
+	now := int64(time.Now().Unix()) * 1e9
+
+	Convey("Test of Token freeze", t, func() {
+
+		Reset(func() {
+			e, host, code = InitVM(t, "token")
+			code.ID = "token.iost"
+			host.Context().Set("contract_name", "token.iost")
+			host.SetDeadline(time.Now().Add(10 * time.Second))
+			host.Context().Set("time", now)
+			authList = host.Context().Value("auth_list").(map[string]int)
+
+			authList[issuer0] = 1
+			host.Context().Set("auth_list", authList)
+			_, _, err := e.LoadAndCall(host, code, "create", "iost", "issuer0", int64(100), []byte("{}"))
+			So(err, ShouldBeNil)
+
+			_, _, err = e.LoadAndCall(host, code, "issue", "iost", "issuer0", "100")
+			So(err, ShouldBeNil)
+		})
+
+		Convey("freeze prepare", func() {
+			authList[issuer0] = 1
+			host.Context().Set("auth_list", authList)
+			_, _, err := e.LoadAndCall(host, code, "create", "iost", "issuer0", int64(100), []byte("{}"))
+			So(err, ShouldBeNil)
+
+			_, _, err = e.LoadAndCall(host, code, "issue", "iost", "issuer0", "100")
+			So(err, ShouldBeNil)
+		})
+
+		Convey("spend partially frozen balance", func() {
+			_, cost, err := e.LoadAndCall(host, code, "freeze", "iost", "issuer0", "60", now+10)
+			So(err, ShouldBeNil)
+			So(cost.ToGas(), ShouldBeGreaterThan, 0)
+
+			rs, _, err := e.LoadAndCall(host, code, "balanceOf", "iost", "issuer0")
+			So(err, ShouldBeNil)
+			So(rs[0], ShouldEqual, "40")
+
+			_, _, err = e.LoadAndCall(host, code, "transfer", "iost", "issuer0", "user0", "50", "")
+			So(err, ShouldNotBeNil)
+
+			_, _, err = e.LoadAndCall(host, code, "transfer", "iost", "issuer0", "user0", "40", "")
+			So(err, ShouldBeNil)
+
+			host.Context().Set("time", now+10)
+			_, _, err = e.LoadAndCall(host, code, "transfer", "iost", "issuer0", "user0", "50", "")
+			So(err, ShouldBeNil)
+
+			rs, _, err = e.LoadAndCall(host, code, "balanceOf", "iost", "issuer0")
+			So(err, ShouldBeNil)
+			So(rs[0], ShouldEqual, "10")
+		})
+
+		Convey("freeze with invalid time", func() {
+			_, _, err := e.LoadAndCall(host, code, "freeze", "iost", "issuer0", "60", now)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("freeze too much", func() {
+			_, _, err := e.LoadAndCall(host, code, "freeze", "iost", "issuer0", "100.1", now+10)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("freeze without auth", func() {
+			_, _, err := e.LoadAndCall(host, code, "freeze", "iost", "user0", "1", now+10)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestToken_Allowance(t *testing.T) {
+	issuer0 := "issuer0"
+	e, host, code := InitVM(t, "token")
+	code.ID = "token.iost"
+	host.Context().Set("contract_name", "token.iost")
+	host.SetDeadline(time.Now().Add(10 * time.Second))
+	authList := host.Context().Value("auth_list").(map[string]int)
+
+	Convey("Test of Token allowance", t, func() {
+
+		Reset(func() {
+			e, host, code = InitVM(t, "token")
+			code.ID = "token.iost"
+			host.Context().Set("contract_name", "token.iost")
+			host.SetDeadline(time.Now().Add(10 * time.Second))
+			authList = host.Context().Value("auth_list").(map[string]int)
+
+			authList[issuer0] = 1
+			host.Context().Set("auth_list", authList)
+			_, _, err := e.LoadAndCall(host, code, "create", "iost", "issuer0", int64(100), []byte("{}"))
+			So(err, ShouldBeNil)
+
+			_, _, err = e.LoadAndCall(host, code, "issue", "iost", "issuer0", "100")
+			So(err, ShouldBeNil)
+		})
+
+		Convey("allowance prepare", func() {
+			authList[issuer0] = 1
+			host.Context().Set("auth_list", authList)
+			_, _, err := e.LoadAndCall(host, code, "create", "iost", "issuer0", int64(100), []byte("{}"))
+			So(err, ShouldBeNil)
+
+			_, _, err = e.LoadAndCall(host, code, "issue", "iost", "issuer0", "100")
+			So(err, ShouldBeNil)
+		})
+
+		Convey("approve", func() {
+			rs, _, err := e.LoadAndCall(host, code, "allowance", "iost", "issuer0", "user0")
+			So(err, ShouldBeNil)
+			So(rs[0], ShouldEqual, "0")
+
+			_, cost, err := e.LoadAndCall(host, code, "approve", "iost", "issuer0", "user0", "30")
+			So(err, ShouldBeNil)
+			So(cost.ToGas(), ShouldBeGreaterThan, 0)
+			rs, _, err = e.LoadAndCall(host, code, "allowance", "iost", "issuer0", "user0")
+			So(err, ShouldBeNil)
+			So(rs[0], ShouldEqual, "30")
+
+			_, _, err = e.LoadAndCall(host, code, "approve", "iost", "issuer0", "user0", "0")
+			So(err, ShouldBeNil)
+			rs, _, err = e.LoadAndCall(host, code, "allowance", "iost", "issuer0", "user0")
+			So(err, ShouldBeNil)
+			So(rs[0], ShouldEqual, "0")
+		})
+
+		Convey("approve without auth", func() {
+			_, _, err := e.LoadAndCall(host, code, "approve", "iost", "user1", "user0", "30")
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("spend within allowance", func() {
+			_, _, err := e.LoadAndCall(host, code, "approve", "iost", "issuer0", "user0", "30")
+			So(err, ShouldBeNil)
+
+			delete(authList, issuer0)
+			authList["user0"] = 1
+			host.Context().Set("auth_list", authList)
+			_, _, err = e.LoadAndCall(host, code, "transferFrom", "iost", "user0", "issuer0", "user1", "20")
+			So(err, ShouldBeNil)
+
+			rs, _, err := e.LoadAndCall(host, code, "balanceOf", "iost", "issuer0")
+			So(err, ShouldBeNil)
+			So(rs[0], ShouldEqual, "80")
+			rs, _, err = e.LoadAndCall(host, code, "balanceOf", "iost", "user1")
+			So(err, ShouldBeNil)
+			So(rs[0], ShouldEqual, "20")
+			rs, _, err = e.LoadAndCall(host, code, "allowance", "iost", "issuer0", "user0")
+			So(err, ShouldBeNil)
+			So(rs[0], ShouldEqual, "10")
+		})
+
+		Convey("spend over allowance", func() {
+			_, _, err := e.LoadAndCall(host, code, "approve", "iost", "issuer0", "user0", "30")
+			So(err, ShouldBeNil)
+
+			delete(authList, issuer0)
+			authList["user0"] = 1
+			host.Context().Set("auth_list", authList)
+			_, _, err = e.LoadAndCall(host, code, "transferFrom", "iost", "user0", "issuer0", "user1", "30.1")
+			So(err.Error(), ShouldEqual, "allowance not enough 30 < 30.1")
+
+			_, _, err = e.LoadAndCall(host, code, "transferFrom", "iost", "user1", "issuer0", "user1", "1")
+			So(err, ShouldNotBeNil)
+
+			rs, _, err := e.LoadAndCall(host, code, "balanceOf", "iost", "issuer0")
+			So(err, ShouldBeNil)
+			So(rs[0], ShouldEqual, "100")
+		})
+	})
+}
+
 func TestToken_TransferFreeze(t *testing.T) {
 	issuer0 := "issuer0"
 	e, host, code := InitVM(t, "token")
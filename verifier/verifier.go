@@ -35,13 +35,17 @@ type Config struct {
 	Timeout     time.Duration
 	TxTimeLimit time.Duration
 	Thread      int
+	// MinGasRatio is the lowest gas ratio of the txs packed by Gen, the others are skipped.
+	MinGasRatio int64
 }
 
 // Info info in block
 type Info struct {
-	Mode   int   `json:"mode"`
-	Thread int   `json:"thread"`
-	Batch  []int `json:"batch"`
+	Mode     int    `json:"mode"`
+	Thread   int    `json:"thread"`
+	Batch    []int  `json:"batch"`
+	BaseFee  int64  `json:"base_fee,omitempty"`
+	SignHash string `json:"sign_hash,omitempty"`
 }
 
 //var ParallelMask int64 = 1 // 0000 0001
@@ -150,6 +154,7 @@ func baseGen(blk *block.Block, db database.IMultiValue, provider Provider, isola
 	var tn time.Time
 	to := time.Now().Add(c.Timeout)
 	blockGasLimit := common.MaxBlockGasLimit
+	blockTxsSize := common.MaxBlockTxsSize
 
 L:
 	for tn.Before(to) {
@@ -186,7 +191,11 @@ L:
 			provider.Drop(t, ErrExpiredTx)
 			continue L
 		}
-		if t.GasLimit > blockGasLimit {
+		if t.GasLimit > blockGasLimit || t.GasRatio < c.MinGasRatio {
+			continue L
+		}
+		txSize := t.EncodedLen()
+		if txSize > blockTxsSize {
 			continue L
 		}
 		err := isolator.PrepareTx(t, limit)
@@ -222,6 +231,7 @@ L:
 		blk.Txs = append(blk.Txs, t)
 		blk.Receipts = append(blk.Receipts, r)
 		blockGasLimit -= r.GasUsage
+		blockTxsSize -= txSize
 	}
 	buf, err := json.Marshal(info)
 	if err != nil {
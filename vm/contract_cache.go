@@ -0,0 +1,60 @@
+package vm
+
+import (
+	"sync/atomic"
+
+	"github.com/hashicorp/golang-lru"
+	"github.com/iost-official/go-iost/common"
+	"github.com/iost-official/go-iost/core/contract"
+	"github.com/iost-official/go-iost/vm/host"
+)
+
+var contractCacheSize = 1024
+
+type cachedContract struct {
+	hash string
+	c    *contract.Contract
+}
+
+// contractCache caches the decoded contracts by id. An entry is used only if the hash of
+// the contract in storage is unchanged, since the monitor is shared by different states.
+type contractCache struct {
+	cache  *lru.Cache
+	hits   int64
+	misses int64
+}
+
+func newContractCache(size int) *contractCache {
+	c, err := lru.New(size)
+	if err != nil {
+		panic(err)
+	}
+	return &contractCache{cache: c}
+}
+
+func (cc *contractCache) get(h *host.Host, cid string) *contract.Contract {
+	raw := h.DB().ContractRaw(cid)
+	if raw == "" {
+		return nil
+	}
+	hash := string(common.Sha3([]byte(raw)))
+	if v, ok := cc.cache.Get(cid); ok && v.(*cachedContract).hash == hash {
+		atomic.AddInt64(&cc.hits, 1)
+		return v.(*cachedContract).c
+	}
+	atomic.AddInt64(&cc.misses, 1)
+	c := &contract.Contract{}
+	if err := c.Decode(raw); err != nil {
+		return nil
+	}
+	cc.cache.Add(cid, &cachedContract{hash: hash, c: c})
+	return c
+}
+
+func (cc *contractCache) remove(cid string) {
+	cc.cache.Remove(cid)
+}
+
+func (cc *contractCache) stats() (hits, misses int) {
+	return int(atomic.LoadInt64(&cc.hits)), int(atomic.LoadInt64(&cc.misses))
+}
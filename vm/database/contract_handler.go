@@ -32,6 +32,11 @@ func (m *ContractHandler) Contract(key string) (c *contract.Contract) {
 	return
 }
 
+// ContractRaw get the encoded contract by key, "" if not exists
+func (m *ContractHandler) ContractRaw(key string) string {
+	return m.db.Get(ContractPrefix + key)
+}
+
 // HasContract determine if contract existed
 func (m *ContractHandler) HasContract(key string) bool {
 	return m.db.Has(ContractPrefix + key)
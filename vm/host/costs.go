@@ -1,6 +1,12 @@
 package host
 
-import "github.com/iost-official/go-iost/core/contract"
+import (
+	"errors"
+	"sync/atomic"
+
+	"github.com/iost-official/go-iost/common"
+	"github.com/iost-official/go-iost/core/contract"
+)
 
 // var costs
 var (
@@ -18,9 +24,75 @@ var (
 		"SetCodePrice":     contract.NewCost(0, 0, 70),
 		"OpPrice":          contract.NewCost(0, 0, 1),
 		"ErrPrice":         contract.NewCost(0, 0, 1),
+		"CallDepthCost":    contract.NewCost(0, 0, 100),
+		"TransferCost":     contract.NewCost(0, 0, 10),
+		"CallPrice":        contract.NewCost(0, 0, 1),
+	}
+)
+
+// CostModel is the set of per-operation costs a chain can tune
+type CostModel struct {
+	StorageRead  contract.Cost
+	StorageWrite contract.Cost
+	Transfer     contract.Cost
+	Call         contract.Cost
+	Error        contract.Cost
+}
+
+// DefaultCostModel returns the built-in cost model
+func DefaultCostModel() *CostModel {
+	return &CostModel{
+		StorageRead:  contract.NewCost(0, 0, 300),
+		StorageWrite: contract.NewCost(0, 0, 300),
+		Transfer:     contract.NewCost(0, 0, 10),
+		Call:         contract.NewCost(0, 0, 1),
+		Error:        contract.NewCost(0, 0, 1),
+	}
+}
+
+// NewCostModel returns the cost model of the config, the costs not configured keep the defaults
+func NewCostModel(conf *common.CostModelConfig) *CostModel {
+	m := DefaultCostModel()
+	if conf == nil {
+		return m
+	}
+	set := func(cost *contract.Cost, cpu int64) {
+		if cpu > 0 {
+			*cost = contract.NewCost(0, 0, cpu)
+		}
 	}
+	set(&m.StorageRead, conf.StorageRead)
+	set(&m.StorageWrite, conf.StorageWrite)
+	set(&m.Transfer, conf.Transfer)
+	set(&m.Call, conf.Call)
+	set(&m.Error, conf.Error)
+	return m
+}
+
+var (
+	errCostModelLoaded = errors.New("cost model is already loaded")
+	costModelLoaded    int32
 )
 
+// LoadCostModel replaces the costs charged by host and native contracts, nil keeps the default.
+// It can be called only once, at node start before any vm runs, so the costs are immutable while vms read them.
+func LoadCostModel(m *CostModel) error {
+	if !atomic.CompareAndSwapInt32(&costModelLoaded, 0, 1) {
+		return errCostModelLoaded
+	}
+	if m == nil {
+		return nil
+	}
+	Costs["GetCost"] = m.StorageRead
+	Costs["KeysCost"] = m.StorageRead
+	Costs["PutCost"] = m.StorageWrite
+	Costs["DelCost"] = m.StorageWrite
+	Costs["TransferCost"] = m.Transfer
+	Costs["CallPrice"] = m.Call
+	Costs["ErrPrice"] = m.Error
+	return nil
+}
+
 // EventCost return cost based on event size
 func EventCost(size int) contract.Cost {
 	return Costs["EventPrice"].Multiply(int64(size))
@@ -48,11 +120,26 @@ func CommonErrorCost(layer int) contract.Cost {
 	return Costs["ErrPrice"].Multiply(int64(layer * 10))
 }
 
+// CallCost returns cost of a contract call increased by stack layer
+func CallCost(layer int) contract.Cost {
+	return Costs["CallPrice"].Multiply(int64(layer * 10))
+}
+
+// TransferCost returns base cost of a token transfer
+func TransferCost() contract.Cost {
+	return Costs["TransferCost"]
+}
+
 // CommonOpCost returns cost increased by stack layer
 func CommonOpCost(layer int) contract.Cost {
 	return Costs["OpPrice"].Multiply(int64(layer * 10))
 }
 
+// CallDepthExceededCost returns the fixed cost of a call rejected by MaxCallDepth
+func CallDepthExceededCost() contract.Cost {
+	return Costs["CallDepthCost"]
+}
+
 // DelayTxCost returns cost of a delay transaction.
 func DelayTxCost(dataLen int, payer string) contract.Cost {
 	cost := Costs["PutCost"]
@@ -0,0 +1,41 @@
+package host
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/iost-official/go-iost/common"
+	"github.com/iost-official/go-iost/core/contract"
+)
+
+func TestLoadCostModel(t *testing.T) {
+	defaults := make(map[string]contract.Cost, len(Costs))
+	for k, v := range Costs {
+		defaults[k] = v
+	}
+	defer func() {
+		Costs = defaults
+		costModelLoaded = 0
+	}()
+
+	if !reflect.DeepEqual(NewCostModel(nil), DefaultCostModel()) {
+		t.Fatal(NewCostModel(nil))
+	}
+	m := NewCostModel(&common.CostModelConfig{Transfer: 1000})
+	if m.Transfer.CPU != 1000 || m.StorageRead.CPU != DefaultCostModel().StorageRead.CPU {
+		t.Fatal(m)
+	}
+
+	if err := LoadCostModel(m); err != nil {
+		t.Fatal(err)
+	}
+	if TransferCost().CPU != 1000 || Costs["GetCost"].CPU != DefaultCostModel().StorageRead.CPU {
+		t.Fatal(Costs)
+	}
+	if err := LoadCostModel(DefaultCostModel()); err != errCostModelLoaded {
+		t.Fatal(err)
+	}
+	if TransferCost().CPU != 1000 {
+		t.Fatal(TransferCost())
+	}
+}
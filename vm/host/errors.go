@@ -4,19 +4,24 @@ import "errors"
 
 // var errors
 var (
-	ErrBalanceNotEnough = errors.New("balance not enough")
-	ErrTransferNegValue = errors.New("trasfer amount less than zero")
-	ErrReenter          = errors.New("re-entering")
-	ErrPermissionLost   = errors.New("transaction has no permission")
-	ErrInvalidData      = errors.New("invalid data")
-	ErrInvalidAmount    = errors.New("invalid amount")
-	ErrOutOfGas         = errors.New("out of gas")
+	ErrBalanceNotEnough  = errors.New("balance not enough")
+	ErrTransferNegValue  = errors.New("trasfer amount less than zero")
+	ErrReenter           = errors.New("re-entering")
+	ErrCallDepthExceeded = errors.New("call depth exceeded")
+	ErrPermissionLost    = errors.New("transaction has no permission")
+	ErrInvalidData       = errors.New("invalid data")
+	ErrInvalidAmount     = errors.New("invalid amount")
+	ErrOutOfGas          = errors.New("out of gas")
 
 	ErrContractNotFound   = errors.New("contract not exists")
 	ErrContractExists     = errors.New("contract exists")
 	ErrAbiHasInternalFunc = errors.New("abi has internal function")
 	ErrUpdateRefused      = errors.New("update refused")
+	ErrUpdateIncompatible = errors.New("update incompatible")
 	ErrDestroyRefused     = errors.New("destroy refused")
+	ErrContractPaused     = errors.New("contract is paused")
+	ErrDeployRateExceeded = errors.New("deploy rate exceeded")
+	ErrNotActivated       = errors.New("not activated")
 
 	ErrCoinExists         = errors.New("coin exists")
 	ErrCoinNotExists      = errors.New("coin not exists")
@@ -29,6 +34,8 @@ var (
 	ErrTokenNoTransfer           = errors.New("token can't transfer")
 	ErrTokenIssueRefused         = errors.New("token issue refused")
 	ErrMemoTooLarge              = errors.New("memo too large")
+	ErrReceiptTooLarge           = errors.New("receipt too large")
+	ErrActionDataTooLarge        = errors.New("action data too large")
 
 	ErrDelaytxNotFound   = errors.New("delaytx not exists")
 	ErrCannotCancelDelay = errors.New("can not cancel delaytx")
@@ -15,11 +15,15 @@ import (
 	"github.com/iost-official/go-iost/vm/database"
 )
 
+// MaxCallDepth is the max depth of nested contract calls in one transaction
+var MaxCallDepth = 5
+
 // Monitor monitor interface
 type Monitor interface {
 	Call(host *Host, contractName, api string, jarg string) (rtn []interface{}, cost contract.Cost, err error)
 	Validate(con *contract.Contract) error
 	Compile(con *contract.Contract) (string, error)
+	Invalidate(contractID string)
 }
 
 // Host host struct, used as isolate of vm
@@ -66,6 +70,12 @@ func (h *Host) Context() *Context {
 	return h.ctx
 }
 
+// Activated returns whether the rule change activated at height, one of common.Forks, applies to the current block
+func (h *Host) Activated(height *int64) bool {
+	number, _ := h.ctx.Value("number").(int64)
+	return common.Activated(height, number)
+}
+
 // SetContext set a new context to host
 func (h *Host) SetContext(ctx *Context) {
 	h.ctx = ctx
@@ -79,6 +89,9 @@ func (h *Host) Call(cont, api, jarg string, withAuth ...bool) ([]interface{}, co
 	record := cont + "-" + api
 
 	height := h.ctx.Value("stack_height").(int)
+	if height >= MaxCallDepth && h.Activated(common.Forks.CallDepthLimit) {
+		return nil, CallDepthExceededCost(), ErrCallDepthExceeded
+	}
 
 	for i := 0; i < height; i++ {
 		key := "stack" + strconv.Itoa(i)
@@ -104,7 +117,7 @@ func (h *Host) Call(cont, api, jarg string, withAuth ...bool) ([]interface{}, co
 	h.ctx.Set("stack_height", height+1)
 	h.ctx.Set(key, record)
 	rtn, cost, err := h.monitor.Call(h, cont, api, jarg)
-	cost.AddAssign(CommonOpCost(height))
+	cost.AddAssign(CallCost(height))
 
 	return rtn, cost, err
 }
@@ -218,14 +231,22 @@ func (h *Host) SetCode(c *contract.Contract, owner string) (contract.Cost, error
 		return cost, ErrContractExists
 	}
 	h.db.SetContract(c)
+	h.monitor.Invalidate(c.ID)
 	_, cost0, err = h.Call(c.ID, "init", "[]")
 	cost.AddAssign(cost0)
 
 	return cost, err
 }
 
-// UpdateCode update code
-func (h *Host) UpdateCode(c *contract.Contract, id database.SerializedJSON) (contract.Cost, error) {
+// ContractPaused returns whether the contract is paused by its owner
+func (h *Host) ContractPaused(id string) (bool, contract.Cost) {
+	v, cost := h.GlobalMapGet("system.iost", "contract_paused", id)
+	paused, _ := v.(bool)
+	return paused, cost
+}
+
+// UpdateCode update code. Unless force is set, the new abi must keep every abi of the old one with the same args.
+func (h *Host) UpdateCode(c *contract.Contract, id database.SerializedJSON, force bool) (contract.Cost, error) {
 	if err := c.VerifySelf(); err != nil {
 		return CommonErrorCost(1), err
 	}
@@ -250,6 +271,13 @@ func (h *Host) UpdateCode(c *contract.Contract, id database.SerializedJSON) (con
 		return cost, ErrUpdateRefused
 	}
 
+	if !force && h.Activated(common.Forks.UpdateCodeCompatibility) {
+		cost.AddAssign(CommonOpCost(len(oc.Info.Abi)))
+		if err := c.Info.CheckCompatible(oc.Info); err != nil {
+			return cost, fmt.Errorf("%v: %v", ErrUpdateIncompatible, err)
+		}
+	}
+
 	cost0, err := h.checkAbiValid(c)
 	cost.AddAssign(cost0)
 	if err != nil {
@@ -271,6 +299,7 @@ func (h *Host) UpdateCode(c *contract.Contract, id database.SerializedJSON) (con
 
 	// set code  without invoking init
 	h.db.SetContract(c)
+	h.monitor.Invalidate(c.ID)
 
 	publisher := h.Context().Value("publisher").(string)
 	l := len(c.Encode())
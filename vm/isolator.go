@@ -34,6 +34,16 @@ type Isolator struct {
 
 var staticMonitor = NewMonitor()
 
+// MaxActionDataLen is the max length of the data of an action run by the isolator
+var MaxActionDataLen = 64 * 1024
+
+func checkActionData(h *host.Host, action *tx.Action) error {
+	if h.Activated(common.Forks.ActionDataLimit) && len(action.Data) > MaxActionDataLen {
+		return host.ErrActionDataTooLarge
+	}
+	return nil
+}
+
 // TriggerBlockBaseMode start blockbase mode
 func (i *Isolator) TriggerBlockBaseMode() {
 	i.blockBaseMode = true
@@ -71,8 +81,8 @@ func (i *Isolator) PrepareTx(t *tx.Tx, limit time.Duration) error {
 		if err != nil {
 			return err
 		}
-		if i.h.GasPaid(t.Publisher)*t.GasRatio >= t.GasLimit {
-			return fmt.Errorf("gas limit should be larger, paid: %v, gas limit: %v, gas ratio: %v", i.h.GasPaid(t.Publisher), t.GasLimit, t.GasRatio)
+		if i.h.GasPaid(t.Publisher)*t.EffectiveGasRatio() >= t.GasLimit {
+			return fmt.Errorf("gas limit should be larger, paid: %v, gas limit: %v, gas ratio: %v", i.h.GasPaid(t.Publisher), t.GasLimit, t.EffectiveGasRatio())
 		}
 		gas := i.h.TotalGas(i.publisherID)
 		err = CheckTxGasLimitValid(t, gas, i.h.DB())
@@ -119,7 +129,11 @@ func (i *Isolator) runAction(action tx.Action) (cost contract.Cost, status *tx.S
 
 	var rtn []interface{}
 
-	rtn, cost, err = staticMonitor.Call(i.h, action.Contract, action.ActionName, action.Data)
+	if err = checkActionData(i.h, &action); err != nil {
+		cost = host.CommonErrorCost(1)
+	} else {
+		rtn, cost, err = staticMonitor.Call(i.h, action.Contract, action.ActionName, action.Data)
+	}
 
 	if err != nil {
 		actionDesc := action.String()
@@ -166,9 +180,9 @@ func (i *Isolator) delDelaytx(refTxHash, publisher, deferTxHash string) {
 // Run actions in tx
 func (i *Isolator) Run() (*tx.TxReceipt, error) { // nolint
 	startTime := time.Now()
-	vmGasLimit := i.t.GasLimit/i.t.GasRatio - i.h.GasPaid()
+	vmGasLimit := i.t.GasLimit/i.t.EffectiveGasRatio() - i.h.GasPaid()
 	if vmGasLimit <= 0 {
-		ilog.Fatalf("vmGasLimit < 0. It should not happen. %v / %v < %v", i.t.GasLimit, i.t.GasRatio, i.h.GasPaid())
+		ilog.Fatalf("vmGasLimit < 0. It should not happen. %v / %v < %v", i.t.GasLimit, i.t.EffectiveGasRatio(), i.h.GasPaid())
 	}
 	i.h.Context().GSet("gas_limit", vmGasLimit)
 	i.h.Context().GSet("receipts", make([]*tx.Receipt, 0))
@@ -226,7 +240,7 @@ func (i *Isolator) Run() (*tx.TxReceipt, error) { // nolint
 		actionCost.AddAssign(contract.NewCost(0, int64(len(ret)), 0))
 		if (status.Code == tx.ErrorRuntime && status.Message == "out of gas") ||
 			(vmGasLimit < actionCost.ToGas()) ||
-			(!i.genesisMode && !i.blockBaseMode && i.h.TotalGas(i.t.Publisher).Value/i.t.GasRatio < i.h.GasPaid()+vmGasLimit) {
+			(!i.genesisMode && !i.blockBaseMode && i.h.TotalGas(i.t.Publisher).Value/i.t.EffectiveGasRatio() < i.h.GasPaid()+vmGasLimit) {
 			ilog.Errorf("out of gas vmGasLimit %v actionCost %v totalGas %v gasPaid %v", vmGasLimit, actionCost.ToGas(), i.h.TotalGas(i.t.Publisher).ToString(), i.h.GasPaid())
 			status.Code = tx.ErrorRuntime
 			status.Message = "out of gas"
@@ -239,7 +253,23 @@ func (i *Isolator) Run() (*tx.TxReceipt, error) { // nolint
 			ret = ""
 		}
 
-		i.h.PayCost(actionCost, i.publisherID)
+		payer := i.publisherID
+		if !i.genesisMode && !i.blockBaseMode {
+			if cid, payment := staticMonitor.payment(i.h, action.Contract, action.ActionName); payment == contract.ContractPay {
+				if i.h.TotalGas(cid).Value/i.t.EffectiveGasRatio() < i.h.GasPaid(cid)+actionCost.ToGas() {
+					status.Code = tx.ErrorBalanceNotEnough
+					status.Message = "contract gas not enough"
+					ret = ""
+				} else {
+					payer = cid
+				}
+			}
+		}
+		i.h.PayCost(actionCost, payer)
+		if i.h.Activated(common.Forks.ContractGasReceipt) {
+			// same as GasUsage, which DoPay charges at the effective gas ratio
+			i.tr.AddContractGas(action.Contract, actionCost.ToGas()*i.t.EffectiveGasRatio())
+		}
 
 		if status.Code != tx.Success {
 			if !(status.Code == tx.ErrorTimeout && i.limit < common.MaxTxTimeLimit) {
@@ -269,10 +299,10 @@ func (i *Isolator) Run() (*tx.TxReceipt, error) { // nolint
 
 // PayCost as name
 func (i *Isolator) PayCost() (*tx.TxReceipt, error) {
-	if i.t.GasLimit < i.h.GasPaid()*i.t.GasRatio {
-		ilog.Fatalf("total gas cost is above limit %v < %v * %v", i.t.GasLimit, i.h.GasPaid(), i.t.GasRatio)
+	if i.t.GasLimit < i.h.GasPaid()*i.t.EffectiveGasRatio() {
+		ilog.Fatalf("total gas cost is above limit %v < %v * %v", i.t.GasLimit, i.h.GasPaid(), i.t.EffectiveGasRatio())
 	}
-	paidGas, err := i.h.DoPay(i.h.Context().Value("witness").(string), i.t.GasRatio)
+	paidGas, err := i.h.DoPay(i.h.Context().Value("witness").(string), i.t.EffectiveGasRatio())
 	if err != nil {
 		ilog.Errorf("DoPay failed, rollback %v", err)
 		i.h.DB().Rollback()
@@ -281,12 +311,15 @@ func (i *Isolator) PayCost() (*tx.TxReceipt, error) {
 		i.tr.RAMUsage = make(map[string]int64)
 		i.tr.Status.Code = tx.ErrorBalanceNotEnough
 		i.tr.Status.Message = "balance not enough after executing actions: " + err.Error()
-		paidGas, err = i.h.DoPay(i.h.Context().Value("witness").(string), i.t.GasRatio)
+		paidGas, err = i.h.DoPay(i.h.Context().Value("witness").(string), i.t.EffectiveGasRatio())
 		if err != nil {
 			return nil, err
 		}
 	}
 	i.tr.GasUsage = paidGas.Value
+	if tip := i.t.Tip(); tip > 0 {
+		i.tr.Receipts = append(i.tr.Receipts, tx.NewGasTipReceipt(i.t.GasRatio, tip))
+	}
 	for k, v := range i.h.Costs() {
 		if v.Data != 0 {
 			i.tr.RAMUsage[k] = v.Data
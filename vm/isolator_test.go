@@ -0,0 +1,36 @@
+package vm
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/iost-official/go-iost/common"
+	"github.com/iost-official/go-iost/core/tx"
+	"github.com/iost-official/go-iost/vm/host"
+)
+
+func TestCheckActionData(t *testing.T) {
+	ctx := host.NewContext(nil)
+	ctx.Set("number", int64(10))
+	h := host.NewHost(ctx, nil, nil, nil)
+	action := &tx.Action{Contract: "token.iost", ActionName: "transfer"}
+
+	action.Data = strings.Repeat("a", MaxActionDataLen+1)
+	if err := checkActionData(h, action); err != nil {
+		t.Fatalf("data over the limit before the fork got %v", err)
+	}
+
+	defer func(f common.ForkConfig) { common.Forks = f }(common.Forks)
+	height := int64(10)
+	common.Forks.ActionDataLimit = &height
+
+	action.Data = strings.Repeat("a", MaxActionDataLen)
+	if err := checkActionData(h, action); err != nil {
+		t.Fatalf("data at the limit got %v", err)
+	}
+
+	action.Data += "a"
+	if err := checkActionData(h, action); err != host.ErrActionDataTooLarge {
+		t.Fatalf("data over the limit expect %v, got %v", host.ErrActionDataTooLarge, err)
+	}
+}
@@ -23,13 +23,15 @@ import (
 
 // Monitor ...
 type Monitor struct {
-	vms map[string]VM
+	vms       map[string]VM
+	contracts *contractCache
 }
 
 // NewMonitor ...
 func NewMonitor() *Monitor {
 	m := &Monitor{
-		vms: make(map[string]VM),
+		vms:       make(map[string]VM),
+		contracts: newContractCache(contractCacheSize),
 	}
 	jsvm := Factory("javascript")
 	m.vms["javascript"] = jsvm
@@ -47,7 +49,7 @@ func (m *Monitor) prepareContract(h *host.Host, contractName, api, jarg string)
 		cid = contractName
 	}
 
-	c = h.DB().Contract(cid)
+	c = m.contracts.get(h, cid)
 	if c == nil {
 		return nil, nil, nil, fmt.Errorf("contract %s not found", cid)
 	}
@@ -63,6 +65,21 @@ func (m *Monitor) prepareContract(h *host.Host, contractName, api, jarg string)
 	return
 }
 
+// payment returns the contract id and the payment mode of the abi, SelfPay if the abi isn't found.
+func (m *Monitor) payment(h *host.Host, contractName, api string) (string, contract.PaymentCode) {
+	cid := contractName
+	if h.IsDomain(contractName) {
+		if id := h.ContractID(contractName); id != "" {
+			cid = id
+		}
+	}
+	c := m.contracts.get(h, cid)
+	if c == nil {
+		return cid, contract.SelfPay
+	}
+	return cid, contract.PaymentCode(c.ABI(api).GetPayment())
+}
+
 func checkLimit(amountLimit map[string]*common.Fixed, token string, amount *common.Fixed) bool {
 	if amount.Value > 0 {
 		if limit, ok := amountLimit[token]; ok {
@@ -102,20 +119,37 @@ func getAmountLimitMap(h *host.Host, amountList []*contract.Amount) (map[string]
 // Call ...
 // nolint
 func (m *Monitor) Call(h *host.Host, contractName, api string, jarg string) (rtn []interface{}, cost contract.Cost, err error) {
+	if ct := tracerOf(h); ct != nil {
+		frame := ct.enter(contractName, api, jarg)
+		defer func() {
+			ct.exit(frame, cost, err)
+		}()
+	}
 	c, abi, args, err := m.prepareContract(h, contractName, api, jarg)
 	if err != nil {
 		return nil, host.Costs["GetCost"], fmt.Errorf("prepare contract: %v", err)
 	}
+	paused, cost := h.ContractPaused(c.ID)
+	if !h.Activated(common.Forks.ContractPausedCost) {
+		cost = contract.Cost0()
+	}
+	if paused {
+		cost.AddAssign(host.CommonErrorCost(1))
+		return nil, cost, host.ErrContractPaused
+	}
 
 	h.PushCtx()
 	defer func() {
 		h.PopCtx()
 	}()
-	cost = contract.Cost0()
 
 	stackHeight := h.Context().Value("stack_height").(int)
-	if stackHeight > 5 {
-		return nil, cost, fmt.Errorf("stack height exceed. actual %v", stackHeight)
+	if stackHeight > host.MaxCallDepth {
+		if !h.Activated(common.Forks.CallDepthLimit) {
+			return nil, cost, fmt.Errorf("stack height exceed. actual %v", stackHeight)
+		}
+		cost.AddAssign(host.CallDepthExceededCost())
+		return nil, cost, host.ErrCallDepthExceeded
 	}
 
 	h.Context().Set("contract_name", c.ID)
@@ -240,6 +274,16 @@ func (m *Monitor) Call(h *host.Host, contractName, api string, jarg string) (rtn
 	return
 }
 
+// Invalidate drops the cached contract, called when its code is set
+func (m *Monitor) Invalidate(contractID string) {
+	m.contracts.remove(contractID)
+}
+
+// CacheStats returns the hits and misses of the contract cache
+func (m *Monitor) CacheStats() (hits, misses int) {
+	return m.contracts.stats()
+}
+
 // Compile ...
 func (m *Monitor) Compile(con *contract.Contract) (string, error) {
 	switch con.Info.Lang {
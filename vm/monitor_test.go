@@ -1,11 +1,15 @@
 package vm
 
 import (
+	"errors"
+	"strconv"
+	"strings"
 	"testing"
 
 	"time"
 
 	. "github.com/golang/mock/gomock"
+	"github.com/iost-official/go-iost/common"
 	"github.com/iost-official/go-iost/core/contract"
 	"github.com/iost-official/go-iost/vm/database"
 	"github.com/iost-official/go-iost/vm/host"
@@ -22,6 +26,16 @@ func Init(t *testing.T) (*Monitor, *MockVM, *database.MockIMultiValue, *database
 	return pm, vm, db, vi
 }
 
+// contractGetter returns the contract for its key and nothing for the other keys
+func contractGetter(c *contract.Contract) func(table string, key string) (string, error) {
+	return func(table string, key string) (string, error) {
+		if strings.HasPrefix(key, database.ContractPrefix) {
+			return c.Encode(), nil
+		}
+		return "", nil
+	}
+}
+
 func TestMonitor_Call(t *testing.T) {
 	monitor, vm, db, vi := Init(t)
 
@@ -53,9 +67,7 @@ func TestMonitor_Call(t *testing.T) {
 		},
 	}
 
-	db.EXPECT().Get(Any(), Any()).DoAndReturn(func(table string, key string) (string, error) {
-		return c.Encode(), nil
-	})
+	db.EXPECT().Get(Any(), Any()).AnyTimes().DoAndReturn(contractGetter(&c))
 
 	monitor.Call(h, "Contract", "abi", "[\"1\"]")
 
@@ -64,6 +76,48 @@ func TestMonitor_Call(t *testing.T) {
 	}
 }
 
+func TestMonitor_ContractPausedCost(t *testing.T) {
+	monitor, vm, db, vi := Init(t)
+
+	ctx := host.NewContext(nil)
+	ctx.Set("gas_ratio", int64(100))
+	ctx.Set("stack_height", 1)
+
+	h := host.NewHost(ctx, vi, monitor, nil)
+
+	vm.EXPECT().LoadAndCall(Any(), Any(), Any(), Any()).AnyTimes().DoAndReturn(func(h *host.Host, c *contract.Contract, api string, args ...interface{}) (rtn []interface{}, cost contract.Cost, err error) {
+		return nil, contract.Cost0(), nil
+	})
+
+	c := contract.Contract{
+		ID:   "Contract",
+		Code: "codes",
+		Info: &contract.Info{
+			Lang:    "",
+			Version: "1.0.0",
+			Abi:     []*contract.ABI{{Name: "abi", Args: []string{"string"}}},
+		},
+	}
+	db.EXPECT().Get(Any(), Any()).AnyTimes().DoAndReturn(contractGetter(&c))
+
+	_, before, err := monitor.Call(h, "Contract", "abi", "[\"1\"]")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func(f common.ForkConfig) { common.Forks = f }(common.Forks)
+	height := int64(0)
+	common.Forks.ContractPausedCost = &height
+	_, after, err := monitor.Call(h, "Contract", "abi", "[\"1\"]")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, pausedCost := h.ContractPaused("Contract")
+	if after.ToGas() != before.ToGas()+pausedCost.ToGas() || pausedCost.ToGas() == 0 {
+		t.Fatalf("expect the paused flag read to be charged after the fork, before %v, after %v", before, after)
+	}
+}
+
 func TestMonitor_Context(t *testing.T) {
 	monitor, vm, db, vi := Init(t)
 	ctx := host.NewContext(nil)
@@ -105,9 +159,7 @@ func TestMonitor_Context(t *testing.T) {
 		},
 	}
 
-	db.EXPECT().Get(Any(), Any()).DoAndReturn(func(table string, key string) (string, error) {
-		return c.Encode(), nil
-	})
+	db.EXPECT().Get(Any(), Any()).AnyTimes().DoAndReturn(contractGetter(&c))
 
 	monitor.Call(h, "Contract", "outer", "[1]")
 
@@ -166,9 +218,7 @@ func TestMonitor_HostCall(t *testing.T) {
 		},
 	}
 
-	db.EXPECT().Get(Any(), Any()).DoAndReturn(func(table string, key string) (string, error) {
-		return c.Encode(), nil
-	})
+	db.EXPECT().Get(Any(), Any()).AnyTimes().DoAndReturn(contractGetter(&c))
 
 	monitor.Call(h, "Contract", "outer", "[1]")
 
@@ -215,9 +265,7 @@ module.exports = Contract;
 		},
 	}
 
-	db.EXPECT().Get(Any(), Any()).DoAndReturn(func(table string, key string) (string, error) {
-		return c.Encode(), nil
-	})
+	db.EXPECT().Get(Any(), Any()).AnyTimes().DoAndReturn(contractGetter(&c))
 
 	rs, co, e := monitor.Call(h, "Contract", "hello", `[]`)
 	if rs[0] != "world" {
@@ -225,3 +273,239 @@ module.exports = Contract;
 	}
 
 }
+
+func TestMonitor_CallDepth(t *testing.T) {
+	monitor, vm, db, vi := Init(t)
+	staticMonitor = monitor
+
+	ctx := host.NewContext(nil)
+	ctx.Set("gas_ratio", int64(100))
+	ctx.Set("stack_height", 1)
+	ctx.Set("stack0", "test")
+
+	h := host.NewHost(ctx, vi, monitor, nil)
+
+	abis := make([]*contract.ABI, 0)
+	for i := 0; i <= host.MaxCallDepth; i++ {
+		abis = append(abis, &contract.ABI{Name: "abi" + strconv.Itoa(i), Args: []string{"number"}})
+	}
+	c := contract.Contract{
+		ID:   "Contract",
+		Code: "codes",
+		Info: &contract.Info{
+			Lang:    "",
+			Version: "1.0.0",
+			Abi:     abis,
+		},
+	}
+
+	maxDepth := 0
+	vm.EXPECT().LoadAndCall(Any(), Any(), Any(), Any()).AnyTimes().DoAndReturn(func(h *host.Host, c *contract.Contract, api string, args ...interface{}) (rtn []interface{}, cost contract.Cost, err error) {
+		depth := h.Context().Value("stack_height").(int)
+		if depth > maxDepth {
+			maxDepth = depth
+		}
+		next, _ := strconv.Atoi(api[len("abi"):])
+		return h.Call("Contract", "abi"+strconv.Itoa(next+1), "[1]")
+	})
+
+	db.EXPECT().Get(Any(), Any()).AnyTimes().DoAndReturn(contractGetter(&c))
+
+	_, _, err := monitor.Call(h, "Contract", "abi0", "[1]")
+	if err == nil || err == host.ErrCallDepthExceeded {
+		t.Fatalf("expect stack height exceed before the fork, got %v", err)
+	}
+
+	defer func(f common.ForkConfig) { common.Forks = f }(common.Forks)
+	height := int64(0)
+	common.Forks.CallDepthLimit = &height
+	maxDepth = 0
+	_, cost, err := monitor.Call(h, "Contract", "abi0", "[1]")
+	if err != host.ErrCallDepthExceeded {
+		t.Fatalf("expect %v, got %v", host.ErrCallDepthExceeded, err)
+	}
+	if cost.ToGas() < host.CallDepthExceededCost().ToGas() {
+		t.Fatalf("call depth exceeded should charge cost, got %v", cost)
+	}
+	if maxDepth != host.MaxCallDepth {
+		t.Fatalf("expect max depth %v, got %v", host.MaxCallDepth, maxDepth)
+	}
+}
+
+func TestMonitor_ContractCache(t *testing.T) {
+	monitor, vm, db, vi := Init(t)
+
+	ctx := host.NewContext(nil)
+	ctx.Set("gas_ratio", int64(100))
+	ctx.Set("stack_height", 1)
+
+	h := host.NewHost(ctx, vi, monitor, nil)
+
+	c := contract.Contract{
+		ID:   "Contract",
+		Code: "codes",
+		Info: &contract.Info{
+			Lang:    "",
+			Version: "1.0.0",
+			Abi: []*contract.ABI{
+				{Name: "abi", Args: []string{"number"}},
+			},
+		},
+	}
+	db.EXPECT().Get(Any(), Any()).AnyTimes().DoAndReturn(contractGetter(&c))
+	vm.EXPECT().LoadAndCall(Any(), Any(), Any(), Any()).AnyTimes().Return([]interface{}{}, contract.Cost0(), nil)
+
+	for i := 0; i < 2; i++ {
+		if _, _, err := monitor.Call(h, "Contract", "abi", "[1]"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if hits, misses := monitor.CacheStats(); hits != 1 || misses != 1 {
+		t.Fatalf("expect 1 hit and 1 miss, got %v hits and %v misses", hits, misses)
+	}
+
+	monitor.Invalidate("Contract")
+	if _, _, err := monitor.Call(h, "Contract", "abi", "[1]"); err != nil {
+		t.Fatal(err)
+	}
+	if hits, misses := monitor.CacheStats(); hits != 1 || misses != 2 {
+		t.Fatalf("expect 1 hit and 2 misses after invalidation, got %v hits and %v misses", hits, misses)
+	}
+}
+
+func TestMonitor_ContractCacheForks(t *testing.T) {
+	mc := NewController(t)
+	defer mc.Finish()
+	vm := NewMockVM(mc)
+	monitor := NewMonitor()
+	monitor.vms[""] = vm
+
+	newContract := func(code string) *contract.Contract {
+		return &contract.Contract{
+			ID:   "Contract",
+			Code: code,
+			Info: &contract.Info{
+				Lang:    "",
+				Version: "1.0.0",
+				Abi:     []*contract.ABI{{Name: "abi", Args: []string{"number"}}},
+			},
+		}
+	}
+	// the two hosts share the monitor, like the states of two forks, or the state of a
+	// rolled back tx that updated the code and the state it is rolled back to
+	newHost := func(c *contract.Contract) *host.Host {
+		db := database.NewMockIMultiValue(mc)
+		db.EXPECT().Get(Any(), Any()).AnyTimes().DoAndReturn(contractGetter(c))
+		ctx := host.NewContext(nil)
+		ctx.Set("gas_ratio", int64(100))
+		ctx.Set("stack_height", 1)
+		return host.NewHost(ctx, database.NewVisitor(100, db), monitor, nil)
+	}
+	h1 := newHost(newContract("old codes"))
+	h2 := newHost(newContract("new codes"))
+
+	var code string
+	vm.EXPECT().LoadAndCall(Any(), Any(), Any(), Any()).AnyTimes().DoAndReturn(func(h *host.Host, c *contract.Contract, api string, args ...interface{}) ([]interface{}, contract.Cost, error) {
+		code = c.Code
+		return []interface{}{}, contract.Cost0(), nil
+	})
+
+	for _, tc := range []struct {
+		h    *host.Host
+		code string
+	}{{h1, "old codes"}, {h2, "new codes"}, {h1, "old codes"}} {
+		if _, _, err := monitor.Call(tc.h, "Contract", "abi", "[1]"); err != nil {
+			t.Fatal(err)
+		}
+		if code != tc.code {
+			t.Fatalf("expect %q, got %q", tc.code, code)
+		}
+	}
+}
+
+func TestMonitor_CallTraced(t *testing.T) {
+	monitor, vm, db, vi := Init(t)
+	ctx := host.NewContext(nil)
+	ctx.Set("gas_ratio", int64(100))
+	ctx.Set("stack_height", 1)
+
+	h := host.NewHost(ctx, vi, monitor, nil)
+
+	vm.EXPECT().LoadAndCall(Any(), Any(), "outer", Any()).AnyTimes().DoAndReturn(func(h *host.Host, c *contract.Contract, api string, args ...interface{}) (rtn []interface{}, cost contract.Cost, err error) {
+		if _, _, err := monitor.Call(h, "Contract", "ok", `["hello"]`); err != nil {
+			return nil, cost, err
+		}
+		_, _, err = monitor.Call(h, "Contract", "inner", `["world"]`)
+		return nil, cost, err
+	})
+	vm.EXPECT().LoadAndCall(Any(), Any(), "ok", Any()).AnyTimes().Return([]interface{}{"ok"}, contract.Cost0(), nil)
+	vm.EXPECT().LoadAndCall(Any(), Any(), "inner", Any()).AnyTimes().Return(nil, contract.Cost0(), errors.New("inner failed"))
+
+	c := contract.Contract{
+		ID:   "Contract",
+		Code: "codes",
+		Info: &contract.Info{
+			Lang:    "",
+			Version: "1.0.0",
+			Abi: []*contract.ABI{
+				{Name: "outer", Args: []string{"number"}},
+				{Name: "ok", Args: []string{"string"}},
+				{Name: "inner", Args: []string{"string"}},
+			},
+		},
+	}
+	db.EXPECT().Get(Any(), Any()).AnyTimes().DoAndReturn(contractGetter(&c))
+
+	_, _, trace, err := monitor.CallTraced(h, "Contract", "outer", "[1]")
+	if err == nil || trace != nil {
+		t.Fatalf("trace should be nil when disabled, err: %v, trace: %v", err, trace)
+	}
+
+	CallTraceEnabled = true
+	defer func() { CallTraceEnabled = false }()
+	_, _, trace, err = monitor.CallTraced(h, "Contract", "outer", "[1]")
+	if err == nil {
+		t.Fatal("call should fail")
+	}
+	if trace == nil || trace.ABI != "outer" || trace.Error != err.Error() || len(trace.Calls) != 2 {
+		t.Fatalf("unexpected trace %+v", trace)
+	}
+	if trace.Calls[0].ABI != "ok" || trace.Calls[0].Error != "" || trace.Calls[0].Args != `["hello"]` {
+		t.Fatalf("unexpected trace of the succeeded call %+v", trace.Calls[0])
+	}
+	failed := trace.FailedFrame()
+	if failed != trace.Calls[1] || failed.ABI != "inner" || failed.Error != "inner failed" || failed.Args != `["world"]` {
+		t.Fatalf("unexpected failed frame %+v", failed)
+	}
+	if tracerOf(h) != nil {
+		t.Fatal("tracer should be removed after the call")
+	}
+}
+
+func BenchmarkContractCache_Get(b *testing.B) {
+	mc := NewController(b)
+	defer mc.Finish()
+	db := database.NewMockIMultiValue(mc)
+	vi := database.NewVisitor(100, db)
+	monitor := NewMonitor()
+	h := host.NewHost(host.NewContext(nil), vi, monitor, nil)
+
+	c := contract.Contract{
+		ID:   "Contract",
+		Code: strings.Repeat("a", 48*1024),
+		Info: &contract.Info{
+			Lang:    "javascript",
+			Version: "1.0.0",
+			Abi:     []*contract.ABI{{Name: "abi", Args: []string{"number"}}},
+		},
+	}
+	raw := c.Encode()
+	db.EXPECT().Get(Any(), Any()).AnyTimes().Return(raw, nil)
+	if monitor.contracts.get(h, c.ID) == nil {
+		b.Fatal("contract not found")
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		monitor.contracts.get(h, c.ID)
+	}
+}
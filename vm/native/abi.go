@@ -46,6 +46,15 @@ func DomainABI() *contract.Contract {
 	return SystemContractABI("domain.iost", "1.0.0")
 }
 
+// SystemABINames returns the names of the system.iost abis in sorted order
+func SystemABINames() []string {
+	aset, err := getABISetByVersion("system.iost", "1.0.0")
+	if err != nil {
+		return nil
+	}
+	return aset.Names()
+}
+
 // SystemContractABI return system contract abi
 func SystemContractABI(conID, version string) *contract.Contract {
 	aset, err := getABISetByVersion(conID, version)
@@ -99,7 +108,8 @@ func ABI(id string, abiSet *abiSet, version string) *contract.Contract {
 		},
 	}
 
-	for _, v := range abiSet.PublicAbis() {
+	for _, name := range abiSet.Names() {
+		v, _ := abiSet.Get(name)
 		c.Info.Abi = append(c.Info.Abi, &contract.ABI{
 			Name: v.name,
 			Args: v.args,
@@ -2,6 +2,8 @@ package native
 
 import (
 	"fmt"
+	"sort"
+
 	"github.com/iost-official/go-iost/core/contract"
 	"github.com/iost-official/go-iost/ilog"
 	"github.com/iost-official/go-iost/vm/host"
@@ -61,6 +63,18 @@ func (as *abiSet) PublicAbis() map[string]*abi {
 	return abis
 }
 
+// Names returns the names of the public abis in the abiSet in sorted order
+func (as *abiSet) Names() []string {
+	names := make([]string, 0, len(as.abi))
+	for name := range as.abi {
+		if _, ok := as.privateMap[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
 // Impl .
 type Impl struct {
 }
@@ -1,27 +1,50 @@
 package native
 
 import (
+	"encoding/binary"
 	"errors"
+	"fmt"
+	"strconv"
+	"time"
 
 	"encoding/json"
 
 	"github.com/bitly/go-simplejson"
+	"github.com/iost-official/go-iost/common"
 	"github.com/iost-official/go-iost/core/contract"
+	"github.com/iost-official/go-iost/vm/database"
 	"github.com/iost-official/go-iost/vm/host"
 )
 
 var systemABIs *abiSet
 
+// MaxReceiptContentLen is the max length of a receipt content written by the receipt ABI
+var MaxReceiptContentLen = 4096
+
+// deploy rate limit of setCode: a publisher can deploy at most MaxDeploysPerWindow contracts in one DeployRateWindow, 0 means no limit
+var (
+	MaxDeploysPerWindow int64 = 50
+	DeployRateWindow          = time.Hour
+)
+
+// MaxStorageKeysLimit is the max page size of storageKeys
+var MaxStorageKeysLimit int64 = 100
+
 func init() {
 	systemABIs = newAbiSet()
 	systemABIs.Register(requireAuth)
 	systemABIs.Register(receipt)
 	systemABIs.Register(setCode)
 	systemABIs.Register(updateCode)
+	systemABIs.Register(forceUpdateCode)
 	systemABIs.Register(initSetCode)
 	systemABIs.Register(cancelDelaytx)
 	systemABIs.Register(hostSettings)
 	systemABIs.Register(updateNativeCode)
+	systemABIs.Register(random)
+	systemABIs.Register(pauseContract)
+	systemABIs.Register(unpauseContract)
+	systemABIs.Register(storageKeys)
 }
 
 // var .
@@ -42,7 +65,11 @@ var (
 		name: "receipt",
 		args: []string{"string"},
 		do: func(h *host.Host, args ...interface{}) (rtn []interface{}, cost contract.Cost, err error) {
-			cost = h.Receipt(args[0].(string))
+			content := args[0].(string)
+			if h.Activated(common.Forks.ReceiptContentLimit) && len(content) > MaxReceiptContentLen {
+				return nil, host.CommonErrorCost(1), host.ErrReceiptTooLarge
+			}
+			cost = h.Receipt(content)
 			return []interface{}{}, cost, nil
 		},
 	}
@@ -86,6 +113,15 @@ var (
 
 			publisher := h.Context().Value("publisher").(string)
 
+			if h.Activated(common.Forks.DeployRateLimit) {
+				cost.AddAssign(host.CommonOpCost(1))
+				cost0, err := checkDeployRate(h, publisher)
+				cost.AddAssign(cost0)
+				if err != nil {
+					return nil, cost, err
+				}
+			}
+
 			cost.AddAssign(host.SetCodeCost(len(con.Code)))
 			if !CheckCost(h, cost) {
 				return nil, cost, host.ErrOutOfGas
@@ -107,36 +143,18 @@ var (
 		name: "updateCode",
 		args: []string{"string", "string"},
 		do: func(h *host.Host, args ...interface{}) (rtn []interface{}, cost contract.Cost, err error) {
-			cost = contract.Cost0()
-			con := &contract.Contract{}
-			codeRaw := args[0].(string)
-
-			cost.AddAssign(host.CommonOpCost(1))
-			stackHeight := h.Context().Value("stack_height").(int)
-			if stackHeight != 1 {
-				return nil, cost, errors.New("can't call UpdateCode from other contract")
-			}
-
-			if codeRaw[0] == '{' {
-				err = json.Unmarshal([]byte(codeRaw), con)
-				if err != nil {
-					return nil, host.CommonErrorCost(1), err
-				}
-			} else {
-				err = con.B64Decode(codeRaw)
-				if err != nil {
-					return nil, host.CommonErrorCost(1), err
-				}
-			}
-
-			cost.AddAssign(host.SetCodeCost(len(con.Code)))
-			if !CheckCost(h, cost) {
-				return nil, cost, host.ErrOutOfGas
+			return doUpdateCode(h, args[0].(string), args[1].(string), false)
+		},
+	}
+	// forceUpdateCode is the same as updateCode, but skips the abi compatibility check
+	forceUpdateCode = &abi{
+		name: "forceUpdateCode",
+		args: []string{"string", "string"},
+		do: func(h *host.Host, args ...interface{}) (rtn []interface{}, cost contract.Cost, err error) {
+			if !h.Activated(common.Forks.UpdateCodeCompatibility) {
+				return nil, host.CommonErrorCost(1), host.ErrNotActivated
 			}
-
-			cost1, err := h.UpdateCode(con, []byte(args[1].(string)))
-			cost.AddAssign(cost1)
-			return []interface{}{}, cost, err
+			return doUpdateCode(h, args[0].(string), args[1].(string), true)
 		},
 	}
 
@@ -211,7 +229,7 @@ var (
 				}
 			}
 
-			cost0, err = h.UpdateCode(con, []byte(""))
+			cost0, err = h.UpdateCode(con, []byte(""), true)
 			cost.AddAssign(cost0)
 			return []interface{}{}, cost, err
 		},
@@ -256,4 +274,160 @@ var (
 			return nil, cost, nil
 		},
 	}
+	// random returns a number derived from the parent block hash, the block number and a nonce.
+	// Every node computes the same value, so it is not unpredictable to the block producer.
+	random = &abi{
+		name: "random",
+		args: []string{"string"},
+		do: func(h *host.Host, args ...interface{}) (rtn []interface{}, cost contract.Cost, err error) {
+			cost = host.Costs["ContextCost"]
+			cost.AddAssign(host.CommonOpCost(1))
+			parentHash := h.Context().Value("parent_hash").(string)
+			number := h.Context().Value("number").(int64)
+			return []interface{}{randomOf(parentHash, number, args[0].(string))}, cost, nil
+		},
+	}
+	// pauseContract stops the contract from being called until it is unpaused, without destroying it.
+	pauseContract = &abi{
+		name: "pauseContract",
+		args: []string{"string"},
+		do: func(h *host.Host, args ...interface{}) (rtn []interface{}, cost contract.Cost, err error) {
+			return setContractPaused(h, args[0].(string), true)
+		},
+	}
+	unpauseContract = &abi{
+		name: "unpauseContract",
+		args: []string{"string"},
+		do: func(h *host.Host, args ...interface{}) (rtn []interface{}, cost contract.Cost, err error) {
+			return setContractPaused(h, args[0].(string), false)
+		},
+	}
+	// storageKeys returns a page of the fields stored under a map key of the current contract, and the cursor of the next page, which is 0 at the end
+	storageKeys = &abi{
+		name: "storageKeys",
+		args: []string{"string", "number", "number"},
+		do: func(h *host.Host, args ...interface{}) (rtn []interface{}, cost contract.Cost, err error) {
+			key := args[0].(string)
+			cursor := args[1].(int64)
+			limit := args[2].(int64)
+			if cursor < 0 || limit <= 0 || limit > MaxStorageKeysLimit {
+				return nil, host.CommonErrorCost(1), fmt.Errorf("invalid cursor or limit, limit should be in [1, %v]", MaxStorageKeysLimit)
+			}
+
+			// all the fields of a map are stored in one record, charge for every field read
+			keys, cost := h.MapKeys(key)
+			cost.AddAssign(host.CommonOpCost(len(keys)))
+			page := []string{}
+			var next int64
+			if cursor < int64(len(keys)) {
+				end := cursor + limit
+				if end < int64(len(keys)) {
+					next = end
+				} else {
+					end = int64(len(keys))
+				}
+				page = keys[cursor:end]
+			}
+
+			pageJSON, err := json.Marshal(page)
+			if err != nil {
+				return nil, cost, err
+			}
+			return []interface{}{string(pageJSON), next}, cost, nil
+		},
+	}
 )
+
+// setContractPaused sets the pause flag of a contract, which requires the auth of the contract owner.
+func setContractPaused(h *host.Host, id string, paused bool) (rtn []interface{}, cost contract.Cost, err error) {
+	owner, cost := h.MapGet("contract_owner", id)
+	ownerStr, ok := owner.(string)
+	if !ok || ownerStr == "" {
+		return nil, cost, host.ErrContractNotFound
+	}
+	ok, cost0 := h.RequireAuth(ownerStr, "active")
+	cost.AddAssign(cost0)
+	if !ok {
+		return nil, cost, host.ErrPermissionLost
+	}
+	if paused {
+		cost0, err = h.MapPut("contract_paused", id, true, ownerStr)
+	} else {
+		cost0, err = h.MapDel("contract_paused", id)
+	}
+	cost.AddAssign(cost0)
+	return []interface{}{}, cost, err
+}
+
+// deployRate is the number of deploys of a publisher in a DeployRateWindow.
+type deployRate struct {
+	Window int64 `json:"window"`
+	Count  int64 `json:"count"`
+}
+
+// checkDeployRate counts a deploy of publisher in the current window, and fails if the window is already full.
+// The count is kept in one map field per publisher, whose RAM is paid by the publisher.
+func checkDeployRate(h *host.Host, publisher string) (contract.Cost, error) {
+	if MaxDeploysPerWindow <= 0 || DeployRateWindow <= 0 {
+		return contract.Cost0(), nil
+	}
+	now, _ := h.Context().Value("time").(int64)
+	window := now / int64(DeployRateWindow)
+
+	rate := deployRate{Window: window}
+	v, cost := h.MapGet("deploy_rate", publisher)
+	if j, ok := v.(database.SerializedJSON); ok {
+		var old deployRate
+		if err := json.Unmarshal(j, &old); err == nil && old.Window == window {
+			rate.Count = old.Count
+		}
+	}
+	if rate.Count >= MaxDeploysPerWindow {
+		return cost, host.ErrDeployRateExceeded
+	}
+	rate.Count++
+	b, err := json.Marshal(rate)
+	if err != nil {
+		return cost, err
+	}
+	cost0, err := h.MapPut("deploy_rate", publisher, database.SerializedJSON(b), publisher)
+	cost.AddAssign(cost0)
+	return cost, err
+}
+
+func randomOf(parentHash string, number int64, nonce string) string {
+	seed := fmt.Sprintf("%v-%v-%v", parentHash, number, nonce)
+	return strconv.FormatUint(binary.BigEndian.Uint64(common.Sha3([]byte(seed))), 10)
+}
+
+func doUpdateCode(h *host.Host, codeRaw, id string, force bool) (rtn []interface{}, cost contract.Cost, err error) {
+	cost = contract.Cost0()
+	con := &contract.Contract{}
+
+	cost.AddAssign(host.CommonOpCost(1))
+	stackHeight := h.Context().Value("stack_height").(int)
+	if stackHeight != 1 {
+		return nil, cost, errors.New("can't call UpdateCode from other contract")
+	}
+
+	if codeRaw[0] == '{' {
+		err = json.Unmarshal([]byte(codeRaw), con)
+		if err != nil {
+			return nil, host.CommonErrorCost(1), err
+		}
+	} else {
+		err = con.B64Decode(codeRaw)
+		if err != nil {
+			return nil, host.CommonErrorCost(1), err
+		}
+	}
+
+	cost.AddAssign(host.SetCodeCost(len(con.Code)))
+	if !CheckCost(h, cost) {
+		return nil, cost, host.ErrOutOfGas
+	}
+
+	cost1, err := h.UpdateCode(con, []byte(id), force)
+	cost.AddAssign(cost1)
+	return []interface{}{}, cost, err
+}
@@ -21,6 +21,7 @@ const (
 	TokenInfoMapPrefix            = "TI"
 	TokenBalanceMapPrefix         = "TB"
 	TokenFreezeMapPrefix          = "TF"
+	TokenAllowanceMapPrefix       = "TA"
 	IssuerMapField                = "issuer"
 	SupplyMapField                = "supply"
 	TotalSupplyMapField           = "totalSupply"
@@ -38,10 +39,25 @@ func init() {
 	tokenABIs.Register(issueTokenABI)
 	tokenABIs.Register(transferTokenABI)
 	tokenABIs.Register(transferFreezeTokenABI)
+	tokenABIs.Register(freezeTokenABI)
 	tokenABIs.Register(balanceOfTokenABI)
 	tokenABIs.Register(supplyTokenABI)
 	tokenABIs.Register(totalSupplyTokenABI)
 	tokenABIs.Register(destroyTokenABI)
+	tokenABIs.Register(tokenInfoTokenABI)
+	tokenABIs.Register(approveTokenABI)
+	tokenABIs.Register(allowanceTokenABI)
+	tokenABIs.Register(transferFromTokenABI)
+}
+
+// maxTokenDecimal is the max decimal of a token
+const maxTokenDecimal = 18
+
+// TokenInfo is the metadata of a token returned by the tokenInfo ABI
+type TokenInfo struct {
+	Symbol      string `json:"symbol"`
+	Decimals    int64  `json:"decimals"`
+	TotalSupply string `json:"totalSupply"`
 }
 
 func checkTokenExists(h *host.Host, tokenSym string) (ok bool, cost contract.Cost) {
@@ -64,6 +80,34 @@ func setBalance(h *host.Host, tokenSym string, from string, balance int64, ramPa
 	return cost
 }
 
+// allowanceField is the field of the amount of the token the spender may transfer from the owner,
+// in the allowance map of the owner.
+func allowanceField(tokenSym, spender string) string {
+	return tokenSym + "/" + spender
+}
+
+func getAllowance(h *host.Host, tokenSym, owner, spender string) (int64, contract.Cost) {
+	allowance, cost := h.MapGet(TokenAllowanceMapPrefix+owner, allowanceField(tokenSym, spender))
+	if allowance == nil {
+		return 0, cost
+	}
+	return allowance.(int64), cost
+}
+
+// setAllowance sets the allowance paid by the owner, a zero allowance is deleted.
+func setAllowance(h *host.Host, tokenSym, owner, spender string, allowance int64) (contract.Cost, error) {
+	if allowance == 0 {
+		ok, cost := h.MapHas(TokenAllowanceMapPrefix+owner, allowanceField(tokenSym, spender))
+		if !ok {
+			return cost, nil
+		}
+		cost0, err := h.MapDel(TokenAllowanceMapPrefix+owner, allowanceField(tokenSym, spender))
+		cost.AddAssign(cost0)
+		return cost, err
+	}
+	return h.MapPut(TokenAllowanceMapPrefix+owner, allowanceField(tokenSym, spender), allowance, owner)
+}
+
 func getBalance(h *host.Host, tokenSym string, from string, ramPayer string) (balance int64, cost contract.Cost, err error) {
 	balance = int64(0)
 	cost = contract.Cost0()
@@ -285,7 +329,7 @@ var (
 			}
 
 			// check valid
-			if decimal < 0 || decimal >= 19 {
+			if decimal < 0 || decimal > maxTokenDecimal {
 				return nil, cost, errors.New("invalid decimal")
 			}
 			if totalSupply > math.MaxInt64/int64(math.Pow10(decimal)) {
@@ -414,7 +458,7 @@ var (
 		args: []string{"string", "string", "string", "string", "string"},
 		do: func(h *host.Host, args ...interface{}) (rtn []interface{}, cost contract.Cost, err error) {
 			cost = contract.Cost0()
-			cost.AddAssign(host.CommonOpCost(1))
+			cost.AddAssign(host.TransferCost())
 			tokenSym := args[0].(string)
 			from := args[1].(string)
 			to := args[2].(string)
@@ -535,7 +579,7 @@ var (
 		args: []string{"string", "string", "string", "string", "number", "string"},
 		do: func(h *host.Host, args ...interface{}) (rtn []interface{}, cost contract.Cost, err error) {
 			cost = contract.Cost0()
-			cost.AddAssign(host.CommonOpCost(1))
+			cost.AddAssign(host.TransferCost())
 			tokenSym := args[0].(string)
 			from := args[1].(string)
 			to := args[2].(string)
@@ -637,6 +681,88 @@ var (
 		},
 	}
 
+	freezeTokenABI = &abi{
+		name: "freeze",
+		args: []string{"string", "string", "string", "number"},
+		do: func(h *host.Host, args ...interface{}) (rtn []interface{}, cost contract.Cost, err error) {
+			cost = contract.Cost0()
+			cost.AddAssign(host.CommonOpCost(1))
+			tokenSym := args[0].(string)
+			account := args[1].(string)
+			amountStr := args[2].(string)
+			ftime := args[3].(int64)
+
+			// get token info
+			ok, cost0 := checkTokenExists(h, tokenSym)
+			cost.AddAssign(cost0)
+			if !ok {
+				return nil, cost, host.ErrTokenNotExists
+			}
+
+			// check auth
+			ok, cost0 = h.RequireAuth(account, TransferPermission)
+			cost.AddAssign(cost0)
+			if !ok {
+				return nil, cost, host.ErrPermissionLost
+			}
+			if !CheckCost(h, cost) {
+				return nil, cost, host.ErrOutOfGas
+			}
+
+			// the balance is unfrozen once the block time reaches ftime
+			ntime, cost0 := h.BlockTime()
+			cost.AddAssign(cost0)
+			if ftime <= ntime {
+				return nil, cost, fmt.Errorf("invalid freeze time %v, block time is %v", ftime, ntime)
+			}
+
+			// get amount by fixed point number
+			amount, cost0, err := parseAmount(h, tokenSym, amountStr)
+			cost.AddAssign(cost0)
+			if err != nil {
+				return nil, cost, err
+			}
+			if amount <= 0 {
+				return nil, cost, host.ErrInvalidAmount
+			}
+			if !CheckCost(h, cost) {
+				return nil, cost, host.ErrOutOfGas
+			}
+
+			publisher := h.Context().Value("publisher").(string)
+			balance, cost0, err := getBalance(h, tokenSym, account, publisher)
+			cost.AddAssign(cost0)
+			if err != nil {
+				return nil, cost, err
+			}
+			if balance < amount {
+				return nil, cost, host.ErrBalanceNotEnough
+			}
+
+			cost0 = setBalance(h, tokenSym, account, balance-amount, publisher)
+			cost.AddAssign(cost0)
+			if !CheckCost(h, cost) {
+				return nil, cost, host.ErrOutOfGas
+			}
+
+			cost0, err = freezeBalance(h, tokenSym, account, amount, ftime, publisher)
+			cost.AddAssign(cost0)
+			if err != nil {
+				return nil, cost, err
+			}
+
+			// generate receipt
+			message, err := json.Marshal(args)
+			cost.AddAssign(host.CommonOpCost(1))
+			if err != nil {
+				return nil, cost, err
+			}
+			cost0 = h.Receipt(string(message))
+			cost.AddAssign(cost0)
+			return []interface{}{}, cost, nil
+		},
+	}
+
 	destroyTokenABI = &abi{
 		name: "destroy",
 		args: []string{"string", "string", "string"},
@@ -808,4 +934,243 @@ var (
 			return []interface{}{totalSupplyStr}, cost, nil
 		},
 	}
+
+	tokenInfoTokenABI = &abi{
+		name: "tokenInfo",
+		args: []string{"string"},
+		do: func(h *host.Host, args ...interface{}) (rtn []interface{}, cost contract.Cost, err error) {
+			cost = contract.Cost0()
+			cost.AddAssign(host.CommonOpCost(1))
+			tokenSym := args[0].(string)
+
+			// check token info
+			ok, cost0 := checkTokenExists(h, tokenSym)
+			cost.AddAssign(cost0)
+			if !ok {
+				return nil, cost, host.ErrTokenNotExists
+			}
+
+			decimal, cost0 := h.MapGet(TokenInfoMapPrefix+tokenSym, DecimalMapField)
+			cost.AddAssign(cost0)
+			totalSupply, cost0 := h.MapGet(TokenInfoMapPrefix+tokenSym, TotalSupplyMapField)
+			cost.AddAssign(cost0)
+			totalSupplyStr, cost0 := genAmount(h, tokenSym, totalSupply.(int64))
+			cost.AddAssign(cost0)
+
+			info, err := json.Marshal(TokenInfo{
+				Symbol:      tokenSym,
+				Decimals:    decimal.(int64),
+				TotalSupply: totalSupplyStr,
+			})
+			cost.AddAssign(host.CommonOpCost(1))
+			if err != nil {
+				return nil, cost, err
+			}
+			return []interface{}{string(info)}, cost, nil
+		},
+	}
+
+	approveTokenABI = &abi{
+		name: "approve",
+		args: []string{"string", "string", "string", "string"},
+		do: func(h *host.Host, args ...interface{}) (rtn []interface{}, cost contract.Cost, err error) {
+			cost = contract.Cost0()
+			cost.AddAssign(host.CommonOpCost(1))
+			tokenSym := args[0].(string)
+			owner := args[1].(string)
+			spender := args[2].(string)
+			amountStr := args[3].(string)
+
+			if !h.IsValidAccount(spender) {
+				return nil, cost, fmt.Errorf("invalid account %v", spender)
+			}
+			if owner == spender {
+				return nil, cost, fmt.Errorf("owner can't approve itself")
+			}
+
+			// get token info
+			ok, cost0 := checkTokenExists(h, tokenSym)
+			cost.AddAssign(cost0)
+			if !ok {
+				return nil, cost, host.ErrTokenNotExists
+			}
+
+			// check auth
+			ok, cost0 = h.RequireAuth(owner, TransferPermission)
+			cost.AddAssign(cost0)
+			if !ok {
+				return nil, cost, host.ErrPermissionLost
+			}
+			if !CheckCost(h, cost) {
+				return nil, cost, host.ErrOutOfGas
+			}
+
+			// the allowance is replaced rather than added, 0 revokes it
+			amount, cost0, err := parseAmount(h, tokenSym, amountStr)
+			cost.AddAssign(cost0)
+			if err != nil {
+				return nil, cost, err
+			}
+			if amount < 0 {
+				return nil, cost, host.ErrInvalidAmount
+			}
+			cost0, err = setAllowance(h, tokenSym, owner, spender, amount)
+			cost.AddAssign(cost0)
+			if err != nil {
+				return nil, cost, err
+			}
+
+			// generate receipt
+			message, err := json.Marshal(args)
+			cost.AddAssign(host.CommonOpCost(1))
+			if err != nil {
+				return nil, cost, err
+			}
+			cost0 = h.Receipt(string(message))
+			cost.AddAssign(cost0)
+			return []interface{}{}, cost, nil
+		},
+	}
+
+	allowanceTokenABI = &abi{
+		name: "allowance",
+		args: []string{"string", "string", "string"},
+		do: func(h *host.Host, args ...interface{}) (rtn []interface{}, cost contract.Cost, err error) {
+			cost = contract.Cost0()
+			cost.AddAssign(host.CommonOpCost(1))
+			tokenSym := args[0].(string)
+			owner := args[1].(string)
+			spender := args[2].(string)
+
+			// check token info
+			ok, cost0 := checkTokenExists(h, tokenSym)
+			cost.AddAssign(cost0)
+			if !ok {
+				return nil, cost, host.ErrTokenNotExists
+			}
+
+			allowance, cost0 := getAllowance(h, tokenSym, owner, spender)
+			cost.AddAssign(cost0)
+			allowanceStr, cost0 := genAmount(h, tokenSym, allowance)
+			cost.AddAssign(cost0)
+			return []interface{}{allowanceStr}, cost, nil
+		},
+	}
+
+	transferFromTokenABI = &abi{
+		name: "transferFrom",
+		args: []string{"string", "string", "string", "string", "string"},
+		do: func(h *host.Host, args ...interface{}) (rtn []interface{}, cost contract.Cost, err error) {
+			cost = contract.Cost0()
+			cost.AddAssign(host.TransferCost())
+			tokenSym := args[0].(string)
+			spender := args[1].(string)
+			from := args[2].(string)
+			to := args[3].(string)
+			amountStr := args[4].(string)
+
+			if !h.IsValidAccount(from) {
+				return nil, cost, fmt.Errorf("invalid account %v", from)
+			}
+			if !h.IsValidAccount(to) {
+				return nil, cost, fmt.Errorf("invalid account %v", to)
+			}
+
+			// get token info
+			ok, cost0 := checkTokenExists(h, tokenSym)
+			cost.AddAssign(cost0)
+			if !ok {
+				return nil, cost, host.ErrTokenNotExists
+			}
+			canTransfer, cost0 := h.MapGet(TokenInfoMapPrefix+tokenSym, CanTransferMapField)
+			cost.AddAssign(cost0)
+			if !(canTransfer.(bool)) {
+				return nil, cost, host.ErrTokenNoTransfer
+			}
+			onlyIssuerCanTransfer, cost0 := h.MapGet(TokenInfoMapPrefix+tokenSym, OnlyIssuerCanTransferMapField)
+			cost.AddAssign(cost0)
+			if onlyIssuerCanTransfer.(bool) {
+				issuer, cost0 := h.MapGet(TokenInfoMapPrefix+tokenSym, IssuerMapField)
+				cost.AddAssign(cost0)
+				ok, cost0 = h.RequireAuth(issuer.(string), TransferPermission)
+				cost.AddAssign(cost0)
+				if !ok {
+					return nil, cost, fmt.Errorf("transfer need issuer permission")
+				}
+			}
+			if !CheckCost(h, cost) {
+				return nil, cost, host.ErrOutOfGas
+			}
+
+			// the spender transfers with its own auth, within the allowance of the owner
+			ok, cost0 = h.RequireAuth(spender, TransferPermission)
+			cost.AddAssign(cost0)
+			if !ok {
+				return nil, cost, host.ErrPermissionLost
+			}
+			if !CheckCost(h, cost) {
+				return nil, cost, host.ErrOutOfGas
+			}
+
+			// get amount by fixed point number
+			amount, cost0, err := parseAmount(h, tokenSym, amountStr)
+			cost.AddAssign(cost0)
+			if err != nil {
+				return nil, cost, err
+			}
+			if amount <= 0 {
+				return nil, cost, host.ErrInvalidAmount
+			}
+			allowance, cost0 := getAllowance(h, tokenSym, from, spender)
+			cost.AddAssign(cost0)
+			if allowance < amount {
+				allowanceStr, cost0 := genAmount(h, tokenSym, allowance)
+				cost.AddAssign(cost0)
+				return nil, cost, fmt.Errorf("allowance not enough %v < %v", allowanceStr, amountStr)
+			}
+			if !CheckCost(h, cost) {
+				return nil, cost, host.ErrOutOfGas
+			}
+
+			publisher := h.Context().Value("publisher").(string)
+			// set balance
+			fbalance, cost0, err := getBalance(h, tokenSym, from, publisher)
+			cost.AddAssign(cost0)
+			if err != nil {
+				return nil, cost, err
+			}
+			if fbalance < amount {
+				return nil, cost, host.ErrBalanceNotEnough
+			}
+			cost0, err = setAllowance(h, tokenSym, from, spender, allowance-amount)
+			cost.AddAssign(cost0)
+			if err != nil {
+				return nil, cost, err
+			}
+			if from != to {
+				tbalance, cost0, err := getBalance(h, tokenSym, to, publisher)
+				cost.AddAssign(cost0)
+				if err != nil {
+					return nil, cost, err
+				}
+				cost0 = setBalance(h, tokenSym, to, tbalance+amount, publisher)
+				cost.AddAssign(cost0)
+				cost0 = setBalance(h, tokenSym, from, fbalance-amount, publisher)
+				cost.AddAssign(cost0)
+			}
+			if !CheckCost(h, cost) {
+				return nil, cost, host.ErrOutOfGas
+			}
+
+			// generate receipt
+			message, err := json.Marshal(args)
+			cost.AddAssign(host.CommonOpCost(1))
+			if err != nil {
+				return nil, cost, err
+			}
+			cost0 = h.Receipt(string(message))
+			cost.AddAssign(cost0)
+			return []interface{}{}, cost, nil
+		},
+	}
 )
@@ -0,0 +1,82 @@
+package vm
+
+import (
+	"github.com/iost-official/go-iost/core/contract"
+	"github.com/iost-official/go-iost/vm/host"
+)
+
+// CallTraceEnabled enables Monitor.CallTraced, it is a debug flag and off by default,
+// so that the calls in production aren't traced.
+var CallTraceEnabled = false
+
+const callTraceKey = "call_trace"
+
+// CallTrace is a frame of a traced contract call, with the sub-calls made by it.
+type CallTrace struct {
+	Contract string        `json:"contract"`
+	ABI      string        `json:"abi"`
+	Args     string        `json:"args"`
+	Cost     contract.Cost `json:"cost"`
+	Error    string        `json:"error,omitempty"`
+	Calls    []*CallTrace  `json:"calls,omitempty"`
+}
+
+// FailedFrame returns the deepest failed frame of the trace, nil if the call succeeded.
+func (t *CallTrace) FailedFrame() *CallTrace {
+	if t == nil || t.Error == "" {
+		return nil
+	}
+	for _, c := range t.Calls {
+		if f := c.FailedFrame(); f != nil {
+			return f
+		}
+	}
+	return t
+}
+
+// callTracer records the frames of the calls in progress.
+type callTracer struct {
+	root  *CallTrace
+	stack []*CallTrace
+}
+
+func (ct *callTracer) enter(contractName, api, jarg string) *CallTrace {
+	frame := &CallTrace{Contract: contractName, ABI: api, Args: jarg}
+	if len(ct.stack) == 0 {
+		ct.root = frame
+	} else {
+		parent := ct.stack[len(ct.stack)-1]
+		parent.Calls = append(parent.Calls, frame)
+	}
+	ct.stack = append(ct.stack, frame)
+	return frame
+}
+
+func (ct *callTracer) exit(frame *CallTrace, cost contract.Cost, err error) {
+	frame.Cost = cost
+	if err != nil {
+		frame.Error = err.Error()
+	}
+	ct.stack = ct.stack[:len(ct.stack)-1]
+}
+
+func tracerOf(h *host.Host) *callTracer {
+	ct, _ := h.Context().GValue(callTraceKey).(*callTracer)
+	return ct
+}
+
+// CallTraced calls the contract like Call, and returns the trace of the call and its sub-calls
+// if CallTraceEnabled is set, otherwise the trace is nil.
+func (m *Monitor) CallTraced(h *host.Host, contractName, api string, jarg string) (rtn []interface{}, cost contract.Cost, trace *CallTrace, err error) {
+	if !CallTraceEnabled {
+		rtn, cost, err = m.Call(h, contractName, api, jarg)
+		return
+	}
+	ct := &callTracer{}
+	old := h.Context().GValue(callTraceKey)
+	h.Context().GSet(callTraceKey, ct)
+	defer h.Context().GSet(callTraceKey, old)
+
+	rtn, cost, err = m.Call(h, contractName, api, jarg)
+	return rtn, cost, ct.root, err
+}